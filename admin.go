@@ -0,0 +1,185 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Admin 提供日志运行时管理的 HTTP 接口，挂载时需要去掉路径前缀:
+//
+//	admin := &slogplus.Admin{Format: formatVar}
+//	http.Handle("/debug/log/", http.StripPrefix("/debug/log", admin))
+//
+// 支持的接口:
+//
+//	GET /format      返回当前输出格式
+//	PUT /format      切换输出格式，请求体为格式名称（text、console、json 等）
+//	GET /capture     返回抓取状态
+//	POST /capture    开始抓取，参数 duration（如 30s）和 records，写入文件并返回路径；
+//	                 带 stream=1 参数时直接以流的形式返回抓取内容，直到抓取结束
+//	DELETE /capture  立即结束抓取
+//	GET /stats       以 JSON 返回统计信息，包括配额用量（quota）和管道统计（pipeline）
+type Admin struct {
+	// Format 可切换的输出格式，为 nil 时不提供 /format 接口
+	Format *FormatVar
+
+	// Capture 突发抓取，为 nil 时不提供 /capture 接口
+	Capture *Capture
+
+	// Quota 日志配额，设置后 /stats 返回各属性值的用量
+	Quota *Quota
+
+	// Stats 管道统计，设置后 /stats 返回当前窗口的统计信息
+	Stats *PipelineStats
+
+	once sync.Once
+	mux  *http.ServeMux
+}
+
+// ServeHTTP 实现 http.Handler
+func (a *Admin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.once.Do(a.init)
+	a.mux.ServeHTTP(w, r)
+}
+
+func (a *Admin) init() {
+	a.mux = http.NewServeMux()
+	if a.Format != nil {
+		a.mux.HandleFunc("GET /format", a.getFormat)
+		a.mux.HandleFunc("PUT /format", a.setFormat)
+		a.mux.HandleFunc("POST /format", a.setFormat)
+	}
+	if a.Capture != nil {
+		a.mux.HandleFunc("GET /capture", a.captureStatus)
+		a.mux.HandleFunc("POST /capture", a.startCapture)
+		a.mux.HandleFunc("DELETE /capture", a.stopCapture)
+	}
+	a.mux.HandleFunc("GET /stats", a.stats)
+}
+
+// adminStats 是 /stats 接口的响应
+type adminStats struct {
+	Quota    []QuotaUsage      `json:"quota,omitempty"`
+	Pipeline *PipelineSnapshot `json:"pipeline,omitempty"`
+}
+
+func (a *Admin) stats(w http.ResponseWriter, _ *http.Request) {
+	var s adminStats
+	if a.Quota != nil {
+		s.Quota = a.Quota.Usage()
+	}
+	if a.Stats != nil {
+		snap := a.Stats.Snapshot()
+		s.Pipeline = &snap
+	}
+	writeJSON(w, http.StatusOK, s)
+}
+
+func (a *Admin) getFormat(w http.ResponseWriter, _ *http.Request) {
+	io.WriteString(w, a.Format.Format().String()+"\n")
+}
+
+func (a *Admin) setFormat(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 64))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f, err := ParseFormat(strings.TrimSpace(string(body)))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	a.Format.Set(f)
+	io.WriteString(w, f.String()+"\n")
+}
+
+func (a *Admin) captureStatus(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, a.Capture.Status())
+}
+
+func (a *Admin) startCapture(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var d time.Duration
+	if s := q.Get("duration"); s != "" {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	var records int
+	if s := q.Get("records"); s != "" {
+		var err error
+		if records, err = strconv.Atoi(s); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if q.Get("stream") == "" {
+		if _, err := a.Capture.Start(d, records); err != nil {
+			http.Error(w, err.Error(), captureErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, a.Capture.Status())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fw := &flushWriter{w: w, rc: http.NewResponseController(w)}
+	done, err := a.Capture.StartWriter(fw, d, records)
+	if err != nil {
+		http.Error(w, err.Error(), captureErrorStatus(err))
+		return
+	}
+	fw.rc.Flush()
+	select {
+	case <-done:
+	case <-r.Context().Done():
+		a.Capture.Stop()
+	}
+}
+
+func (a *Admin) stopCapture(w http.ResponseWriter, _ *http.Request) {
+	if err := a.Capture.Stop(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, a.Capture.Status())
+}
+
+// captureErrorStatus 返回抓取错误对应的状态码
+func captureErrorStatus(err error) int {
+	if errors.Is(err, ErrCaptureActive) {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+// writeJSON 以 JSON 格式返回 v
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// flushWriter 每次写入后立即刷新 HTTP 响应
+type flushWriter struct {
+	w  io.Writer
+	rc *http.ResponseController
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	f.rc.Flush()
+	return n, err
+}
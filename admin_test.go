@@ -0,0 +1,77 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAdmin_Format(t *testing.T) {
+	fv := NewFormatVar(FormatText)
+	admin := &Admin{Format: fv}
+
+	rec := httptest.NewRecorder()
+	admin.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/format", strings.NewReader("json")))
+	if rec.Code != http.StatusOK || fv.Format() != FormatJSON {
+		t.Fatalf("应该切换为 JSON 格式: %d %s", rec.Code, fv.Format())
+	}
+
+	rec = httptest.NewRecorder()
+	admin.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/format", nil))
+	if strings.TrimSpace(rec.Body.String()) != "json" {
+		t.Errorf("应该返回当前格式: %q", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	admin.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/format", strings.NewReader("xml")))
+	if rec.Code != http.StatusBadRequest || fv.Format() != FormatJSON {
+		t.Errorf("未知格式应该返回 400 且不修改格式: %d", rec.Code)
+	}
+}
+
+func TestAdmin_Capture(t *testing.T) {
+	c := &Capture{Dir: t.TempDir()}
+	admin := &Admin{Capture: c}
+
+	rec := httptest.NewRecorder()
+	admin.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/capture?duration=1m", nil))
+	if rec.Code != http.StatusOK || !c.Active() {
+		t.Fatalf("应该开始抓取: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	admin.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/capture?duration=1m", nil))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("重复开始抓取应该返回 409: %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	admin.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/capture", nil))
+	if rec.Code != http.StatusOK || c.Active() {
+		t.Errorf("应该结束抓取: %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	admin.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/capture", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("未设置时长和记录数应该返回 400: %d", rec.Code)
+	}
+}
+
+func TestAdmin_Stats(t *testing.T) {
+	q := &Quota{Key: "tenant", MaxRecords: 1}
+	logger := NewLogger(io.Discard, &Options{Quota: q})
+	logger.Info("a", "tenant", "acme")
+	logger.Info("b", "tenant", "acme")
+
+	rec := httptest.NewRecorder()
+	(&Admin{Quota: q, Stats: &PipelineStats{}}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	want := `{"quota":[{"value":"acme","records":1,"bytes":`
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Body.String(), want) || !strings.Contains(rec.Body.String(), `"dropped_records":1`) || !strings.Contains(rec.Body.String(), `"pipeline":{`) {
+		t.Errorf("应该返回配额用量: %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,421 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AlertFormat 是告警 webhook 的请求体格式
+type AlertFormat string
+
+const (
+	// AlertSlack Slack incoming webhook，请求体为 {"text": "..."}
+	AlertSlack AlertFormat = "slack"
+
+	// AlertTeams Microsoft Teams incoming webhook，请求体为 MessageCard
+	AlertTeams AlertFormat = "teams"
+
+	// AlertJSON 通用 JSON，请求体为 {"fingerprint": ..., "suppressed": N, "record": {...}}，
+	// record 为 FormatJSON 编码的记录
+	AlertJSON AlertFormat = "json"
+)
+
+// AlertOptions 定义告警 webhook 的配置
+type AlertOptions struct {
+	// URL webhook 地址，必须设置
+	URL string
+
+	// Format 请求体格式，默认为 AlertJSON
+	Format AlertFormat
+
+	// Level 告警的最低级别，默认为 Error
+	Level slog.Leveler
+
+	// Interval 同一指纹的记录在该时间内最多告警一次，其余的计入下一次告警的 suppressed，默认 5 分钟
+	// 包含错误属性的记录使用 ErrorFingerprint，否则按级别和去掉易变部分的消息计算指纹
+	Interval time.Duration
+
+	// Options Slack 和 Teams 告警中记录的编码配置，默认为 &Options{Format: FormatText}
+	Options *Options
+
+	// Headers 额外的请求头
+	Headers map[string]string
+
+	// QueueSize 等待发送的最大告警数，队列满时丢弃新告警，默认 100
+	QueueSize int
+
+	// Timeout 单次请求的超时时间，默认 10 秒
+	Timeout time.Duration
+
+	// Retry 重试策略，为 nil 时使用默认策略
+	Retry *RetryPolicy
+
+	// Security TLS、认证和代理配置
+	Security *SinkSecurity
+
+	// OnError 发送失败（重试耗尽）时的回调
+	OnError func(err error)
+
+	// Stats 设置后报告发送队列深度和发送延迟，名称为 "alert"
+	Stats *PipelineStats
+}
+
+// AlertHandler 将所有记录交给被包装的 Handler，同时把 Error 及以上的记录发送到告警 webhook，
+// 同一指纹的告警按 Interval 限流，避免故障期间刷屏
+type AlertHandler struct {
+	next slog.Handler
+	h    *Handler
+	a    *alerter
+}
+
+// alerter 是所有派生 Handler 共享的限流状态和发送队列
+type alerter struct {
+	opts   AlertOptions
+	client *http.Client
+
+	mu   sync.Mutex
+	seen map[string]*alertState
+	now  func() time.Time
+
+	queue   chan []byte
+	flushCh chan chan error
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+
+	dropped    atomic.Int64
+	lastErr    error
+	unregister func()
+}
+
+// alertState 是一个指纹的限流状态
+type alertState struct {
+	last       time.Time
+	suppressed int64
+}
+
+// alertStateMax 限流状态的最大数量，超出后清理已过期的指纹
+const alertStateMax = 1024
+
+// ErrAlertQueueFull 表示告警发送队列已满，告警被丢弃
+var ErrAlertQueueFull = errors.New("slogplus: 告警发送队列已满")
+
+// NewAlertHandler 创建告警 Handler，next 为 nil 时只告警不输出，使用完毕后需要调用 Close
+func NewAlertHandler(next slog.Handler, opts *AlertOptions) (*AlertHandler, error) {
+	a := &alerter{seen: make(map[string]*alertState)}
+	if opts != nil {
+		a.opts = *opts
+	}
+	o := &a.opts
+	if o.URL == "" {
+		return nil, errors.New("slogplus: 必须设置告警 webhook 地址")
+	}
+	switch o.Format {
+	case "":
+		o.Format = AlertJSON
+	case AlertSlack, AlertTeams, AlertJSON:
+	default:
+		return nil, fmt.Errorf("slogplus: 未知的告警格式 %q", o.Format)
+	}
+	if o.Interval <= 0 {
+		o.Interval = 5 * time.Minute
+	}
+	if o.QueueSize <= 0 {
+		o.QueueSize = 100
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 10 * time.Second
+	}
+	if o.Retry == nil {
+		o.Retry = &RetryPolicy{}
+	}
+
+	client, err := o.Security.HTTPClient(o.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	a.client = client
+
+	hopts := Options{Format: FormatText}
+	if o.Options != nil {
+		hopts = *o.Options
+	}
+	if o.Format == AlertJSON {
+		hopts.Format, hopts.FormatVar = FormatJSON, nil
+	}
+
+	a.queue = make(chan []byte, o.QueueSize)
+	a.flushCh = make(chan chan error)
+	a.stop = make(chan struct{})
+	a.done = make(chan struct{})
+	a.unregister = RegisterFlusher(a)
+	go a.loop()
+
+	return &AlertHandler{next: next, h: New(nil, &hopts), a: a}, nil
+}
+
+// Enabled 实现 slog.Handler，被包装的 Handler 或告警任一需要时返回 true
+func (h *AlertHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.a.level() || h.next != nil && h.next.Enabled(ctx, level)
+}
+
+// Handle 将记录交给被包装的 Handler，达到告警级别且未被限流时放入发送队列，不会阻塞
+func (h *AlertHandler) Handle(ctx context.Context, r slog.Record) error {
+	var err error
+	if h.next != nil && h.next.Enabled(ctx, r.Level) {
+		err = h.next.Handle(ctx, r)
+	}
+	if r.Level < h.a.level() {
+		return err
+	}
+
+	r, ok := h.h.prepare(ctx, r)
+	if !ok {
+		return err
+	}
+	fp := alertFingerprint(r)
+	suppressed, ok := h.a.allow(fp)
+	if !ok {
+		return err
+	}
+
+	body := h.body(ctx, r, fp, suppressed)
+	select {
+	case h.a.queue <- body:
+		h.a.opts.Stats.QueueDepth("alert", len(h.a.queue))
+	default:
+		h.a.dropped.Add(1)
+		err = errors.Join(err, ErrAlertQueueFull)
+	}
+	return err
+}
+
+// level 返回告警的最低级别
+func (a *alerter) level() slog.Level {
+	if a.opts.Level != nil {
+		return a.opts.Level.Level()
+	}
+	return slog.LevelError
+}
+
+// allow 判断指纹 fp 是否可以告警，可以时返回上次告警后被抑制的记录数
+func (a *alerter) allow(fp string) (suppressed int64, ok bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.clock()
+	s := a.seen[fp]
+	if s != nil && now.Sub(s.last) < a.opts.Interval {
+		s.suppressed++
+		return 0, false
+	}
+	if s == nil {
+		if len(a.seen) >= alertStateMax {
+			for k, v := range a.seen {
+				if now.Sub(v.last) >= a.opts.Interval {
+					delete(a.seen, k)
+				}
+			}
+		}
+		s = &alertState{}
+		a.seen[fp] = s
+	}
+	suppressed = s.suppressed
+	s.last, s.suppressed = now, 0
+	return suppressed, true
+}
+
+func (a *alerter) clock() time.Time {
+	if a.now != nil {
+		return a.now()
+	}
+	return time.Now()
+}
+
+// alertFingerprint 计算记录的告警指纹
+func alertFingerprint(r slog.Record) string {
+	var err error
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Value.Kind() == slog.KindAny && !isNilValue(a.Value) {
+			if e, ok := a.Value.Any().(error); ok {
+				err = e
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return ErrorFingerprint(err, r.PC)
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(levelName(r.Level)))
+	h.Write([]byte{'\n'})
+	h.Write(sanitizeErrorMessage(r.Message))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// body 按 Format 生成请求体
+func (h *AlertHandler) body(ctx context.Context, r slog.Record, fp string, suppressed int64) []byte {
+	rec := bytes.TrimRight(h.h.appendRecord(nil, ctx, r), "\n")
+
+	var buf []byte
+	switch h.a.opts.Format {
+	case AlertSlack, AlertTeams:
+		text := string(rec)
+		if suppressed > 0 {
+			text += "\n（上次告警后另有 " + strconv.FormatInt(suppressed, 10) + " 条相同记录被抑制）"
+		}
+		if h.a.opts.Format == AlertSlack {
+			buf = append(buf, `{"text":`...)
+			buf = appendJSONString(buf, text)
+			return append(buf, '}')
+		}
+		buf = append(buf, `{"@type":"MessageCard","@context":"https://schema.org/extensions","themeColor":"D70000","summary":`...)
+		buf = appendJSONString(buf, r.Message)
+		buf = append(buf, `,"title":`...)
+		buf = appendJSONString(buf, levelName(r.Level)+" "+r.Message)
+		buf = append(buf, `,"text":`...)
+		buf = appendJSONString(buf, text)
+		return append(buf, '}')
+	default:
+		buf = append(buf, `{"fingerprint":`...)
+		buf = appendJSONString(buf, fp)
+		buf = append(buf, `,"suppressed":`...)
+		buf = strconv.AppendInt(buf, suppressed, 10)
+		buf = append(buf, `,"record":`...)
+		buf = append(buf, rec...)
+		return append(buf, '}')
+	}
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *AlertHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	nh := *h
+	if h.next != nil {
+		nh.next = h.next.WithAttrs(attrs)
+	}
+	nh.h = h.h.WithAttrs(attrs).(*Handler)
+	return &nh
+}
+
+// WithGroup 实现 slog.Handler
+func (h *AlertHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	nh := *h
+	if h.next != nil {
+		nh.next = h.next.WithGroup(name)
+	}
+	nh.h = h.h.WithGroup(name).(*Handler)
+	return &nh
+}
+
+// Flush 立即发送队列中的所有告警
+func (h *AlertHandler) Flush() error {
+	return h.a.Flush()
+}
+
+// Dropped 返回因队列已满而丢弃的告警数
+func (h *AlertHandler) Dropped() int64 {
+	return h.a.dropped.Load()
+}
+
+// Close 发送剩余告警并停止发送，不会关闭被包装的 Handler
+func (h *AlertHandler) Close() error {
+	var err error
+	h.a.once.Do(func() {
+		h.a.unregister()
+		close(h.a.stop)
+		<-h.a.done
+		err = h.a.lastErr
+	})
+	return err
+}
+
+// Flush 实现 Flusher
+func (a *alerter) Flush() error {
+	ch := make(chan error, 1)
+	select {
+	case a.flushCh <- ch:
+		return <-ch
+	case <-a.done:
+		return nil
+	}
+}
+
+func (a *alerter) loop() {
+	defer close(a.done)
+	send := func(body []byte) error {
+		start := time.Now()
+		err := a.send(body)
+		a.opts.Stats.Latency("alert", time.Since(start))
+		if err != nil && a.opts.OnError != nil {
+			a.opts.OnError(err)
+		}
+		return err
+	}
+	drain := func() error {
+		var errs []error
+		for {
+			select {
+			case body := <-a.queue:
+				errs = append(errs, send(body))
+			default:
+				return errors.Join(errs...)
+			}
+		}
+	}
+
+	for {
+		select {
+		case body := <-a.queue:
+			send(body)
+		case ch := <-a.flushCh:
+			ch <- drain()
+		case <-a.stop:
+			a.lastErr = drain()
+			return
+		}
+	}
+}
+
+// send 发送一个告警
+func (a *alerter) send(body []byte) error {
+	return a.opts.Retry.Do(context.Background(), func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.opts.URL, bytes.NewReader(body))
+		if err != nil {
+			return Permanent(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range a.opts.Headers {
+			req.Header.Set(k, v)
+		}
+		a.opts.Security.Authorize(req)
+
+		resp, err := a.client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return &StatusError{Code: resp.StatusCode, Status: resp.Status}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,117 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IAmMrChen/slogplus/sinktest"
+)
+
+func TestAlertHandler_RateLimit(t *testing.T) {
+	c := sinktest.NewHTTPCollector(t)
+	var out bytes.Buffer
+	h, err := NewAlertHandler(New(&out, nil), &AlertOptions{URL: c.URL, Interval: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	now := time.Date(2025, 11, 14, 14, 0, 0, 0, time.UTC)
+	h.a.now = func() time.Time { return now }
+
+	logger := slog.New(h).With("service", "api")
+	logger.Info("ok")
+	for i := 0; i < 3; i++ {
+		logger.Error("查询失败", "err", errors.New("timeout"), "attempt", i)
+	}
+	logger.Error("磁盘已满")
+	now = now.Add(time.Minute)
+	logger.Error("查询失败", "err", errors.New("timeout"))
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := strings.Count(out.String(), "\n"); n != 6 {
+		t.Errorf("所有记录都应该写入被包装的 Handler: %d", n)
+	}
+	batches := c.Batches()
+	if len(batches) != 3 {
+		t.Fatalf("同一指纹在间隔内只应该告警一次: %d", len(batches))
+	}
+
+	type alert struct {
+		Fingerprint string         `json:"fingerprint"`
+		Suppressed  int64          `json:"suppressed"`
+		Record      map[string]any `json:"record"`
+	}
+	var alerts []alert
+	for _, b := range batches {
+		var a alert
+		if err := json.Unmarshal(b.Body, &a); err != nil {
+			t.Fatalf("%v: %s", err, b.Body)
+		}
+		alerts = append(alerts, a)
+	}
+	if alerts[0].Record["msg"] != "查询失败" || alerts[0].Record["service"] != "api" || alerts[0].Suppressed != 0 {
+		t.Errorf("告警内容错误: %+v", alerts[0])
+	}
+	if alerts[1].Record["msg"] != "磁盘已满" || alerts[1].Fingerprint == alerts[0].Fingerprint {
+		t.Errorf("不同的记录应该单独告警: %+v", alerts[1])
+	}
+	if alerts[2].Fingerprint != alerts[0].Fingerprint || alerts[2].Suppressed != 2 {
+		t.Errorf("间隔后应该再次告警并带上抑制数: %+v", alerts[2])
+	}
+}
+
+func TestAlertHandler_Slack(t *testing.T) {
+	c := sinktest.NewHTTPCollector(t)
+	h, err := NewAlertHandler(nil, &AlertOptions{URL: c.URL, Format: AlertSlack})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slog.New(h).Warn("忽略")
+	slog.New(h).WithGroup("db").Error("连接池耗尽", "size", 10)
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	batches := c.Batches()
+	if len(batches) != 1 {
+		t.Fatalf("只应该发送 Error 记录: %d", len(batches))
+	}
+	var body struct{ Text string }
+	if err := json.Unmarshal(batches[0].Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body.Text, "ERROR") || !strings.Contains(body.Text, "msg=连接池耗尽 db.size=10") {
+		t.Errorf("Slack 消息错误: %q", body.Text)
+	}
+}
+
+func TestAlertHandler_Teams(t *testing.T) {
+	c := sinktest.NewHTTPCollector(t)
+	h, err := NewAlertHandler(nil, &AlertOptions{URL: c.URL, Format: AlertTeams})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slog.New(h).Error("支付失败")
+	h.Close()
+
+	var card map[string]any
+	if err := json.Unmarshal(c.Batches()[0].Body, &card); err != nil {
+		t.Fatal(err)
+	}
+	if card["@type"] != "MessageCard" || card["title"] != "ERROR 支付失败" {
+		t.Errorf("Teams 消息错误: %v", card)
+	}
+
+	if _, err := NewAlertHandler(nil, &AlertOptions{URL: c.URL, Format: "email"}); err == nil {
+		t.Error("未知格式应该返回错误")
+	}
+}
@@ -0,0 +1,117 @@
+package slogplus
+
+import (
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"strings"
+)
+
+// IPAnonymizer 将指定属性中的 IP 地址截断为网段，用于符合 GDPR 的访问日志
+// 例如 192.168.1.23 -> 192.168.1.0，2001:db8:1:2::1 -> 2001:db8:1::
+// 可以直接作为 Options.ReplaceAttr 或 Options.Redactor 使用
+type IPAnonymizer struct {
+	// Keys 需要处理的属性名（不区分大小写），默认为 ip、remote_addr、client_ip
+	Keys []string
+
+	// IPv4Bits IPv4 保留的前缀位数，默认 24
+	IPv4Bits int
+
+	// IPv6Bits IPv6 保留的前缀位数，默认 48
+	IPv6Bits int
+}
+
+// defaultIPKeys 默认需要匿名化的属性名
+var defaultIPKeys = []string{"ip", "remote_addr", "client_ip"}
+
+// ReplaceAttr 匿名化匹配属性中的 IP 地址，签名与 Options.ReplaceAttr 一致
+func (an *IPAnonymizer) ReplaceAttr(_ []string, a slog.Attr) slog.Attr {
+	if !an.match(a.Key) {
+		return a
+	}
+
+	var s string
+	switch a.Value.Kind() {
+	case slog.KindString:
+		s = a.Value.String()
+	case slog.KindAny:
+		switch v := a.Value.Any().(type) {
+		case netip.Addr:
+			s = v.String()
+		case fmt.Stringer: // net.IP 等类型
+			s = v.String()
+		default:
+			return a
+		}
+	default:
+		return a
+	}
+	return slog.String(a.Key, an.Anonymize(s))
+}
+
+// Redact 实现 Redactor，与 ReplaceAttr 相同
+func (an *IPAnonymizer) Redact(groups []string, a slog.Attr) slog.Attr {
+	return an.ReplaceAttr(groups, a)
+}
+
+// Anonymize 匿名化字符串中的 IP 地址
+// 支持 "ip"、"ip:port"、"[ipv6]:port" 以及逗号分隔的列表（如 X-Forwarded-For），
+// 无法解析的部分原样保留
+func (an *IPAnonymizer) Anonymize(s string) string {
+	if !strings.Contains(s, ",") {
+		return an.anonymizeOne(s)
+	}
+	parts := strings.Split(s, ",")
+	for i, p := range parts {
+		trimmed := strings.TrimSpace(p)
+		parts[i] = strings.Replace(p, trimmed, an.anonymizeOne(trimmed), 1)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (an *IPAnonymizer) anonymizeOne(s string) string {
+	if addr, err := netip.ParseAddr(s); err == nil {
+		return an.mask(addr).String()
+	}
+	if ap, err := netip.ParseAddrPort(s); err == nil {
+		return netip.AddrPortFrom(an.mask(ap.Addr()), ap.Port()).String()
+	}
+	return s
+}
+
+// mask 将地址截断到配置的前缀长度
+func (an *IPAnonymizer) mask(addr netip.Addr) netip.Addr {
+	addr = addr.WithZone("")
+	bits := an.IPv6Bits
+	if bits <= 0 {
+		bits = 48
+	}
+	if addr.Is4() || addr.Is4In6() {
+		addr = addr.Unmap()
+		bits = an.IPv4Bits
+		if bits <= 0 {
+			bits = 24
+		}
+	}
+	if bits > addr.BitLen() {
+		bits = addr.BitLen()
+	}
+	p, err := addr.Prefix(bits)
+	if err != nil {
+		return addr
+	}
+	return p.Addr()
+}
+
+func (an *IPAnonymizer) match(key string) bool {
+	keys := an.Keys
+	if len(keys) == 0 {
+		keys = defaultIPKeys
+	}
+	for _, k := range keys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
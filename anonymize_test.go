@@ -0,0 +1,53 @@
+package slogplus
+
+import (
+	"bytes"
+	"log/slog"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestIPAnonymizer_Anonymize(t *testing.T) {
+	an := &IPAnonymizer{}
+	tests := []struct {
+		in, want string
+	}{
+		{"192.168.1.23", "192.168.1.0"},
+		{"192.168.1.23:8080", "192.168.1.0:8080"},
+		{"2001:db8:1:2::1", "2001:db8:1::"},
+		{"[2001:db8:1:2::1]:443", "[2001:db8:1::]:443"},
+		{"::ffff:10.1.2.3", "10.1.2.0"},
+		{"10.0.0.1, 172.16.5.4", "10.0.0.0, 172.16.5.0"},
+		{"unknown", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := an.Anonymize(tt.in); got != tt.want {
+			t.Errorf("Anonymize(%q) = %q, 期望 %q", tt.in, got, tt.want)
+		}
+	}
+
+	an16 := &IPAnonymizer{IPv4Bits: 16}
+	if got := an16.Anonymize("192.168.1.23"); got != "192.168.0.0" {
+		t.Errorf("自定义前缀长度不正确: %s", got)
+	}
+}
+
+func TestIPAnonymizer_ReplaceAttr(t *testing.T) {
+	var buf bytes.Buffer
+	an := &IPAnonymizer{Keys: []string{"ip", "peer"}}
+	logger := NewLogger(&buf, &Options{ReplaceAttr: an.ReplaceAttr})
+
+	logger.Info("login",
+		"ip", "203.0.113.77",
+		slog.Any("peer", netip.MustParseAddr("198.51.100.9")),
+		"host", "203.0.113.77",
+	)
+
+	output := buf.String()
+	for _, want := range []string{"ip=203.0.113.0", "peer=198.51.100.0", "host=203.0.113.77"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("输出应该包含 %s: %s", want, output)
+		}
+	}
+}
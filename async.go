@@ -0,0 +1,349 @@
+package slogplus
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AsyncOptions 定义异步 Handler 的配置
+type AsyncOptions struct {
+	// QueueSize 等待写入的最大记录数，队列满时丢弃新记录，默认 1024
+	QueueSize int
+
+	// Workers 写入记录的后台 goroutine 数，默认 1
+	// 大于 1 时可以提高慢速输出的吞吐，但记录的输出顺序不再与调用顺序一致
+	Workers int
+
+	// MaxBatch 大于 1 时后台 goroutine 一次最多取出 MaxBatch 条等待中的记录，
+	// 被包装的是 slogplus Handler 时逐条编码后合并为一次写入，输出为网络连接时使用 writev，
+	// 高负载下可以大幅减少系统调用；输出必须是字节流，GELFWriter 等每次 Write 对应一条消息的输出不能开启
+	MaxBatch int
+
+	// OnDrop 队列已满、记录被丢弃时的回调，在调用日志的 goroutine 中执行，不能阻塞
+	OnDrop func(r slog.Record)
+
+	// OnError 被包装的 Handler 返回错误时的回调，在后台 goroutine 中执行
+	OnError func(err error)
+
+	// Stats 设置后报告队列深度，名称为 "async"
+	Stats *PipelineStats
+
+	// FlushContext 设置后在该 context 结束时等待队列中的记录全部写入，通常为服务的根 context，
+	// 使 SIGTERM 等关闭流程中排队的记录不会丢失
+	FlushContext context.Context
+}
+
+// AsyncHandler 将记录放入有界队列，由后台 goroutine 交给被包装的 Handler，
+// 写入变慢时日志调用也不会阻塞请求
+type AsyncHandler struct {
+	next slog.Handler
+	a    *asyncQueue
+}
+
+// asyncQueue 是所有派生 Handler 共享的队列和后台 goroutine
+type asyncQueue struct {
+	opts  AsyncOptions
+	queue chan asyncItem
+	free  chan *asyncRecord // 空闲的记录副本，后台 goroutine 写入后放回，入队时取出
+
+	mu     sync.RWMutex // 保护 closed，保证 Close 之后不再有记录入队
+	closed bool
+	fmu    sync.Mutex // 保证同一时间只有一组 Flush 屏障在队列中
+
+	wg         sync.WaitGroup
+	once       sync.Once
+	dropped    atomic.Int64
+	unregister func()
+	stopCtx    func()
+}
+
+// asyncItem 是队列中的一条记录，barrier 不为 nil 时表示 Flush 的屏障
+type asyncItem struct {
+	rec     *asyncRecord
+	barrier *sync.WaitGroup
+}
+
+// asyncRecord 是入队时复制的记录，写入后放回 asyncQueue.free 复用
+// slog.Handler 的约定要求保留记录时必须复制属性，复用 attrs 的容量使入队不分配内存
+type asyncRecord struct {
+	ctx   context.Context
+	h     slog.Handler
+	time  time.Time
+	level slog.Level
+	msg   string
+	pc    uintptr
+	attrs []slog.Attr
+	snap  ctxSnapshot // 入队时的全局属性和剩余时长
+}
+
+// maxPooledAttrs 超过该数量的属性切片不再复用，避免长期占用内存
+const maxPooledAttrs = 64
+
+// newRecord 取出一个空闲的 asyncRecord 并复制记录，没有空闲时新建
+func (a *asyncQueue) newRecord(ctx context.Context, h slog.Handler, r slog.Record) *asyncRecord {
+	var rec *asyncRecord
+	select {
+	case rec = <-a.free:
+	default:
+		rec = new(asyncRecord)
+	}
+	rec.ctx, rec.h = ctx, h
+	rec.time, rec.level, rec.msg, rec.pc = r.Time, r.Level, r.Message, r.PC
+	rec.snap = takeSnapshot(ctx)
+	r.Attrs(func(a slog.Attr) bool {
+		rec.attrs = append(rec.attrs, a)
+		return true
+	})
+	return rec
+}
+
+// freeRecord 清除引用后放回空闲列表，列表已满时丢弃
+func (a *asyncQueue) freeRecord(rec *asyncRecord) {
+	if cap(rec.attrs) > maxPooledAttrs {
+		return
+	}
+	clear(rec.attrs)
+	*rec = asyncRecord{attrs: rec.attrs[:0]}
+	select {
+	case a.free <- rec:
+	default:
+	}
+}
+
+// context 返回后台写入使用的 context，不传递取消信号，带有入队时的状态
+func (rec *asyncRecord) context() context.Context {
+	return context.WithValue(context.WithoutCancel(rec.ctx), ctxSnapshotKey{}, rec.snap)
+}
+
+// record 重建 slog.Record，属性超过 5 个时 slog 内部仍会分配一次，在后台 goroutine 中进行
+func (rec *asyncRecord) record() slog.Record {
+	r := slog.NewRecord(rec.time, rec.level, rec.msg, rec.pc)
+	r.AddAttrs(rec.attrs...)
+	return r
+}
+
+// ErrAsyncQueueFull 表示异步队列已满，记录被丢弃
+var ErrAsyncQueueFull = errors.New("slogplus: 异步日志队列已满")
+
+// Async 返回异步写入 h 的 Handler，使用完毕后需要调用 Close 写入队列中剩余的记录
+// Close 之后的记录直接同步写入 h
+//
+//	ah := slogplus.Async(slogplus.New(conn, nil), &slogplus.AsyncOptions{QueueSize: 4096})
+//	defer ah.Close()
+//	slog.SetDefault(slog.New(ah))
+//
+// 负数的 QueueSize、Workers 按默认值处理，需要得到错误时使用 AsyncE
+func Async(h slog.Handler, opts *AsyncOptions) *AsyncHandler {
+	a := &asyncQueue{}
+	if opts != nil {
+		a.opts = *opts
+	}
+	if a.opts.QueueSize <= 0 {
+		a.opts.QueueSize = 1024
+	}
+	if a.opts.Workers <= 0 {
+		a.opts.Workers = 1
+	}
+	a.queue = make(chan asyncItem, a.opts.QueueSize)
+	a.free = make(chan *asyncRecord, a.opts.QueueSize)
+	for i := 0; i < a.opts.Workers; i++ {
+		a.wg.Add(1)
+		go a.work()
+	}
+	ah := &AsyncHandler{next: h, a: a}
+	a.unregister = RegisterFlusher(FlusherFunc(ah.Flush))
+	a.stopCtx = func() {}
+	if a.opts.FlushContext != nil {
+		a.stopCtx = FlushOnDone(a.opts.FlushContext, FlusherFunc(ah.Flush))
+	}
+	return ah
+}
+
+// AsyncE 与 Async 相同，但配置无效时返回 AsyncOptions.Validate 的错误
+func AsyncE(h slog.Handler, opts *AsyncOptions) (*AsyncHandler, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return Async(h, opts), nil
+}
+
+// Enabled 实现 slog.Handler
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle 将记录放入队列，不会阻塞；队列已满时丢弃记录并返回 ErrAsyncQueueFull
+// context 的取消不会传递给后台写入，其中的值仍然可用；PushScope 的全局属性和 ctx_remaining 按入队时的状态输出
+func (h *AsyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.a.mu.RLock()
+	defer h.a.mu.RUnlock()
+	if h.a.closed {
+		return h.next.Handle(ctx, r)
+	}
+
+	rec := h.a.newRecord(ctx, h.next, r)
+	select {
+	case h.a.queue <- asyncItem{rec: rec}:
+		h.a.opts.Stats.QueueDepth("async", len(h.a.queue))
+		return nil
+	default:
+		h.a.freeRecord(rec)
+		h.a.dropped.Add(1)
+		if h.a.opts.OnDrop != nil {
+			h.a.opts.OnDrop(r)
+		}
+		return ErrAsyncQueueFull
+	}
+}
+
+// WithAttrs 实现 slog.Handler，派生的 Handler 共享同一个队列
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{next: h.next.WithAttrs(attrs), a: h.a}
+}
+
+// WithGroup 实现 slog.Handler，派生的 Handler 共享同一个队列
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &AsyncHandler{next: h.next.WithGroup(name), a: h.a}
+}
+
+// Dropped 返回因队列已满而丢弃的记录数
+func (h *AsyncHandler) Dropped() int64 {
+	return h.a.dropped.Load()
+}
+
+// Flush 等待调用之前入队的记录全部写入，并刷新被包装的 Handler
+func (h *AsyncHandler) Flush() error {
+	h.a.fmu.Lock()
+	h.a.mu.RLock()
+	if !h.a.closed {
+		// 每个后台 goroutine 各取到一个屏障后才能继续，此时之前入队的记录都已写入
+		var barrier sync.WaitGroup
+		barrier.Add(h.a.opts.Workers)
+		for i := 0; i < h.a.opts.Workers; i++ {
+			h.a.queue <- asyncItem{barrier: &barrier}
+		}
+		barrier.Wait()
+	}
+	h.a.mu.RUnlock()
+	h.a.fmu.Unlock()
+
+	if f, ok := h.next.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close 写入队列中剩余的记录并停止后台 goroutine，不会关闭被包装的 Handler
+func (h *AsyncHandler) Close() error {
+	h.a.once.Do(func() {
+		h.a.unregister()
+		h.a.stopCtx()
+		h.a.mu.Lock()
+		h.a.closed = true
+		close(h.a.queue)
+		h.a.mu.Unlock()
+		h.a.wg.Wait()
+	})
+	if f, ok := h.next.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// work 从队列中取出记录并写入，队列关闭后退出
+func (a *asyncQueue) work() {
+	defer a.wg.Done()
+	var batch []*asyncRecord
+	for item := range a.queue {
+		for {
+			if item.barrier != nil {
+				item.barrier.Done()
+				item.barrier.Wait()
+				break
+			}
+			h, ok := item.rec.h.(*Handler)
+			if a.opts.MaxBatch <= 1 || !ok {
+				a.handle(item.rec)
+				break
+			}
+			batch = append(batch[:0], item.rec)
+			next, more := a.collect(h, &batch)
+			a.writeBatch(h, batch)
+			if !more {
+				break
+			}
+			// 取出的下一项不能加入本批，继续处理
+			item = next
+		}
+	}
+}
+
+// handle 将一条记录交给被包装的 Handler
+func (a *asyncQueue) handle(rec *asyncRecord) {
+	err := rec.h.Handle(rec.context(), rec.record())
+	a.freeRecord(rec)
+	if err != nil && a.opts.OnError != nil {
+		a.opts.OnError(err)
+	}
+}
+
+// collect 不阻塞地取出写入同一输出的后续记录加入 batch，直到队列为空或达到 MaxBatch
+// 取到屏障或其它 Handler 的记录时停止并返回该项，more 为 true
+func (a *asyncQueue) collect(h *Handler, batch *[]*asyncRecord) (next asyncItem, more bool) {
+	for len(*batch) < a.opts.MaxBatch {
+		select {
+		case item, ok := <-a.queue:
+			if !ok {
+				return asyncItem{}, false
+			}
+			if item.barrier != nil {
+				return item, true
+			}
+			// 派生的 Handler 共享输出和写锁，可以合并写入
+			if bh, ok := item.rec.h.(*Handler); !ok || bh.mu != h.mu {
+				return item, true
+			}
+			*batch = append(*batch, item.rec)
+		default:
+			return asyncItem{}, false
+		}
+	}
+	return asyncItem{}, false
+}
+
+// writeBatch 逐条编码 batch 中的记录，合并为一次写入
+func (a *asyncQueue) writeBatch(h *Handler, batch []*asyncRecord) {
+	bufs := make([][]byte, 0, len(batch))
+	bufps := make([]*[]byte, 0, len(batch))
+	levels := make([]slog.Level, 0, len(batch))
+	for _, rec := range batch {
+		bh := rec.h.(*Handler)
+		bufp := bh.pool.get()
+		b, ok := bh.encode(rec.context(), rec.record(), *bufp)
+		if ok {
+			bufs = append(bufs, b)
+			levels = append(levels, rec.level)
+		}
+		// encode 可能扩容，写入后归还扩容后的 buffer
+		*bufp = b
+		bufps = append(bufps, bufp)
+		a.freeRecord(rec)
+	}
+	var err error
+	if len(bufs) > 0 {
+		err = h.writeBatch(bufs, levels)
+	}
+	if err != nil && a.opts.OnError != nil {
+		a.opts.OnError(err)
+	}
+	for _, bufp := range bufps {
+		h.pool.put(bufp)
+	}
+}
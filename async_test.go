@@ -0,0 +1,342 @@
+package slogplus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingHandler 在 release 关闭之前阻塞 Handle
+type blockingHandler struct {
+	slog.Handler
+	release chan struct{}
+}
+
+func (h *blockingHandler) Handle(ctx context.Context, r slog.Record) error {
+	<-h.release
+	return h.Handler.Handle(ctx, r)
+}
+
+func TestAsync(t *testing.T) {
+	var buf syncBuffer
+	ah := Async(New(&buf, &Options{TimeFormat: "-"}), nil)
+	logger := slog.New(ah).With("app", "demo")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	for i := 0; i < 100; i++ {
+		logger.InfoContext(ctx, "msg", "i", i)
+	}
+	cancel()
+	if err := ah.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 100 || lines[99] != "- INFO app=demo msg=msg i=99" {
+		t.Fatalf("Flush 后应该写入全部记录且保持顺序: %d %q", len(lines), lines[len(lines)-1])
+	}
+
+	ah.Close()
+	ah.Close()
+	logger.Info("after close")
+	if !strings.HasSuffix(buf.String(), "msg=after close\n") {
+		t.Error("Close 之后应该同步写入")
+	}
+}
+
+func TestAsync_RecordCopy(t *testing.T) {
+	var buf syncBuffer
+	release := make(chan struct{})
+	ah := Async(&blockingHandler{Handler: New(&buf, &Options{TimeFormat: "-"}), release: release}, nil)
+
+	attrs := []slog.Attr{slog.Int("a", 1), slog.Int("b", 2), slog.Int("c", 3), slog.Int("d", 4), slog.Int("e", 5), slog.Int("f", 6)}
+	r := slog.NewRecord(time.Time{}, slog.LevelInfo, "copy", 0)
+	r.AddAttrs(attrs...)
+	ah.Handle(context.Background(), r)
+	// 调用方在 Handle 返回后修改记录和属性切片，不应该影响排队的记录
+	attrs[5] = slog.Int("f", 0)
+	r.AddAttrs(slog.String("late", "x"))
+
+	close(release)
+	ah.Close()
+	if got, want := buf.String(), "INFO msg=copy a=1 b=2 c=3 d=4 e=5 f=6\n"; got != want {
+		t.Errorf("排队的记录应该是入队时的副本:\n got %q\nwant %q", got, want)
+	}
+}
+
+// gateHandler 在 mu 被锁定时阻塞 Handle
+type gateHandler struct {
+	mu sync.Mutex
+}
+
+func (h *gateHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *gateHandler) Handle(context.Context, slog.Record) error {
+	h.mu.Lock()
+	h.mu.Unlock()
+	return nil
+}
+func (h *gateHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *gateHandler) WithGroup(string) slog.Handler      { return h }
+
+func TestAsync_NoAlloc(t *testing.T) {
+	gate := &gateHandler{}
+	ah := Async(gate, &AsyncOptions{QueueSize: 1024})
+	defer ah.Close()
+	ctx := context.Background()
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+	r.AddAttrs(slog.String("k", "v"), slog.Int("n", 1), slog.Bool("ok", true))
+
+	// 先让空闲列表中有足够的记录副本
+	gate.mu.Lock()
+	for i := 0; i < 300; i++ {
+		ah.Handle(ctx, r)
+	}
+	gate.mu.Unlock()
+	ah.Flush()
+
+	// 阻塞后台 goroutine，只统计入队的内存分配
+	gate.mu.Lock()
+	allocs := testing.AllocsPerRun(200, func() {
+		ah.Handle(ctx, r)
+	})
+	gate.mu.Unlock()
+	if allocs > 0 {
+		t.Errorf("入队不应该分配内存: %.2f 次/条", allocs)
+	}
+}
+
+// countWriter 记录 Write 的调用次数，first 不为 nil 时第一次写入阻塞到 first 关闭
+type countWriter struct {
+	syncBuffer
+	writes atomic.Int64
+	first  chan struct{}
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	if w.writes.Add(1) == 1 && w.first != nil {
+		<-w.first
+	}
+	return w.syncBuffer.Write(p)
+}
+
+func TestAsync_MaxBatch(t *testing.T) {
+	w := &countWriter{first: make(chan struct{})}
+	h := New(w, &Options{TimeFormat: "-"})
+	ah := Async(h, &AsyncOptions{MaxBatch: 64})
+	logger := slog.New(ah)
+	child := logger.With("child", true)
+
+	logger.Info("msg", "i", 0)
+	for i := 1; i < 100; i++ {
+		if i%10 == 0 {
+			child.Info("msg", "i", i)
+		} else {
+			logger.Info("msg", "i", i)
+		}
+	}
+	close(w.first)
+	if err := ah.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+	if len(lines) != 100 {
+		t.Fatalf("应该写入 100 条记录: %d", len(lines))
+	}
+	for i, line := range lines {
+		want := fmt.Sprintf("- INFO msg=msg i=%d", i)
+		if i%10 == 0 && i > 0 {
+			want = fmt.Sprintf("- INFO child=true msg=msg i=%d", i)
+		}
+		if line != want {
+			t.Fatalf("第 %d 行错误: %q", i, line)
+		}
+	}
+	// 第一条单独写入，其余 99 条最多分两批
+	if n := w.writes.Load(); n > 3 {
+		t.Errorf("排队的记录应该合并写入，实际 Write %d 次", n)
+	}
+
+	// 不是 slogplus Handler 时逐条交给被包装的 Handler
+	var buf syncBuffer
+	release := make(chan struct{})
+	close(release)
+	ah2 := Async(&blockingHandler{Handler: New(&buf, &Options{TimeFormat: "-"}), release: release}, &AsyncOptions{MaxBatch: 64})
+	slog.New(ah2).Info("x")
+	slog.New(ah2).Info("y")
+	ah2.Close()
+	if buf.String() != "- INFO msg=x\n- INFO msg=y\n" || buf.writes != 2 {
+		t.Errorf("非 slogplus Handler 应该逐条写入: %q", buf.String())
+	}
+}
+
+func TestAsync_Drop(t *testing.T) {
+	var buf syncBuffer
+	release := make(chan struct{})
+	var dropped atomic.Int64
+	stats := &PipelineStats{}
+	ah := Async(&blockingHandler{Handler: New(&buf, nil), release: release}, &AsyncOptions{
+		QueueSize: 2,
+		OnDrop:    func(slog.Record) { dropped.Add(1) },
+		Stats:     stats,
+	})
+	logger := slog.New(ah)
+
+	// 第一条被后台 goroutine 取出后阻塞，队列中最多再放 2 条
+	logger.Info("0")
+	time.Sleep(20 * time.Millisecond)
+	var errs []error
+	for i := 1; i <= 5; i++ {
+		errs = append(errs, ah.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, fmt.Sprint(i), 0)))
+	}
+	if ah.Dropped() != 3 || dropped.Load() != 3 || !errors.Is(errors.Join(errs...), ErrAsyncQueueFull) {
+		t.Errorf("队列满时应该丢弃新记录: %d %d %v", ah.Dropped(), dropped.Load(), errs)
+	}
+	if stats.Snapshot().QueueHigh["async"] != 2 {
+		t.Errorf("队列深度统计错误: %+v", stats.Snapshot())
+	}
+
+	close(release)
+	ah.Close()
+	if n := strings.Count(buf.String(), "\n"); n != 3 {
+		t.Errorf("Close 应该写入队列中剩余的记录: %d\n%s", n, buf.String())
+	}
+}
+
+func TestAsync_Workers(t *testing.T) {
+	var buf syncBuffer
+	var errCount atomic.Int64
+	ah := Async(New(&buf, nil), &AsyncOptions{Workers: 4, QueueSize: 10000, OnError: func(error) { errCount.Add(1) }})
+	logger := slog.New(ah)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				logger.Info("m")
+				if i%100 == 0 {
+					ah.Flush()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	ah.Flush()
+	if n := strings.Count(buf.String(), "\n"); n != 4000 {
+		t.Errorf("应该写入全部记录: %d", n)
+	}
+	ah.Close()
+}
+
+// flushCountHandler 记录 Flush 的调用次数
+type flushCountHandler struct {
+	slog.Handler
+	flushes atomic.Int64
+}
+
+func (h *flushCountHandler) Flush() error {
+	h.flushes.Add(1)
+	return nil
+}
+
+func TestAsync_FlushContext(t *testing.T) {
+	out := &syncBuffer{}
+	next := &flushCountHandler{Handler: New(out, nil)}
+	ctx, cancel := context.WithCancel(context.Background())
+	ah := Async(next, &AsyncOptions{FlushContext: ctx})
+	defer ah.Close()
+	slog.New(ah).Info("queued")
+
+	cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for next.flushes.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if next.flushes.Load() != 1 || !strings.Contains(out.String(), "msg=queued") {
+		t.Errorf("context 结束时应该写入排队的记录并刷新: %d %q", next.flushes.Load(), out.String())
+	}
+}
+
+func TestAsync_FlushAllBuffered(t *testing.T) {
+	out := &syncBuffer{}
+	bw := NewBufferedWriter(out, &BufferedOptions{FlushInterval: time.Hour})
+	defer bw.Close()
+	next := &blockingHandler{Handler: New(bw, &Options{TimeFormat: "-"}), release: make(chan struct{})}
+	ah := Async(next, nil)
+	defer ah.Close()
+	slog.New(ah).Info("queued")
+
+	// 队列中的记录写入缓冲之后，缓冲才能被刷新
+	time.AfterFunc(20*time.Millisecond, func() { close(next.release) })
+	if err := FlushAll(); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "- INFO msg=queued\n" {
+		t.Errorf("FlushAll 应该先清空 Async 队列再刷新缓冲: %q", got)
+	}
+
+	ah2 := Async(New(bw, &Options{TimeFormat: "-"}), nil)
+	defer ah2.Close()
+	slog.New(ah2).Info("direct")
+	if err := ah2.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); !strings.HasSuffix(got, "msg=direct\n") {
+		t.Errorf("Async 包装 Handler 时应该刷新其缓冲输出: %q", got)
+	}
+}
+
+func TestAsync_PushScope(t *testing.T) {
+	var buf syncBuffer
+	next := &blockingHandler{Handler: New(&buf, &Options{TimeFormat: "-"}), release: make(chan struct{})}
+	ah := Async(next, nil)
+	defer ah.Close()
+	logger := slog.New(ah)
+
+	pop := PushScope("phase", "migrate")
+	logger.Info("迁移")
+	pop()
+	pop = PushScope("phase", "serve")
+	logger.Info("启动")
+	pop()
+
+	// 记录在 Scope 移除之后才写入，仍然使用入队时的全局属性
+	close(next.release)
+	ah.Flush()
+	want := "- INFO msg=迁移 phase=migrate\n- INFO msg=启动 phase=serve\n"
+	if got := buf.String(); got != want {
+		t.Errorf("全局属性应该在入队时确定:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestAsync_CtxRemaining(t *testing.T) {
+	var buf syncBuffer
+	next := &blockingHandler{Handler: New(&buf, &Options{TimeFormat: "-", CtxRemaining: slog.LevelWarn}), release: make(chan struct{})}
+	ah := Async(next, nil)
+	defer ah.Close()
+	logger := slog.New(ah)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	expired, cancel2 := context.WithDeadline(context.Background(), time.Now().Add(-1500*time.Millisecond))
+	defer cancel2()
+	logger.WarnContext(ctx, "slow")
+	logger.ErrorContext(expired, "timeout")
+	logger.Error("no ctx")
+
+	// 剩余时长在入队时计算，不受排队时间影响
+	time.Sleep(20 * time.Millisecond)
+	close(next.release)
+	ah.Flush()
+	want := "- WARN msg=slow ctx_remaining=1m0s\n- ERROR msg=timeout ctx_remaining=-1.5s\n- ERROR msg=no ctx\n"
+	if got := buf.String(); got != want {
+		t.Errorf("ctx_remaining 错误:\n got %q\nwant %q", got, want)
+	}
+}
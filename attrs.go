@@ -0,0 +1,89 @@
+package slogplus
+
+import (
+	"encoding/hex"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// IPAddr 返回 IP 地址属性，IPv4 映射的 IPv6 地址输出为 IPv4 形式，无效地址输出为空字符串
+func IPAddr(key string, ip netip.Addr) slog.Attr {
+	if !ip.IsValid() {
+		return slog.String(key, "")
+	}
+	return slog.String(key, ip.Unmap().String())
+}
+
+// urlSensitiveParams 是 URL 中会被替换为 REDACTED 的查询参数（不区分大小写）
+var urlSensitiveParams = []string{
+	"access_token", "api_key", "apikey", "auth", "key", "password", "passwd",
+	"secret", "sig", "signature", "token", "x-amz-signature", "x-amz-credential",
+}
+
+// URL 返回 URL 属性，去掉用户名和密码，并将 token、signature 等敏感查询参数的值替换为 REDACTED，
+// 避免日志中出现凭据；u 为 nil 时输出为空字符串
+func URL(key string, u *url.URL) slog.Attr {
+	if u == nil {
+		return slog.String(key, "")
+	}
+	c := *u
+	c.User = nil
+	if c.RawQuery != "" {
+		q := c.Query()
+		changed := false
+		for name := range q {
+			for _, s := range urlSensitiveParams {
+				if strings.EqualFold(name, s) {
+					q[name] = []string{"REDACTED"}
+					changed = true
+					break
+				}
+			}
+		}
+		if changed {
+			c.RawQuery = q.Encode()
+		}
+	}
+	return slog.String(key, c.String())
+}
+
+// Bytes 返回字节数属性，按 1024 进制输出为易读的形式，例如 512B、1.5KiB、2.0GiB
+func Bytes(key string, n int64) slog.Attr {
+	return slog.String(key, formatBytes(n))
+}
+
+// formatBytes 按 1024 进制格式化字节数，不含空格，使文本格式中不需要加引号
+func formatBytes(n int64) string {
+	const units = "KMGTPE"
+	abs := n
+	if abs < 0 {
+		abs = -abs
+	}
+	if abs < 1024 {
+		return strconv.FormatInt(n, 10) + "B"
+	}
+	v, i := float64(n)/1024, 0
+	for ; (v >= 1024 || v <= -1024) && i < len(units)-1; i++ {
+		v /= 1024
+	}
+	return strconv.FormatFloat(v, 'f', 1, 64) + units[i:i+1] + "iB"
+}
+
+// RedactedValue 是被脱敏属性的替换值
+const RedactedValue = "[REDACTED]"
+
+// HashLen 是 Hash 属性保留的十六进制字符数
+const HashLen = 12
+
+// Hash 返回摘要属性，输出为十六进制并截断为前 HashLen 个字符，
+// 与 git 的短提交号类似，足以区分不同的内容而不会让日志行过长
+func Hash(key string, sum []byte) slog.Attr {
+	s := hex.EncodeToString(sum)
+	if len(s) > HashLen {
+		s = s[:HashLen]
+	}
+	return slog.String(key, s)
+}
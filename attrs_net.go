@@ -0,0 +1,16 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"log/slog"
+	"net"
+	"net/netip"
+)
+
+// IP 返回 IP 地址属性，IPv4 映射的 IPv6 地址输出为 IPv4 形式，nil 或无效地址输出为空字符串
+// 精简构建中使用 IPAddr
+func IP(key string, ip net.IP) slog.Attr {
+	addr, _ := netip.AddrFromSlice(ip)
+	return IPAddr(key, addr)
+}
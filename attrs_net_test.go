@@ -0,0 +1,25 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIP(t *testing.T) {
+	for _, tt := range []struct {
+		ip   net.IP
+		want string
+	}{
+		{net.ParseIP("192.168.1.23"), "192.168.1.23"}, // ParseIP 返回 16 字节的映射地址
+		{net.IPv4(10, 0, 0, 1).To4(), "10.0.0.1"},
+		{net.ParseIP("2001:db8::1"), "2001:db8::1"},
+		{nil, ""},
+		{net.IP{1, 2, 3}, ""},
+	} {
+		if got := IP("ip", tt.ip).Value.String(); got != tt.want {
+			t.Errorf("IP(%v) = %q, 期望 %q", tt.ip, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package slogplus
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// AuditPrevKey 是审计日志中上一行哈希的字段名
+const AuditPrevKey = "audit_prev"
+
+// ErrAuditTampered 表示审计日志的哈希链校验失败
+var ErrAuditTampered = errors.New("slogplus: 审计日志已被篡改")
+
+// AuditOptions 定义审计日志的配置
+type AuditOptions struct {
+	// Options 编码选项，输出格式固定为 FormatJSON
+	Options *Options
+
+	// Prev 第一行的上一行哈希，向已有的审计日志追加时设置为 VerifyAudit 返回的最后一行哈希，新日志为空
+	Prev string
+
+	// Key 设置后哈希链使用 HMAC-SHA256，校验时使用 VerifyAuditKey 并传入相同的密钥
+	// 不设置时使用普通的 SHA-256，能够改写日志文件的人可以重新计算之后所有行的哈希，
+	// 此时只有定期将 LastHash 记录到其它系统才能发现篡改；密钥应与日志分开保存，建议至少 32 字节
+	Key []byte
+}
+
+// AuditHandler 输出防篡改的审计日志
+// 每行 JSON 的末尾带有上一行内容的哈希 audit_prev，构成哈希链，
+// 修改、删除或插入任意一行都会使 VerifyAudit 在该处失败
+// 没有设置 AuditOptions.Key 时哈希链只能发现意外损坏和不重算哈希的篡改，
+// 防止蓄意篡改需要设置 Key，或定期将 LastHash 记录到其它系统；哈希链同样无法发现末尾被截断
+type AuditHandler struct {
+	*Handler
+	w *auditWriter
+}
+
+// NewAuditHandler 创建写入 w 的审计 Handler
+func NewAuditHandler(w io.Writer, opts *AuditOptions) *AuditHandler {
+	var o AuditOptions
+	if opts != nil {
+		o = *opts
+	}
+	var ho Options
+	if o.Options != nil {
+		ho = *o.Options
+	}
+	ho.Format, ho.FormatVar = FormatJSON, nil
+
+	aw := &auditWriter{out: w, prev: o.Prev, key: o.Key}
+	return &AuditHandler{Handler: New(aw, &ho), w: aw}
+}
+
+// LastHash 返回最后写入的一行的哈希
+func (h *AuditHandler) LastHash() string {
+	h.w.mu.Lock()
+	defer h.w.mu.Unlock()
+	return h.w.prev
+}
+
+// WithAttrs 实现 slog.Handler，派生的 AuditHandler 共享同一条哈希链
+func (h *AuditHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AuditHandler{Handler: h.Handler.WithAttrs(attrs).(*Handler), w: h.w}
+}
+
+// WithGroup 实现 slog.Handler，派生的 AuditHandler 共享同一条哈希链
+func (h *AuditHandler) WithGroup(name string) slog.Handler {
+	return &AuditHandler{Handler: h.Handler.WithGroup(name).(*Handler), w: h.w}
+}
+
+// auditWriter 在每行 JSON 的末尾加入上一行的哈希
+// 由 AuditHandler 及其 WithAttrs、WithGroup 派生的 Handler 共享
+type auditWriter struct {
+	mu   sync.Mutex
+	out  io.Writer
+	prev string
+	key  []byte // AuditOptions.Key，为空时使用 SHA-256
+}
+
+// Write 为 p 中的每一行加入哈希后一次写入，Async 开启 MaxBatch 时一次 Write 可能包含多行
+// 任意一行不是 JSON 对象时整体拒绝，不写入任何内容
+func (w *auditWriter) Write(p []byte) (int, error) {
+	lines := bytes.SplitAfter(p, []byte{'\n'})
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	for i, line := range lines {
+		line = bytes.TrimSuffix(line, []byte{'\n'})
+		if len(line) < 2 || line[len(line)-1] != '}' {
+			return 0, fmt.Errorf("slogplus: 审计日志只支持 JSON 行: %q", p)
+		}
+		lines[i] = line
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	prev := w.prev
+	out := make([]byte, 0, len(p)+len(lines)*(len(AuditPrevKey)+80))
+	for _, line := range lines {
+		start := len(out)
+		out = append(out, line[:len(line)-1]...)
+		out = append(out, `,"`+AuditPrevKey+`":"`...)
+		out = append(out, prev...)
+		out = append(out, '"', '}')
+		prev = auditSum(w.key, out[start:])
+		out = append(out, '\n')
+	}
+	if _, err := w.out.Write(out); err != nil {
+		return 0, err
+	}
+	w.prev = prev
+	return len(p), nil
+}
+
+// VerifyAudit 校验 AuditHandler 写出的审计日志，prev 为第一行的上一行哈希（与 AuditOptions.Prev 相同），
+// 成功时返回最后一行的哈希；哈希链断开时返回包装了 ErrAuditTampered 的错误，指出第一处异常的行号
+// 设置了 AuditOptions.Key 的日志使用 VerifyAuditKey 校验
+func VerifyAudit(r io.Reader, prev string) (last string, err error) {
+	return VerifyAuditKey(r, prev, nil)
+}
+
+// VerifyAuditKey 与 VerifyAudit 相同，key 为写入时的 AuditOptions.Key
+func VerifyAuditKey(r io.Reader, prev string, key []byte) (last string, err error) {
+	suffixStart := []byte(`,"` + AuditPrevKey + `":"`)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Bytes()
+		i := bytes.LastIndex(line, suffixStart)
+		if i < 0 || !bytes.HasSuffix(line, []byte(`"}`)) {
+			return "", fmt.Errorf("%w: 第 %d 行缺少 %s", ErrAuditTampered, n, AuditPrevKey)
+		}
+		if got := string(line[i+len(suffixStart) : len(line)-2]); got != prev {
+			return "", fmt.Errorf("%w: 第 %d 行的 %s 与上一行不符", ErrAuditTampered, n, AuditPrevKey)
+		}
+		prev = auditSum(key, line)
+	}
+	if err := sc.Err(); err != nil {
+		return "", err
+	}
+	return prev, nil
+}
+
+// auditSum 返回一行的哈希，key 非空时使用 HMAC-SHA256
+func auditSum(key, line []byte) string {
+	if len(key) == 0 {
+		sum := sha256.Sum256(line)
+		return hex.EncodeToString(sum[:])
+	}
+	m := hmac.New(sha256.New, key)
+	m.Write(line)
+	return hex.EncodeToString(m.Sum(nil))
+}
@@ -0,0 +1,111 @@
+package slogplus
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestAuditHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewAuditHandler(&buf, nil)
+
+	audit := slog.New(h)
+	audit.Info("授权变更", "user", "alice", "role", "admin")
+	audit.With("actor", "bob").Warn("删除用户", "user", "carol")
+
+	last, err := VerifyAudit(bytes.NewReader(buf.Bytes()), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last != h.LastHash() || len(last) != 64 {
+		t.Errorf("最后一行哈希错误: %q %q", last, h.LastHash())
+	}
+	lines := strings.SplitAfter(buf.String(), "\n")
+	if !strings.HasSuffix(lines[0], `,"audit_prev":""}`+"\n") {
+		t.Errorf("第一行应该以空哈希开始: %q", lines[0])
+	}
+
+	// 续写已有的日志
+	h2 := NewAuditHandler(&buf, &AuditOptions{Prev: last})
+	slog.New(h2).Info("续写")
+	if _, err := VerifyAudit(bytes.NewReader(buf.Bytes()), ""); err != nil {
+		t.Errorf("续写后应该仍然通过校验: %v", err)
+	}
+
+	// 篡改、删除行
+	tampered := strings.Replace(buf.String(), "alice", "mallory", 1)
+	if _, err := VerifyAudit(strings.NewReader(tampered), ""); !errors.Is(err, ErrAuditTampered) || !strings.Contains(err.Error(), "第 2 行") {
+		t.Errorf("修改的行应该在下一行被发现: %v", err)
+	}
+	lines = strings.SplitAfter(buf.String(), "\n")
+	deleted := lines[0] + lines[2]
+	if _, err := VerifyAudit(strings.NewReader(deleted), ""); !errors.Is(err, ErrAuditTampered) {
+		t.Errorf("删除行应该校验失败: %v", err)
+	}
+}
+
+func TestAuditHandler_AsyncBatch(t *testing.T) {
+	var buf syncBuffer
+	h := NewAuditHandler(&buf, nil)
+	if _, ok := h.WithAttrs([]slog.Attr{slog.String("a", "b")}).WithGroup("g").(*AuditHandler); !ok {
+		t.Error("派生的 Handler 应该仍然是 AuditHandler")
+	}
+
+	ah := Async(h, &AsyncOptions{MaxBatch: 16})
+	logger := slog.New(ah).With("actor", "bob")
+	for i := 0; i < 50; i++ {
+		logger.Info("授权变更", "i", i)
+	}
+	ah.Close()
+
+	if n := strings.Count(buf.String(), "\n"); n != 50 {
+		t.Fatalf("应该写入 50 行: %d", n)
+	}
+	if last, err := VerifyAudit(strings.NewReader(buf.String()), ""); err != nil || last != h.LastHash() {
+		t.Errorf("批量写入后哈希链应该完整: %v", err)
+	}
+}
+
+func TestAuditWriter_MultiLine(t *testing.T) {
+	var buf bytes.Buffer
+	w := &auditWriter{out: &buf}
+	if n, err := w.Write([]byte("{\"a\":1}\n{\"b\":2}\n")); err != nil || n != 16 {
+		t.Fatalf("多行写入错误: %d %v", n, err)
+	}
+	if _, err := VerifyAudit(bytes.NewReader(buf.Bytes()), ""); err != nil || strings.Count(buf.String(), "\n") != 2 {
+		t.Errorf("每一行都应该加入哈希: %v %q", err, buf.String())
+	}
+	if _, err := w.Write([]byte("{\"c\":3}\nplain\n")); err == nil || strings.Count(buf.String(), "\n") != 2 {
+		t.Errorf("包含非 JSON 行时应该整体拒绝: %v", err)
+	}
+}
+
+func TestAuditHandler_Key(t *testing.T) {
+	var buf bytes.Buffer
+	key := []byte("0123456789abcdef0123456789abcdef")
+	audit := slog.New(NewAuditHandler(&buf, &AuditOptions{Key: key}))
+	audit.Info("授权变更", "user", "alice")
+	audit.Info("删除用户", "user", "carol")
+
+	if _, err := VerifyAuditKey(bytes.NewReader(buf.Bytes()), "", key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyAudit(bytes.NewReader(buf.Bytes()), ""); !errors.Is(err, ErrAuditTampered) {
+		t.Errorf("不带密钥不应该通过校验: %v", err)
+	}
+
+	// 没有密钥的攻击者篡改后重新计算 SHA-256 哈希链
+	var forged bytes.Buffer
+	w := &auditWriter{out: &forged}
+	for _, line := range strings.SplitAfter(buf.String(), "\n") {
+		if i := strings.LastIndex(line, `,"`+AuditPrevKey+`"`); i >= 0 {
+			w.Write([]byte(strings.Replace(line[:i], "alice", "mallory", 1) + "}\n"))
+		}
+	}
+	if _, err := VerifyAuditKey(bytes.NewReader(forged.Bytes()), "", key); !errors.Is(err, ErrAuditTampered) {
+		t.Errorf("重新计算哈希的篡改应该被发现: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package slogplus
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Baggage 将 OpenTelemetry baggage（跨服务传递的租户、实验开关等键值）复制到日志属性中，
+// slogplus 不依赖 OpenTelemetry SDK，通过 From 取出 baggage:
+//
+//	&slogplus.Options{Baggage: &slogplus.Baggage{
+//		Keys: []string{"tenant", "experiment"},
+//		From: func(ctx context.Context) map[string]string {
+//			m := make(map[string]string)
+//			for _, mem := range baggage.FromContext(ctx).Members() {
+//				m[mem.Key()] = mem.Value()
+//			}
+//			return m
+//		},
+//	}}
+type Baggage struct {
+	// From 从 context 中取出 baggage 条目，必须设置
+	// 没有使用 OpenTelemetry 时可以配合 ParseBaggage 解析 W3C baggage 请求头
+	From func(ctx context.Context) map[string]string
+
+	// Keys 需要复制的条目，为空时复制所有条目
+	Keys []string
+
+	// Group 属性所在的分组，默认为 "baggage"，设置为 "-" 时作为顶层属性
+	Group string
+}
+
+// BaggageMaxMembers 是复制到日志中的最大条目数，避免上游传入大量条目使日志膨胀
+const BaggageMaxMembers = 64
+
+// attrs 返回 context 中 baggage 对应的属性，按键排序
+func (b *Baggage) attrs(ctx context.Context) []slog.Attr {
+	if b == nil || b.From == nil || ctx == nil {
+		return nil
+	}
+	m := b.From(ctx)
+	if len(m) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if len(b.Keys) == 0 || slices.Contains(b.Keys, k) {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	slices.Sort(keys)
+	if len(keys) > BaggageMaxMembers {
+		keys = keys[:BaggageMaxMembers]
+	}
+	attrs := make([]slog.Attr, len(keys))
+	for i, k := range keys {
+		attrs[i] = slog.String(k, m[k])
+	}
+
+	group := b.Group
+	if group == "" {
+		group = "baggage"
+	} else if group == "-" {
+		return attrs
+	}
+	return []slog.Attr{{Key: group, Value: slog.GroupValue(attrs...)}}
+}
+
+// withBaggage 将 baggage 属性添加到记录末尾
+func (b *Baggage) withBaggage(ctx context.Context, r slog.Record) slog.Record {
+	attrs := b.attrs(ctx)
+	if len(attrs) == 0 {
+		return r
+	}
+	r = r.Clone()
+	r.AddAttrs(attrs...)
+	return r
+}
+
+// ParseBaggage 解析 W3C baggage 请求头（例如 "tenant=acme,exp=new-ui;ttl=3"），
+// 忽略条目的属性（';' 之后的部分）和无法解析的条目，值经过百分号解码
+func ParseBaggage(header string) map[string]string {
+	m := make(map[string]string)
+	for _, member := range strings.Split(header, ",") {
+		member, _, _ = strings.Cut(member, ";")
+		k, v, ok := strings.Cut(member, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			continue
+		}
+		if dv, err := url.PathUnescape(strings.TrimSpace(v)); err == nil {
+			m[k] = dv
+		}
+	}
+	return m
+}
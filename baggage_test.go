@@ -0,0 +1,48 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+type baggageKey struct{}
+
+func TestBaggage(t *testing.T) {
+	from := func(ctx context.Context) map[string]string {
+		m, _ := ctx.Value(baggageKey{}).(map[string]string)
+		return m
+	}
+	ctx := context.WithValue(context.Background(), baggageKey{}, map[string]string{
+		"tenant": "acme", "experiment": "new-ui", "internal": "x",
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, &Options{TimeFormat: "-", Format: FormatJSON, Baggage: &Baggage{
+		From: from,
+		Keys: []string{"tenant", "experiment"},
+	}}))
+	logger.InfoContext(ctx, "handled", "n", 1)
+	logger.Info("no baggage")
+
+	lines := bytes.Split(buf.Bytes(), []byte("\n"))
+	if !bytes.HasSuffix(lines[0], []byte(`"msg":"handled","n":1,"baggage":{"experiment":"new-ui","tenant":"acme"}}`)) ||
+		bytes.Contains(lines[1], []byte(`"baggage":`)) {
+		t.Errorf("baggage 属性错误:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	logger = slog.New(New(&buf, &Options{TimeFormat: "-", Baggage: &Baggage{From: from, Group: "-"}}))
+	logger.InfoContext(ctx, "handled")
+	if got := buf.String(); got != "- INFO msg=handled experiment=new-ui internal=x tenant=acme\n" {
+		t.Errorf("顶层 baggage 属性错误: %q", got)
+	}
+}
+
+func TestParseBaggage(t *testing.T) {
+	m := ParseBaggage(" tenant = acme , exp=new%20ui;ttl=3,broken,=x,bad=%zz")
+	if len(m) != 2 || m["tenant"] != "acme" || m["exp"] != "new ui" {
+		t.Errorf("解析错误: %v", m)
+	}
+}
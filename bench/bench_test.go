@@ -0,0 +1,244 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/IAmMrChen/slogplus"
+	"github.com/rs/zerolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const message = "request completed"
+
+// config 是一组基准测试的参数
+type config struct {
+	attrs  int  // 每条记录的属性数量
+	with   int  // With 链深度，每层添加一个属性
+	source bool // 是否输出源代码位置
+}
+
+// target 是参与对比的日志库，setup 返回输出一条记录的函数
+type target struct {
+	name  string
+	setup func(c config) func()
+}
+
+var targets = []target{
+	{"slogplus-text", func(c config) func() {
+		return slogLogger(slogplus.New(io.Discard, &slogplus.Options{AddSource: c.source}), c)
+	}},
+	{"slogplus-json", func(c config) func() {
+		return slogLogger(slogplus.New(io.Discard, &slogplus.Options{AddSource: c.source, Format: slogplus.FormatJSON}), c)
+	}},
+	{"slog-text", func(c config) func() {
+		return slogLogger(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{AddSource: c.source}), c)
+	}},
+	{"slog-json", func(c config) func() {
+		return slogLogger(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{AddSource: c.source}), c)
+	}},
+	{"zap", zapLogger},
+	{"zerolog", zerologLogger},
+}
+
+// 属性值按类型轮换，各日志库记录相同的内容
+var (
+	attrKeys = []string{"path", "status", "cached", "latency", "ratio"}
+	strVal   = "/api/v1/users"
+	intVal   = 200
+	boolVal  = true
+	durVal   = 12 * time.Millisecond
+	floatVal = 0.75
+)
+
+func attrKey(i int) string { return fmt.Sprintf("%s%d", attrKeys[i%len(attrKeys)], i) }
+
+func slogAttrs(n int) []slog.Attr {
+	attrs := make([]slog.Attr, n)
+	for i := range attrs {
+		k := attrKey(i)
+		switch i % len(attrKeys) {
+		case 0:
+			attrs[i] = slog.String(k, strVal)
+		case 1:
+			attrs[i] = slog.Int(k, intVal)
+		case 2:
+			attrs[i] = slog.Bool(k, boolVal)
+		case 3:
+			attrs[i] = slog.Duration(k, durVal)
+		default:
+			attrs[i] = slog.Float64(k, floatVal)
+		}
+	}
+	return attrs
+}
+
+func slogLogger(h slog.Handler, c config) func() {
+	l := slog.New(h)
+	for i := 0; i < c.with; i++ {
+		l = l.With(slog.Int(fmt.Sprintf("with%d", i), i))
+	}
+	ctx := context.Background()
+	attrs := slogAttrs(c.attrs)
+	return func() { l.LogAttrs(ctx, slog.LevelInfo, message, attrs...) }
+}
+
+func zapFields(n int) []zap.Field {
+	fields := make([]zap.Field, n)
+	for i := range fields {
+		k := attrKey(i)
+		switch i % len(attrKeys) {
+		case 0:
+			fields[i] = zap.String(k, strVal)
+		case 1:
+			fields[i] = zap.Int(k, intVal)
+		case 2:
+			fields[i] = zap.Bool(k, boolVal)
+		case 3:
+			fields[i] = zap.Duration(k, durVal)
+		default:
+			fields[i] = zap.Float64(k, floatVal)
+		}
+	}
+	return fields
+}
+
+func zapLogger(c config) func() {
+	enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	var opts []zap.Option
+	if c.source {
+		opts = append(opts, zap.AddCaller())
+	}
+	l := zap.New(zapcore.NewCore(enc, zapcore.AddSync(io.Discard), zap.InfoLevel), opts...)
+	for i := 0; i < c.with; i++ {
+		l = l.With(zap.Int(fmt.Sprintf("with%d", i), i))
+	}
+	fields := zapFields(c.attrs)
+	return func() { l.Info(message, fields...) }
+}
+
+func zerologLogger(c config) func() {
+	ctx := zerolog.New(io.Discard).With().Timestamp()
+	if c.source {
+		ctx = ctx.Caller()
+	}
+	for i := 0; i < c.with; i++ {
+		ctx = ctx.Int(fmt.Sprintf("with%d", i), i)
+	}
+	l := ctx.Logger()
+	keys := make([]string, c.attrs)
+	for i := range keys {
+		keys[i] = attrKey(i)
+	}
+	return func() {
+		e := l.Info()
+		for i, k := range keys {
+			switch i % len(attrKeys) {
+			case 0:
+				e = e.Str(k, strVal)
+			case 1:
+				e = e.Int(k, intVal)
+			case 2:
+				e = e.Bool(k, boolVal)
+			case 3:
+				e = e.Dur(k, durVal)
+			default:
+				e = e.Float64(k, floatVal)
+			}
+		}
+		e.Msg(message)
+	}
+}
+
+// run 对所有日志库运行同一组参数
+func run(b *testing.B, c config) {
+	for _, t := range targets {
+		b.Run(t.name, func(b *testing.B) {
+			log := t.setup(c)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				log()
+			}
+		})
+	}
+}
+
+func BenchmarkAttrs(b *testing.B) {
+	for _, n := range []int{0, 5, 10, 20} {
+		b.Run(fmt.Sprintf("attrs=%d", n), func(b *testing.B) {
+			run(b, config{attrs: n})
+		})
+	}
+}
+
+func BenchmarkWith(b *testing.B) {
+	for _, n := range []int{1, 5, 10} {
+		b.Run(fmt.Sprintf("with=%d", n), func(b *testing.B) {
+			run(b, config{attrs: 5, with: n})
+		})
+	}
+}
+
+func BenchmarkAddSource(b *testing.B) {
+	run(b, config{attrs: 5, source: true})
+}
+
+func BenchmarkParallel(b *testing.B) {
+	for _, t := range targets {
+		b.Run(t.name, func(b *testing.B) {
+			log := t.setup(config{attrs: 5, with: 1})
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					log()
+				}
+			})
+		})
+	}
+}
+
+// TestAllocReport 输出各日志库每条记录的内存分配次数，使用 -v 查看
+// slogplus 的分配次数超过标准库同类 Handler 时测试失败
+func TestAllocReport(t *testing.T) {
+	configs := []struct {
+		name string
+		config
+	}{
+		{"attrs=0", config{}},
+		{"attrs=5", config{attrs: 5}},
+		{"attrs=20", config{attrs: 20}},
+		{"with=5", config{attrs: 5, with: 5}},
+		{"source", config{attrs: 5, source: true}},
+	}
+
+	allocs := make(map[string]float64)
+	header := fmt.Sprintf("%-10s", "")
+	for _, tg := range targets {
+		header += fmt.Sprintf("%15s", tg.name)
+	}
+	t.Log(header)
+	for _, c := range configs {
+		line := fmt.Sprintf("%-10s", c.name)
+		for _, tg := range targets {
+			n := testing.AllocsPerRun(200, tg.setup(c.config))
+			allocs[c.name+"/"+tg.name] = n
+			line += fmt.Sprintf("%15.1f", n)
+		}
+		t.Log(line)
+	}
+
+	for _, c := range configs {
+		for _, pair := range [][2]string{{"slogplus-text", "slog-text"}, {"slogplus-json", "slog-json"}} {
+			if got, std := allocs[c.name+"/"+pair[0]], allocs[c.name+"/"+pair[1]]; got > std {
+				t.Errorf("%s: %s 每条记录分配 %.1f 次，多于 %s 的 %.1f 次", c.name, pair[0], got, pair[1], std)
+			}
+		}
+	}
+}
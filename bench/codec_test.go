@@ -0,0 +1,39 @@
+package bench
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/IAmMrChen/slogplus"
+	"github.com/golang/snappy"
+)
+
+// TestSnappy_Reference 使用 github.com/golang/snappy 解压 slogplus.Snappy 的输出，
+// 确认自带的编码器与 Kafka 等系统使用的参考实现兼容；根模块只依赖标准库，对照测试放在这里
+func TestSnappy_Reference(t *testing.T) {
+	random := make([]byte, 200<<10)
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := range random {
+		random[i] = byte(r.UintN(256))
+	}
+	logs := strings.Repeat(`{"time":"2025-11-14T14:03:14Z","level":"INFO","msg":"request","status":200}`+"\n", 3000)
+
+	for name, src := range map[string][]byte{
+		"empty":  {},
+		"short":  []byte("abc"),
+		"repeat": bytes.Repeat([]byte{'x'}, 100000),
+		"random": random,
+		"logs":   []byte(logs),
+	} {
+		out, err := slogplus.Snappy.Compress(nil, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := snappy.Decode(nil, out)
+		if err != nil || !bytes.Equal(got, src) {
+			t.Errorf("%s: 参考实现解压失败: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+// Package bench 是 slogplus 与标准库 slog.TextHandler、slog.JSONHandler 以及 zap、zerolog 的对比基准测试
+//
+// 该目录是独立的 Go 模块，zap 和 zerolog 只是基准测试的依赖，不会进入 slogplus 的依赖列表；
+// 需要第三方参考实现的兼容性测试（例如用 github.com/golang/snappy 解压 slogplus.Snappy 的输出）也放在这里。
+// 所有日志库都写入 io.Discard，使用相同的消息和属性，覆盖不同的属性数量、With 链深度和 AddSource：
+//
+//	cd bench
+//	go test -bench . -benchmem
+//	go test -run TestAllocReport -v   # 输出各日志库每条记录的内存分配次数
+//
+// 修改编码路径前后各运行一次，使用 benchstat 比较结果，可以发现性能回退。
+package bench
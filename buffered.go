@@ -0,0 +1,144 @@
+package slogplus
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// BufferedOptions 定义 BufferedWriter 的配置
+type BufferedOptions struct {
+	// Size 缓冲区大小，写满后立即刷新，默认 64KB
+	Size int
+
+	// FlushInterval 定时刷新间隔，默认 1 秒
+	FlushInterval time.Duration
+
+	// MaxRecords 缓冲的最大记录数（即 Write 调用次数），达到后立即刷新，0 表示只按大小和间隔刷新
+	// Handler 每条记录只调用一次 Write，批量写入时不会拆开一条记录
+	MaxRecords int
+
+	// Stats 设置后报告每批的记录数（队列深度）和写入耗时，名称为 "buffered"
+	Stats *PipelineStats
+
+	// FlushContext 设置后在该 context 结束时立即刷新，通常为服务的根 context，
+	// 使 SIGTERM 等关闭流程中缓冲的记录不会丢失
+	FlushContext context.Context
+}
+
+// BufferedWriter 在内存中合并写入，按大小、记录数或间隔批量写入底层输出
+// 高吞吐的服务不必为每行日志付出一次系统调用。创建时自动注册到 FlushAll，
+// 进程退出前需要调用 Close（或通过 NotifyShutdown）刷新剩余内容
+type BufferedWriter struct {
+	out  io.Writer
+	opts BufferedOptions
+
+	mu      sync.Mutex // 保护 buf 和 records
+	buf     []byte
+	spare   []byte
+	records int
+
+	wmu sync.Mutex // 保证刷新按顺序写入底层输出
+
+	stop       chan struct{}
+	done       chan struct{}
+	once       sync.Once
+	unregister func()
+	stopCtx    func()
+}
+
+// NewBufferedWriter 创建写入 out 的 BufferedWriter
+func NewBufferedWriter(out io.Writer, opts *BufferedOptions) *BufferedWriter {
+	w := &BufferedWriter{
+		out:  out,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	if opts != nil {
+		w.opts = *opts
+	}
+
+	// 设置默认值
+	if w.opts.Size <= 0 {
+		w.opts.Size = 64 << 10
+	}
+	if w.opts.FlushInterval <= 0 {
+		w.opts.FlushInterval = time.Second
+	}
+	w.buf = make([]byte, 0, w.opts.Size)
+	w.spare = make([]byte, 0, w.opts.Size)
+
+	w.unregister = RegisterFlusher(w)
+	w.stopCtx = func() {}
+	if w.opts.FlushContext != nil {
+		w.stopCtx = FlushOnDone(w.opts.FlushContext, w)
+	}
+	go w.loop()
+	return w
+}
+
+// Write 将 p 追加到缓冲区，缓冲区写满或达到 MaxRecords 时刷新
+func (w *BufferedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	w.buf = append(w.buf, p...)
+	w.records++
+	full := len(w.buf) >= w.opts.Size || w.opts.MaxRecords > 0 && w.records >= w.opts.MaxRecords
+	w.mu.Unlock()
+
+	if full {
+		if err := w.Flush(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush 将缓冲区中的内容写入底层输出
+func (w *BufferedWriter) Flush() error {
+	w.wmu.Lock()
+	defer w.wmu.Unlock()
+
+	// 交换缓冲区，写入底层输出时不阻塞新的日志
+	w.mu.Lock()
+	buf, records := w.buf, w.records
+	w.buf, w.records = w.spare[:0], 0
+	w.mu.Unlock()
+
+	if len(buf) == 0 {
+		w.spare = buf
+		return nil
+	}
+	start := time.Now()
+	_, err := w.out.Write(buf)
+	w.opts.Stats.QueueDepth("buffered", records)
+	w.opts.Stats.Latency("buffered", time.Since(start))
+	w.spare = buf[:0]
+	return err
+}
+
+// Close 停止定时刷新并写入剩余内容，不会关闭底层输出
+func (w *BufferedWriter) Close() error {
+	w.once.Do(func() {
+		w.unregister()
+		w.stopCtx()
+		close(w.stop)
+		<-w.done
+	})
+	return w.Flush()
+}
+
+// loop 定时刷新
+func (w *BufferedWriter) loop() {
+	defer close(w.done)
+	t := time.NewTicker(w.opts.FlushInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			w.Flush()
+		case <-w.stop:
+			return
+		}
+	}
+}
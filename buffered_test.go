@@ -0,0 +1,119 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer 是可以并发读写的 bytes.Buffer
+type syncBuffer struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	writes int
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.writes++
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestBufferedWriter(t *testing.T) {
+	out := &syncBuffer{}
+	w := NewBufferedWriter(out, &BufferedOptions{Size: 16, FlushInterval: time.Hour})
+	defer w.Close()
+
+	w.Write([]byte("hello\n"))
+	if out.String() != "" {
+		t.Fatal("缓冲区未满时不应该写入")
+	}
+	w.Write([]byte("world world\n"))
+	if out.String() != "hello\nworld world\n" {
+		t.Errorf("缓冲区写满后应该刷新: %q", out.String())
+	}
+	if out.writes != 1 {
+		t.Errorf("应该合并为一次写入，实际 %d 次", out.writes)
+	}
+
+	w.Write([]byte("tail\n"))
+	if err := FlushAll(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "hello\nworld world\ntail\n" {
+		t.Errorf("FlushAll 应该刷新 BufferedWriter: %q", out.String())
+	}
+}
+
+func TestBufferedWriter_Interval(t *testing.T) {
+	out := &syncBuffer{}
+	w := NewBufferedWriter(out, &BufferedOptions{FlushInterval: 10 * time.Millisecond})
+	defer w.Close()
+
+	w.Write([]byte("line\n"))
+	deadline := time.Now().Add(time.Second)
+	for out.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if out.String() != "line\n" {
+		t.Errorf("应该按间隔刷新: %q", out.String())
+	}
+}
+
+func TestBufferedWriter_Close(t *testing.T) {
+	out := &syncBuffer{}
+	w := NewBufferedWriter(out, nil)
+	NewLogger(w, nil).Info("before close")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains([]byte(out.String()), []byte("before close")) {
+		t.Errorf("Close 应该写入剩余内容: %q", out.String())
+	}
+}
+
+func TestBufferedWriter_MaxRecords(t *testing.T) {
+	out := &syncBuffer{}
+	stats := &PipelineStats{}
+	w := NewBufferedWriter(out, &BufferedOptions{MaxRecords: 3, FlushInterval: time.Hour, Stats: stats})
+	defer w.Close()
+	logger := NewLogger(w, &Options{TimeFormat: "-"})
+
+	for i := 0; i < 7; i++ {
+		logger.Info("batch", "i", i)
+	}
+	if out.writes != 2 || bytes.Count([]byte(out.String()), []byte("\n")) != 6 {
+		t.Errorf("应该每 3 条记录写入一次: %d 次\n%s", out.writes, out.String())
+	}
+	w.Flush()
+	snap := stats.Snapshot()
+	if snap.QueueHigh["buffered"] != 3 || snap.LatencyCount["buffered"] != 3 {
+		t.Errorf("批量写入统计错误: %+v", snap)
+	}
+}
+
+func TestBufferedWriter_FlushContext(t *testing.T) {
+	out := &syncBuffer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	w := NewBufferedWriter(out, &BufferedOptions{FlushInterval: time.Hour, FlushContext: ctx})
+	defer w.Close()
+
+	w.Write([]byte("pending\n"))
+	cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for out.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if out.String() != "pending\n" {
+		t.Errorf("context 结束时应该刷新: %q", out.String())
+	}
+}
@@ -0,0 +1,60 @@
+package slogplus
+
+import "sync"
+
+const (
+	// minBufferSize 新分配 buffer 的初始容量，大多数日志都够用
+	minBufferSize = 256
+	// DefaultMaxBufferSize 是 Options.MaxBufferSize 的默认值
+	DefaultMaxBufferSize = 64 << 10
+)
+
+// bufferTiers 是 buffer 容量的分级上界，最后一级为 [16K, maxCap]
+var bufferTiers = [...]int{1 << 10, 4 << 10, 16 << 10}
+
+// bufferPool 按容量分级缓存编码 buffer
+// 取用时优先使用容量最小的一级，偶尔出现的超长日志不会让后续的普通日志都占用大 buffer；
+// 容量超过 maxCap 的 buffer 直接丢弃，不会因为一条巨大的日志而长期驻留内存
+type bufferPool struct {
+	tiers  [len(bufferTiers) + 1]sync.Pool
+	maxCap int
+}
+
+// newBufferPool 创建 bufferPool，maxCap 小于等于 0 时使用 DefaultMaxBufferSize
+func newBufferPool(maxCap int) *bufferPool {
+	if maxCap <= 0 {
+		maxCap = DefaultMaxBufferSize
+	}
+	return &bufferPool{maxCap: maxCap}
+}
+
+// get 取出一个长度为 0 的 buffer
+func (p *bufferPool) get() *[]byte {
+	for i := range p.tiers {
+		if b, ok := p.tiers[i].Get().(*[]byte); ok {
+			*b = (*b)[:0]
+			return b
+		}
+	}
+	b := make([]byte, 0, minBufferSize)
+	return &b
+}
+
+// put 按容量放回 buffer，超过 maxCap 的 buffer 被丢弃
+func (p *bufferPool) put(b *[]byte) {
+	c := cap(*b)
+	if c > p.maxCap {
+		return
+	}
+	p.tiers[bufferTier(c)].Put(b)
+}
+
+// bufferTier 返回容量 c 所在的级别
+func bufferTier(c int) int {
+	for i, max := range bufferTiers {
+		if c < max {
+			return i
+		}
+	}
+	return len(bufferTiers)
+}
@@ -0,0 +1,61 @@
+package slogplus
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestBufferPool(t *testing.T) {
+	p := newBufferPool(8 << 10)
+
+	b := p.get()
+	if len(*b) != 0 || cap(*b) != minBufferSize {
+		t.Fatalf("新 buffer 的长度应为 0、容量应为 %d: len=%d cap=%d", minBufferSize, len(*b), cap(*b))
+	}
+
+	big := make([]byte, 100, 16<<10)
+	p.put(&big)
+	for i := range p.tiers {
+		if x := p.tiers[i].Get(); x != nil {
+			t.Errorf("超过上限的 buffer 不应该放回缓存池，级别 %d", i)
+		}
+	}
+
+	for c, want := range map[int]int{256: 0, 1023: 0, 1024: 1, 4 << 10: 2, 16 << 10: 3, 1 << 20: 3} {
+		if got := bufferTier(c); got != want {
+			t.Errorf("bufferTier(%d) = %d, 期望 %d", c, got, want)
+		}
+	}
+
+	// -race 模式下 sync.Pool 会随机丢弃放回的对象，多试几次
+	for i := 0; ; i++ {
+		mid := make([]byte, 10, 2<<10)
+		p.put(&mid)
+		got := p.get()
+		if cap(*got) == 2<<10 {
+			if len(*got) != 0 {
+				t.Errorf("取回的 buffer 长度应该重置为 0: %d", len(*got))
+			}
+			break
+		}
+		if i == 100 {
+			t.Fatal("应该取回放入的 buffer")
+		}
+	}
+}
+
+func BenchmarkHandler_LargeLine(b *testing.B) {
+	logger := NewLogger(io.Discard, nil)
+	big := strings.Repeat("x", 128<<10)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if i%100 == 0 {
+			logger.Info("big", "body", big)
+		} else {
+			logger.Info("test message", "key1", "value1", "key2", 42)
+		}
+	}
+}
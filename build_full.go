@@ -0,0 +1,7 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+// Slim 表示当前是否为精简构建
+// 完整构建包含网络、信号、反射等全部功能
+const Slim = false
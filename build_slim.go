@@ -0,0 +1,8 @@
+//go:build slogplus_slim || tinygo
+
+package slogplus
+
+// Slim 表示当前是否为精简构建
+// 使用 -tags slogplus_slim 或 TinyGo 编译时只保留核心 Handler，
+// 不包含网络输出、信号处理、反射等依赖较重的功能，适用于嵌入式网关
+const Slim = true
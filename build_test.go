@@ -0,0 +1,46 @@
+package slogplus
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// slimForbidden 是精简构建中不允许引入的包
+var slimForbidden = []string{
+	"net",
+	"net/http",
+	"os/exec",
+	"os/signal",
+	"crypto/tls",
+	"expvar",
+}
+
+func TestSlimBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short 模式跳过精简构建检查")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("找不到 go 命令")
+	}
+
+	out, err := exec.Command(goBin, "build", "-tags", "slogplus_slim", ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("精简构建失败: %v\n%s", err, out)
+	}
+
+	out, err = exec.Command(goBin, "list", "-tags", "slogplus_slim", "-deps", ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("go list 失败: %v\n%s", err, out)
+	}
+	deps := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		deps[strings.TrimSpace(line)] = true
+	}
+	for _, pkg := range slimForbidden {
+		if deps[pkg] {
+			t.Errorf("精简构建不应该依赖 %s", pkg)
+		}
+	}
+}
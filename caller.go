@@ -0,0 +1,65 @@
+package slogplus
+
+import (
+	"log/slog"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// helpers 是通过 Helper 标记的函数名
+var (
+	helpers    sync.Map // 函数名 -> struct{}
+	hasHelpers atomic.Bool
+)
+
+// Helper 将调用它的函数标记为日志辅助函数，与 testing.T.Helper 类似：
+// 开启 AddSource 时，源代码位置跳过被标记的函数，显示为辅助函数的调用方
+//
+//	func logFailure(ctx context.Context, op string, err error) {
+//		slogplus.Helper()
+//		slog.ErrorContext(ctx, op+" 失败", "err", err)
+//	}
+//
+// 只对在调用方 goroutine 中处理记录的 Handler 生效，经过 Async 等异步队列的记录保持原来的位置
+func Helper() {
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	fn := callerFrame(pcs[0]).Function
+	if _, ok := helpers.Load(fn); !ok {
+		helpers.Store(fn, struct{}{})
+		hasHelpers.Store(true)
+	}
+}
+
+// isHelper 判断 pc 所在的函数是否被 Helper 标记
+func isHelper(pc uintptr) bool {
+	_, ok := helpers.Load(callerFrame(pc).Function)
+	return ok
+}
+
+// adjustCaller 从记录的调用位置向外跳过 skip 层调用以及被 Helper 标记的函数，返回新的调用位置
+// 调用位置不在当前 goroutine 的调用栈中时原样返回
+func adjustCaller(r slog.Record, skip int) slog.Record {
+	if r.PC == 0 || (skip <= 0 && !hasHelpers.Load()) {
+		return r
+	}
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	pcs = pcs[:n]
+	i := 0
+	for i < len(pcs) && pcs[i] != r.PC {
+		i++
+	}
+	if i == len(pcs) {
+		return r
+	}
+	i += max(skip, 0)
+	for i < len(pcs) && isHelper(pcs[i]) {
+		i++
+	}
+	if i < len(pcs) {
+		r.PC = pcs[i]
+	}
+	return r
+}
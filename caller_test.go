@@ -0,0 +1,42 @@
+package slogplus
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+//go:noinline
+func callerTestWrapper(l *slog.Logger, msg string) {
+	l.Info(msg)
+}
+
+func callerTestHelper(l *slog.Logger, msg string) {
+	Helper()
+	l.Info(msg)
+}
+
+func TestCallerSkip(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{TimeFormat: "-", AddSource: true, CallerSkip: 1})
+	callerTestWrapper(logger, "封装")
+	if got := buf.String(); !strings.Contains(got, "caller_test.go:23 msg=封装") {
+		t.Errorf("源代码位置应该是封装函数的调用方: %q", got)
+	}
+}
+
+func TestHelper(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{TimeFormat: "-", AddSource: true})
+	callerTestHelper(logger, "辅助")
+	logger.Info("直接")
+
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.Contains(lines[0], "caller_test.go:32 msg=辅助") {
+		t.Errorf("源代码位置应该跳过辅助函数: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "caller_test.go:33 msg=直接") {
+		t.Errorf("未标记的调用位置不应该改变: %q", lines[1])
+	}
+}
@@ -0,0 +1,169 @@
+package slogplus
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Capture 是按需触发的突发抓取模式
+// 抓取期间 Handler 会放行 Level（默认 Debug）及以上的记录，并把所有记录额外写入抓取输出；
+// 低于正常级别的记录只写入抓取输出，不会影响主输出。
+// 达到时长或记录数上限后自动恢复，适合排查线上偶发问题。
+// 通过 Options.Capture 关联到 Handler，通常由 Admin 的 /capture 接口触发
+type Capture struct {
+	// Dir 抓取文件所在目录，默认为 os.TempDir()
+	Dir string
+
+	// Level 抓取期间的最低级别，默认为 Debug
+	Level slog.Leveler
+
+	active atomic.Bool
+	left   atomic.Int64 // 剩余记录数，<=0 表示不限制
+
+	mu    sync.Mutex
+	w     io.Writer
+	c     io.Closer
+	path  string
+	gen   uint64
+	timer *time.Timer
+	done  chan struct{}
+}
+
+// ErrCaptureActive 表示已经有正在进行的抓取
+var ErrCaptureActive = errors.New("slogplus: 抓取正在进行中")
+
+// CaptureStatus 是抓取状态
+type CaptureStatus struct {
+	Active bool   `json:"active"`
+	Path   string `json:"path,omitempty"`
+	Left   int64  `json:"records_left,omitempty"`
+}
+
+// Start 开始抓取，写入 Dir 下新建的文件，返回文件路径
+// d 为抓取时长，records 为最多抓取的记录数，两者为 0 表示不限制（至少需要设置一个）
+func (c *Capture) Start(d time.Duration, records int) (string, error) {
+	if c.Active() {
+		return "", ErrCaptureActive
+	}
+	dir := c.Dir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	name := "capture-" + time.Now().Format("20060102-150405.000") + ".log"
+	path := filepath.Join(dir, name)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+	if _, err := c.start(f, f, path, d, records); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// StartWriter 开始抓取，写入 w，返回的 channel 在抓取结束时关闭
+func (c *Capture) StartWriter(w io.Writer, d time.Duration, records int) (<-chan struct{}, error) {
+	return c.start(w, nil, "", d, records)
+}
+
+func (c *Capture) start(w io.Writer, closer io.Closer, path string, d time.Duration, records int) (<-chan struct{}, error) {
+	if d <= 0 && records <= 0 {
+		return nil, errors.New("slogplus: 抓取必须设置时长或记录数")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.active.Load() {
+		return nil, ErrCaptureActive
+	}
+
+	c.w, c.c, c.path = w, closer, path
+	c.gen++
+	c.done = make(chan struct{})
+	c.left.Store(int64(records))
+	if d > 0 {
+		gen := c.gen
+		c.timer = time.AfterFunc(d, func() { c.stop(gen) })
+	}
+	c.active.Store(true)
+	return c.done, nil
+}
+
+// Stop 立即结束抓取
+func (c *Capture) Stop() error {
+	c.mu.Lock()
+	gen := c.gen
+	c.mu.Unlock()
+	return c.stop(gen)
+}
+
+// stop 结束第 gen 次抓取，避免过期的定时器结束新的抓取
+func (c *Capture) stop(gen uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.active.Load() || gen != c.gen {
+		return nil
+	}
+	c.active.Store(false)
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	close(c.done)
+
+	var err error
+	if c.c != nil {
+		err = c.c.Close()
+	}
+	c.w, c.c = nil, nil
+	return err
+}
+
+// Active 返回是否正在抓取
+func (c *Capture) Active() bool {
+	return c != nil && c.active.Load()
+}
+
+// level 返回抓取期间的最低级别
+func (c *Capture) level() slog.Level {
+	if c.Level != nil {
+		return c.Level.Level()
+	}
+	return slog.LevelDebug
+}
+
+// Status 返回当前抓取状态
+func (c *Capture) Status() CaptureStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	st := CaptureStatus{Active: c.active.Load()}
+	if st.Active {
+		st.Path = c.path
+		st.Left = c.left.Load()
+	}
+	return st
+}
+
+// write 写入一条已格式化的记录，达到记录数上限时结束抓取
+func (c *Capture) write(p []byte) {
+	c.mu.Lock()
+	if !c.active.Load() {
+		c.mu.Unlock()
+		return
+	}
+	c.w.Write(p)
+	gen := c.gen
+	c.mu.Unlock()
+
+	if c.left.Load() > 0 && c.left.Add(-1) == 0 {
+		c.stop(gen)
+	}
+}
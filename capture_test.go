@@ -0,0 +1,66 @@
+package slogplus
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCapture_Records(t *testing.T) {
+	var main bytes.Buffer
+	c := &Capture{Dir: t.TempDir()}
+	logger := NewLogger(&main, &Options{Capture: c})
+
+	logger.Debug("before")
+	path, err := c.Start(0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Start(0, 1); err != ErrCaptureActive {
+		t.Errorf("重复开始抓取应该返回 ErrCaptureActive: %v", err)
+	}
+
+	logger.Debug("debug during")
+	logger.Info("info during")
+	logger.Debug("last")
+	if c.Active() {
+		t.Fatalf("达到记录数上限后应该自动结束")
+	}
+	logger.Debug("after")
+
+	captured, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"msg=debug during", "msg=info during", "msg=last"} {
+		if !strings.Contains(string(captured), want) {
+			t.Errorf("抓取文件应该包含 %s: %s", want, captured)
+		}
+	}
+	if strings.Contains(string(captured), "before") || strings.Contains(string(captured), "after") {
+		t.Errorf("抓取文件不应该包含抓取前后的记录: %s", captured)
+	}
+
+	if main.String() == "" || strings.Contains(main.String(), "DEBUG") {
+		t.Errorf("主输出只应该包含正常级别的记录: %s", main.String())
+	}
+}
+
+func TestCapture_Duration(t *testing.T) {
+	var buf bytes.Buffer
+	c := &Capture{}
+	done, err := c.StartWriter(&buf, 20*time.Millisecond, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("达到时长后应该自动结束")
+	}
+	if c.Active() {
+		t.Errorf("抓取应该已经结束")
+	}
+}
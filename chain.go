@@ -0,0 +1,25 @@
+package slogplus
+
+import "log/slog"
+
+// Middleware 是包装 Handler 的处理阶段，例如过滤、采样、脱敏、补充属性，
+// 第三方也可以按这个签名实现自己的阶段并通过 Chain 组合
+type Middleware func(next slog.Handler) slog.Handler
+
+// Chain 按顺序组合 Middleware，记录先经过 mws[0]，最后交给 h；nil 的 Middleware 被忽略
+//
+//	h := slogplus.Chain(base,
+//		func(next slog.Handler) slog.Handler { return slogplus.Filter(next, keep) },
+//		func(next slog.Handler) slog.Handler { return slogplus.NewSampler(next, nil) },
+//		func(next slog.Handler) slog.Handler { return slogplus.NewDedup(next, nil) },
+//	)
+//
+// 上面的配置中过滤掉的记录不参与采样，采样保留的记录才参与去重
+func Chain(h slog.Handler, mws ...Middleware) slog.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		if mws[i] != nil {
+			h = mws[i](h)
+		}
+	}
+	return h
+}
@@ -0,0 +1,48 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+// tagMiddleware 为记录添加 stage 属性，用于检查组合顺序
+func tagMiddleware(name string) Middleware {
+	return func(next slog.Handler) slog.Handler {
+		return &stageHandler{Handler: next, name: name}
+	}
+}
+
+// stageHandler 在交给被包装的 Handler 之前添加 stage 属性，只用于单个 Logger，不处理 WithAttrs
+type stageHandler struct {
+	slog.Handler
+	name string
+}
+
+func (h *stageHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.AddAttrs(slog.String("stage", h.name))
+	return h.Handler.Handle(ctx, r)
+}
+
+func TestChain(t *testing.T) {
+	var buf bytes.Buffer
+	h := Chain(New(&buf, &Options{TimeFormat: "-"}),
+		tagMiddleware("first"),
+		nil,
+		tagMiddleware("second"),
+		func(next slog.Handler) slog.Handler {
+			return Filter(next, func(_ context.Context, r slog.Record) bool { return r.Message != "drop" })
+		},
+	)
+	logger := slog.New(h)
+	logger.Info("keep")
+	logger.Info("drop")
+
+	if got := buf.String(); got != "- INFO msg=keep stage=first stage=second\n" {
+		t.Errorf("组合顺序错误: %q", got)
+	}
+	if Chain(h) != h {
+		t.Error("没有 Middleware 时应该返回 h")
+	}
+}
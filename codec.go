@@ -0,0 +1,216 @@
+package slogplus
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"sync"
+)
+
+// Codec 是远程输出使用的压缩算法，OTLP、Sentry 压缩请求体，Kafka 将每批中 key 相同的记录合并后压缩为一条消息
+// slogplus 内置 none、gzip 和 snappy（块格式），其它算法（例如 zstd）可以通过 RegisterCodec 注册:
+//
+//	type zstdCodec struct{ enc *zstd.Encoder }
+//
+//	func (zstdCodec) Name() string { return "zstd" }
+//	func (c zstdCodec) Compress(dst, src []byte) ([]byte, error) { return c.enc.EncodeAll(src, dst), nil }
+//
+//	enc, _ := zstd.NewWriter(nil)
+//	slogplus.RegisterCodec(zstdCodec{enc})
+type Codec interface {
+	// Name 算法名称，同时用作 HTTP 的 Content-Encoding
+	Name() string
+
+	// Compress 将 src 压缩后追加到 dst，必须可以并发调用
+	Compress(dst, src []byte) ([]byte, error)
+}
+
+var (
+	// NoCompression 不压缩
+	NoCompression Codec = noneCodec{}
+
+	// Gzip 使用默认压缩级别的 gzip
+	Gzip Codec = gzipCodec{level: gzip.DefaultCompression}
+
+	// Snappy snappy 块格式，压缩率低于 gzip 但速度快得多
+	Snappy Codec = snappyCodec{}
+)
+
+// codecs 是按名称注册的压缩算法
+var codecs = struct {
+	mu sync.RWMutex
+	m  map[string]Codec
+}{m: map[string]Codec{
+	"none":   NoCompression,
+	"gzip":   Gzip,
+	"snappy": Snappy,
+}}
+
+// RegisterCodec 按名称注册压缩算法，已存在的同名算法会被替换
+func RegisterCodec(c Codec) {
+	codecs.mu.Lock()
+	defer codecs.mu.Unlock()
+	codecs.m[c.Name()] = c
+}
+
+// CodecByName 返回已注册的压缩算法，空字符串返回 NoCompression，
+// 用于从配置文件中按名称选择压缩算法
+func CodecByName(name string) (Codec, error) {
+	if name == "" {
+		return NoCompression, nil
+	}
+	codecs.mu.RLock()
+	defer codecs.mu.RUnlock()
+	if c, ok := codecs.m[name]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("slogplus: 未注册的压缩算法 %q，可以通过 RegisterCodec 注册", name)
+}
+
+// GzipLevel 返回指定压缩级别的 gzip，级别为 gzip.BestSpeed 到 gzip.BestCompression
+func GzipLevel(level int) Codec {
+	return gzipCodec{level: level}
+}
+
+// compress 使用 c 压缩 src，c 为 nil 或 NoCompression 时原样返回，encoding 为 Content-Encoding
+func compress(c Codec, src []byte) (out []byte, encoding string, err error) {
+	if c == nil || c == NoCompression {
+		return src, "", nil
+	}
+	out, err = c.Compress(nil, src)
+	if err != nil {
+		return nil, "", err
+	}
+	return out, c.Name(), nil
+}
+
+type noneCodec struct{}
+
+func (noneCodec) Name() string { return "none" }
+
+func (noneCodec) Compress(dst, src []byte) ([]byte, error) {
+	return append(dst, src...), nil
+}
+
+type gzipCodec struct {
+	level int
+}
+
+// gzipWriters 按压缩级别复用 gzip.Writer
+var gzipWriters sync.Map // map[int]*sync.Pool
+
+func (gzipCodec) Name() string { return "gzip" }
+
+func (c gzipCodec) Compress(dst, src []byte) ([]byte, error) {
+	p, _ := gzipWriters.LoadOrStore(c.level, &sync.Pool{})
+	pool := p.(*sync.Pool)
+
+	buf := bytes.NewBuffer(dst)
+	zw, _ := pool.Get().(*gzip.Writer)
+	if zw == nil {
+		var err error
+		if zw, err = gzip.NewWriterLevel(buf, c.level); err != nil {
+			return nil, err
+		}
+	} else {
+		zw.Reset(buf)
+	}
+	defer pool.Put(zw)
+
+	if _, err := zw.Write(src); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type snappyCodec struct{}
+
+func (snappyCodec) Name() string { return "snappy" }
+
+// snappy 块格式的常量
+const (
+	snappyTagLiteral = 0x00
+	snappyTagCopy1   = 0x01
+	snappyTagCopy2   = 0x02
+	snappyTagCopy4   = 0x03
+
+	snappyBlockSize = 1 << 16 // 每个块独立查找匹配，使偏移量可以用 2 字节表示
+	snappyTableBits = 14
+	snappyMinMatch  = 4
+)
+
+// Compress 按 snappy 块格式压缩：长度的 varint 后跟字面量和复制元素
+func (snappyCodec) Compress(dst, src []byte) ([]byte, error) {
+	dst = binary.AppendUvarint(dst, uint64(len(src)))
+	var table [1 << snappyTableBits]int32
+	for len(src) > 0 {
+		block := src
+		if len(block) > snappyBlockSize {
+			block = block[:snappyBlockSize]
+		}
+		src = src[len(block):]
+		clear(table[:])
+		dst = snappyEncodeBlock(dst, block, &table)
+	}
+	return dst, nil
+}
+
+// snappyEncodeBlock 使用 4 字节哈希表查找重复数据，table 中保存位置 +1，0 表示空
+func snappyEncodeBlock(dst, src []byte, table *[1 << snappyTableBits]int32) []byte {
+	lit := 0 // 尚未输出的字面量的起始位置
+	for i := 0; i+snappyMinMatch <= len(src); {
+		v := binary.LittleEndian.Uint32(src[i:])
+		h := (v * 0x1e35a7bd) >> (32 - snappyTableBits)
+		cand := int(table[h]) - 1
+		table[h] = int32(i + 1)
+		if cand < 0 || binary.LittleEndian.Uint32(src[cand:]) != v {
+			i++
+			continue
+		}
+
+		n := snappyMinMatch
+		for i+n < len(src) && src[cand+n] == src[i+n] {
+			n++
+		}
+		dst = snappyAppendLiteral(dst, src[lit:i])
+		dst = snappyAppendCopy(dst, i-cand, n)
+		i += n
+		lit = i
+	}
+	return snappyAppendLiteral(dst, src[lit:])
+}
+
+func snappyAppendLiteral(dst, lit []byte) []byte {
+	if len(lit) == 0 {
+		return dst
+	}
+	switch n := uint32(len(lit) - 1); {
+	case n < 60:
+		dst = append(dst, byte(n)<<2|snappyTagLiteral)
+	case n < 1<<8:
+		dst = append(dst, 60<<2|snappyTagLiteral, byte(n))
+	case n < 1<<16:
+		dst = append(dst, 61<<2|snappyTagLiteral, byte(n), byte(n>>8))
+	default:
+		dst = append(dst, 62<<2|snappyTagLiteral, byte(n), byte(n>>8), byte(n>>16))
+	}
+	return append(dst, lit...)
+}
+
+// snappyAppendCopy 输出复制元素，长度超过 64 时拆成多个
+func snappyAppendCopy(dst []byte, offset, n int) []byte {
+	for n > 0 {
+		l := min(n, 64)
+		if l >= 4 && l <= 11 && offset < 2048 {
+			dst = append(dst, byte(offset>>8)<<5|byte(l-4)<<2|snappyTagCopy1, byte(offset))
+		} else {
+			dst = append(dst, byte(l-1)<<2|snappyTagCopy2, byte(offset), byte(offset>>8))
+		}
+		n -= l
+	}
+	return dst
+}
@@ -0,0 +1,174 @@
+package slogplus
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+// errSnappyCorrupt 表示 snappy 数据损坏
+var errSnappyCorrupt = errors.New("slogplus: snappy 数据损坏")
+
+// snappyDecode 解压 snappy 块格式的数据
+func snappyDecode(src []byte) ([]byte, error) {
+	size, n := binary.Uvarint(src)
+	if n <= 0 || size > 1<<32 {
+		return nil, errSnappyCorrupt
+	}
+	src = src[n:]
+	dst := make([]byte, 0, size)
+	for len(src) > 0 {
+		tag := src[0]
+		var length, offset int
+		switch tag & 0x03 {
+		case snappyTagLiteral:
+			length = int(tag >> 2)
+			src = src[1:]
+			if length >= 60 {
+				extra := length - 59
+				if len(src) < extra {
+					return nil, errSnappyCorrupt
+				}
+				length = 0
+				for i := extra - 1; i >= 0; i-- {
+					length = length<<8 | int(src[i])
+				}
+				src = src[extra:]
+			}
+			length++
+			if len(src) < length {
+				return nil, errSnappyCorrupt
+			}
+			dst = append(dst, src[:length]...)
+			src = src[length:]
+			continue
+		case snappyTagCopy1:
+			if len(src) < 2 {
+				return nil, errSnappyCorrupt
+			}
+			length = 4 + int(tag>>2&0x07)
+			offset = int(tag&0xe0)<<3 | int(src[1])
+			src = src[2:]
+		case snappyTagCopy2:
+			if len(src) < 3 {
+				return nil, errSnappyCorrupt
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint16(src[1:]))
+			src = src[3:]
+		case snappyTagCopy4:
+			if len(src) < 5 {
+				return nil, errSnappyCorrupt
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint32(src[1:]))
+			src = src[5:]
+		}
+		if offset <= 0 || offset > len(dst) {
+			return nil, errSnappyCorrupt
+		}
+		// 复制的区域可以与输出重叠，需要逐字节复制
+		start := len(dst) - offset
+		for i := 0; i < length; i++ {
+			dst = append(dst, dst[start+i])
+		}
+	}
+	if uint64(len(dst)) != size {
+		return nil, errSnappyCorrupt
+	}
+	return dst, nil
+}
+
+func TestSnappy_Decode(t *testing.T) {
+	// 字面量 "abc" 后跟偏移 3、长度 6 的复制
+	got, err := snappyDecode([]byte{0x09, 0x08, 'a', 'b', 'c', 0x16, 0x03, 0x00})
+	if err != nil || string(got) != "abcabcabc" {
+		t.Errorf("解压错误: %q %v", got, err)
+	}
+}
+
+func TestSnappy_RoundTrip(t *testing.T) {
+	random := make([]byte, 200<<10)
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := range random {
+		random[i] = byte(r.UintN(256))
+	}
+	logs := strings.Repeat(`{"time":"2025-11-14T14:03:14Z","level":"INFO","msg":"request","status":200}`+"\n", 3000)
+
+	for name, src := range map[string][]byte{
+		"empty":  {},
+		"short":  []byte("abc"),
+		"repeat": bytes.Repeat([]byte{'x'}, 1000),
+		"random": random,
+		"logs":   []byte(logs),
+	} {
+		out, err := Snappy.Compress([]byte("prefix"), src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(out, []byte("prefix")) {
+			t.Fatalf("%s: 应该追加到 dst", name)
+		}
+		got, err := snappyDecode(out[len("prefix"):])
+		if err != nil || !bytes.Equal(got, src) {
+			t.Errorf("%s: 解压结果不一致: %v", name, err)
+		}
+		if name == "logs" && len(out) > len(src)/5 {
+			t.Errorf("重复的日志应该被有效压缩: %d -> %d", len(src), len(out))
+		}
+	}
+}
+
+func TestGzip(t *testing.T) {
+	src := []byte(strings.Repeat("hello slogplus ", 100))
+	for _, c := range []Codec{Gzip, GzipLevel(gzip.BestSpeed), Gzip} {
+		out, err := c.Compress(nil, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(out))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _ := io.ReadAll(zr)
+		if !bytes.Equal(got, src) {
+			t.Error("gzip 解压结果不一致")
+		}
+	}
+}
+
+type upperCodec struct{}
+
+func (upperCodec) Name() string { return "upper" }
+func (upperCodec) Compress(dst, src []byte) ([]byte, error) {
+	return append(dst, bytes.ToUpper(src)...), nil
+}
+
+func TestCodecByName(t *testing.T) {
+	for name, want := range map[string]Codec{"": NoCompression, "none": NoCompression, "gzip": Gzip, "snappy": Snappy} {
+		if c, err := CodecByName(name); err != nil || c != want {
+			t.Errorf("%q: %v %v", name, c, err)
+		}
+	}
+	if _, err := CodecByName("zstd"); err == nil {
+		t.Error("未注册的算法应该返回错误")
+	}
+
+	RegisterCodec(upperCodec{})
+	c, err := CodecByName("upper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, encoding, _ := compress(c, []byte("abc"))
+	if string(out) != "ABC" || encoding != "upper" {
+		t.Errorf("应该使用注册的算法: %s %s", out, encoding)
+	}
+	if out, encoding, _ := compress(nil, []byte("abc")); string(out) != "abc" || encoding != "" {
+		t.Error("没有设置算法时不应该压缩")
+	}
+}
@@ -0,0 +1,48 @@
+package slogplus
+
+import (
+	"io"
+	"log/slog"
+	"os"
+)
+
+// ANSI 颜色控制码
+const (
+	ansiReset  = "\x1b[0m"
+	ansiDim    = "\x1b[2m"
+	ansiGray   = "\x1b[90m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// levelColor 返回级别对应的颜色
+// DEBUG 灰色，INFO 绿色，WARN 黄色，ERROR 红色
+func levelColor(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return ansiRed
+	case level >= slog.LevelWarn:
+		return ansiYellow
+	case level >= slog.LevelInfo:
+		return ansiGreen
+	default:
+		return ansiGray
+	}
+}
+
+// isTerminal 判断输出是否为终端
+func isTerminal(w io.Writer) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
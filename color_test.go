@@ -0,0 +1,44 @@
+package slogplus
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestHandler_Color(t *testing.T) {
+	var buf bytes.Buffer
+	handler := New(&buf, &Options{Color: true, Level: slog.LevelDebug})
+	if handler.color {
+		t.Fatalf("输出不是终端时不应该启用颜色")
+	}
+
+	handler.color = true // 模拟终端输出
+	logger := slog.New(handler).With("k", "v")
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	output := buf.String()
+	for _, want := range []string{
+		ansiGray + "DEBUG" + ansiReset,
+		ansiGreen + "INFO" + ansiReset,
+		ansiYellow + "WARN" + ansiReset,
+		ansiRed + "ERROR" + ansiReset,
+		ansiDim,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("输出应该包含 %q: %q", want, output)
+		}
+	}
+}
+
+func TestHandler_NoColor(t *testing.T) {
+	var buf bytes.Buffer
+	NewLogger(&buf, &Options{Color: true}).Info("test")
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("非终端输出不应该包含颜色控制码: %q", buf.String())
+	}
+}
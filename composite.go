@@ -0,0 +1,90 @@
+package slogplus
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// maxCompositeDepth 是文本类格式展开嵌套 map 和切片的最大层数，超过后输出为 {...} 或 [...]
+const maxCompositeDepth = 5
+
+// composite 将 map 和切片转换为文本类格式的输出值：
+// map 按键排序后转换为分组，切片和数组输出为 [a,b,c]，元素递归处理
+// 实现了 error、fmt.Stringer 的值以及 []byte 保持原样，返回 false 表示 v 不是 map 或切片
+func (h *Handler) composite(v slog.Value, depth int) (slog.Value, bool) {
+	if v.Kind() != slog.KindAny || isNilValue(v) {
+		return v, false
+	}
+	x := v.Any()
+	switch x.(type) {
+	case error, fmt.Stringer, []byte:
+		return v, false
+	}
+
+	rv := reflect.ValueOf(x)
+	switch rv.Kind() {
+	case reflect.Map:
+		if depth >= maxCompositeDepth {
+			return slog.StringValue("{...}"), true
+		}
+		if rv.Len() == 0 {
+			return slog.StringValue("{}"), true
+		}
+		attrs := make([]slog.Attr, 0, rv.Len())
+		for it := rv.MapRange(); it.Next(); {
+			attrs = append(attrs, slog.Attr{
+				Key:   fmt.Sprint(it.Key().Interface()),
+				Value: h.compositeElem(it.Value().Interface(), depth+1),
+			})
+		}
+		slices.SortFunc(attrs, func(a, b slog.Attr) int { return strings.Compare(a.Key, b.Key) })
+		return slog.GroupValue(attrs...), true
+	case reflect.Slice, reflect.Array:
+		if depth >= maxCompositeDepth {
+			return slog.StringValue("[...]"), true
+		}
+		buf := []byte{'['}
+		for i := 0; i < rv.Len(); i++ {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = h.appendValue(buf, h.compositeElem(rv.Index(i).Interface(), depth+1))
+		}
+		return slog.StringValue(string(append(buf, ']'))), true
+	}
+	return v, false
+}
+
+// compositeElem 返回 map 或切片元素的输出值
+func (h *Handler) compositeElem(x any, depth int) slog.Value {
+	v := slog.AnyValue(x).Resolve()
+	if c, ok := h.composite(v, depth); ok {
+		return c
+	}
+	return v
+}
+
+// mapGroup 将非空的 map 转换为按键排序的分组，嵌套的 map 递归转换，其余元素保持原样，
+// 用于脱敏在编码前看到 map 中的键；返回 false 表示 v 不是 map
+func mapGroup(v slog.Value, depth int) (slog.Value, bool) {
+	if v.Kind() != slog.KindAny || isNilValue(v) || depth >= maxCompositeDepth {
+		return v, false
+	}
+	rv := reflect.ValueOf(v.Any())
+	if rv.Kind() != reflect.Map || rv.Len() == 0 {
+		return v, false
+	}
+	attrs := make([]slog.Attr, 0, rv.Len())
+	for it := rv.MapRange(); it.Next(); {
+		ev := slog.AnyValue(it.Value().Interface()).Resolve()
+		if g, ok := mapGroup(ev, depth+1); ok {
+			ev = g
+		}
+		attrs = append(attrs, slog.Attr{Key: fmt.Sprint(it.Key().Interface()), Value: ev})
+	}
+	slices.SortFunc(attrs, func(a, b slog.Attr) int { return strings.Compare(a.Key, b.Key) })
+	return slog.GroupValue(attrs...), true
+}
@@ -0,0 +1,35 @@
+package slogplus
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestComposite(t *testing.T) {
+	m := map[string]any{"b": 2, "a": []int{1, 2}, "n": map[int]string{1: "x"}, "e": map[string]int{}}
+	for _, tt := range []struct {
+		opts *Options
+		want string
+	}{
+		{&Options{TimeFormat: "-"}, "- INFO msg=x m={a=[1,2] b=2 e={} n={1=x}} s=[a,b] bs=[104 105]\n"},
+		{&Options{TimeFormat: "-", Logfmt: true}, "time=- level=INFO msg=x m.a=[1,2] m.b=2 m.e={} m.n.1=x s=[a,b] bs=\"[104 105]\"\n"},
+	} {
+		var buf bytes.Buffer
+		NewLogger(&buf, tt.opts).Info("x", "m", m, "s", []string{"a", "b"}, "bs", []byte("hi"))
+		if got := buf.String(); got != tt.want {
+			t.Errorf("输出错误:\n got %q\nwant %q", got, tt.want)
+		}
+	}
+}
+
+func TestComposite_Depth(t *testing.T) {
+	cyclic := map[string]any{"k": 1}
+	cyclic["self"] = cyclic
+
+	var buf bytes.Buffer
+	NewLogger(&buf, &Options{TimeFormat: "-"}).Info("x", "m", cyclic)
+	want := "- INFO msg=x m={k=1 self={k=1 self={k=1 self={k=1 self={k=1 self={...}}}}}}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("应该在最大层数处截断:\n got %q\nwant %q", got, want)
+	}
+}
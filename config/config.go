@@ -0,0 +1,320 @@
+// Package config 从配置文件构建 slogplus 的 Handler
+//
+// 配置文件描述全局级别、格式、按包设置的级别和若干输出（终端、文件及其切割方式），
+// 大型部署中可以只修改配置文件而不改代码。JSON 直接支持，YAML 和 TOML 通过传入对应库的
+// Unmarshal 函数支持，本包不引入第三方依赖：
+//
+//	cfg, err := config.Load("log.yaml", yaml.Unmarshal)
+//	if err != nil {
+//		return err
+//	}
+//	h, closer, err := cfg.Build()
+//	if err != nil {
+//		return err
+//	}
+//	defer closer.Close()
+//	slog.SetDefault(slog.New(h))
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/IAmMrChen/slogplus"
+)
+
+// Config 是日志配置文件的内容，字段同时带有 json、yaml 和 toml 标签
+//
+//	level: info
+//	format: console
+//	packages:
+//	  github.com/me/app/db: debug
+//	redact_keys: [password, token]
+//	sinks:
+//	  - type: stderr
+//	  - type: file
+//	    path: /var/log/app.log
+//	    format: json
+//	    level: debug
+//	    rotation: daily
+//	    max_age: 168h
+type Config struct {
+	// Level 全局日志级别，例如 debug、WARN、INFO+2，默认 info
+	Level string `json:"level" yaml:"level" toml:"level"`
+
+	// Format 输出格式，例如 text、json、console，默认 text，见 slogplus.ParseFormat
+	Format string `json:"format" yaml:"format" toml:"format"`
+
+	// TimeFormat 时间格式，Go 时间布局或 rfc3339、rfc3339nano、datetime
+	TimeFormat string `json:"time_format" yaml:"time_format" toml:"time_format"`
+
+	// AddSource 是否输出源代码位置
+	AddSource bool `json:"add_source" yaml:"add_source" toml:"add_source"`
+
+	// Color 是否为终端输出添加颜色
+	Color bool `json:"color" yaml:"color" toml:"color"`
+
+	// Packages 按包设置的级别，键为包路径前缀，见 slogplus.NewPackageLevels
+	Packages map[string]string `json:"packages" yaml:"packages" toml:"packages"`
+
+	// RedactKeys 需要脱敏的属性名
+	RedactKeys []string `json:"redact_keys" yaml:"redact_keys" toml:"redact_keys"`
+
+	// Sinks 输出列表，为空时输出到 stdout
+	Sinks []Sink `json:"sinks" yaml:"sinks" toml:"sinks"`
+}
+
+// Sink 是一个输出，未设置的级别和格式使用全局配置
+type Sink struct {
+	// Type 输出类型：stdout、stderr 或 file
+	Type string `json:"type" yaml:"type" toml:"type"`
+
+	// Path 文件路径，Type 为 file 时必填
+	Path string `json:"path" yaml:"path" toml:"path"`
+
+	// Level 该输出的最低级别，只能比全局级别更严格
+	Level string `json:"level" yaml:"level" toml:"level"`
+
+	// Format 该输出的格式
+	Format string `json:"format" yaml:"format" toml:"format"`
+
+	// Rotation 文件切割周期：none（默认）、daily 或 hourly
+	Rotation string `json:"rotation" yaml:"rotation" toml:"rotation"`
+
+	// MaxAge 切割后的文件保留时长，例如 168h，为空表示全部保留
+	MaxAge string `json:"max_age" yaml:"max_age" toml:"max_age"`
+
+	// Symlink 是否在原路径创建指向当前文件的符号链接
+	Symlink bool `json:"symlink" yaml:"symlink" toml:"symlink"`
+}
+
+// Unmarshaler 将配置文件内容解码到 v，与 json.Unmarshal、yaml.Unmarshal、toml.Unmarshal 的签名相同
+type Unmarshaler func(data []byte, v any) error
+
+var rotations = map[string]slogplus.Rotation{
+	"":       slogplus.RotateNone,
+	"none":   slogplus.RotateNone,
+	"daily":  slogplus.RotateDaily,
+	"hourly": slogplus.RotateHourly,
+}
+
+var timeFormats = map[string]string{
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"datetime":    time.DateTime,
+}
+
+// Parse 解码配置内容并检查其中的级别、格式和输出，unmarshal 为 nil 时按 JSON 解码
+func Parse(data []byte, unmarshal Unmarshaler) (*Config, error) {
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+	cfg := new(Config)
+	if err := unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("config: 解码失败: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Load 读取并解析配置文件，unmarshal 为 nil 时只支持 .json 文件，
+// 其它扩展名需要传入对应的解码函数，例如 yaml.Unmarshal 或 toml.Unmarshal
+func Load(path string, unmarshal Unmarshaler) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if unmarshal == nil {
+		if err := checkExt(path); err != nil {
+			return nil, err
+		}
+	}
+	return Parse(data, unmarshal)
+}
+
+// checkExt 检查没有传入解码函数时文件能否按 JSON 解码
+func checkExt(path string) error {
+	if ext := strings.ToLower(filepath.Ext(path)); ext != ".json" {
+		return fmt.Errorf("config: 解码 %s 文件需要传入对应的 Unmarshal 函数", ext)
+	}
+	return nil
+}
+
+// Validate 检查配置中的级别、格式、切割周期和输出，返回所有错误
+func (c *Config) Validate() error {
+	var errs []error
+	if _, err := parseLevel(c.Level); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := slogplus.ParseFormat(c.Format); err != nil {
+		errs = append(errs, err)
+	}
+	if _, ok := timeFormats[strings.ToLower(c.TimeFormat)]; !ok {
+		if err := (&slogplus.Options{TimeFormat: c.TimeFormat}).Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for pkg, level := range c.Packages {
+		if _, err := slogplus.ParseLevel(level); err != nil {
+			errs = append(errs, fmt.Errorf("config: 包 %s 的级别无效: %w", pkg, err))
+		}
+	}
+	for i, s := range c.Sinks {
+		if err := s.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("config: 第 %d 个输出: %w", i+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Sink) validate() error {
+	var errs []error
+	switch strings.ToLower(s.Type) {
+	case "stdout", "stderr":
+	case "file":
+		if s.Path == "" {
+			errs = append(errs, errors.New("file 类型必须设置 path"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("未知的输出类型 %q", s.Type))
+	}
+	if s.Level != "" {
+		if _, err := slogplus.ParseLevel(s.Level); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if s.Format != "" {
+		if _, err := slogplus.ParseFormat(s.Format); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if _, ok := rotations[strings.ToLower(s.Rotation)]; !ok {
+		errs = append(errs, fmt.Errorf("未知的切割周期 %q", s.Rotation))
+	}
+	if s.MaxAge != "" {
+		if _, err := time.ParseDuration(s.MaxAge); err != nil {
+			errs = append(errs, fmt.Errorf("max_age 无效: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// parseLevel 解析全局级别，空字符串表示 Info
+func parseLevel(s string) (slog.Level, error) {
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	return slogplus.ParseLevel(s)
+}
+
+// Build 按配置创建 Handler，多个输出通过 slogplus.Fanout 组合，设置了 Packages 时
+// 外层按调用方所在的包过滤；返回的 io.Closer 关闭打开的文件，应在程序退出前调用
+func (c *Config) Build() (slog.Handler, io.Closer, error) {
+	if err := c.Validate(); err != nil {
+		return nil, nil, err
+	}
+	level, _ := parseLevel(c.Level)
+
+	// 按包过滤时全局级别由外层决定，输出只按自身的级别过滤
+	floor := level
+	if len(c.Packages) > 0 {
+		floor = slog.Level(math.MinInt)
+	}
+
+	sinks := c.Sinks
+	if len(sinks) == 0 {
+		sinks = []Sink{{Type: "stdout"}}
+	}
+	var files closers
+	handlers := make([]slog.Handler, 0, len(sinks))
+	for _, s := range sinks {
+		out, err := s.open()
+		if err != nil {
+			files.Close()
+			return nil, nil, err
+		}
+		if f, ok := out.(*slogplus.FileWriter); ok {
+			files = append(files, f)
+		}
+		handlers = append(handlers, slogplus.New(out, c.options(s, floor)))
+	}
+
+	var h slog.Handler
+	if len(handlers) == 1 {
+		h = handlers[0]
+	} else {
+		h = slogplus.Fanout(handlers...)
+	}
+	if len(c.Packages) > 0 {
+		levels := make(map[string]slog.Level, len(c.Packages))
+		for pkg, name := range c.Packages {
+			levels[pkg], _ = slogplus.ParseLevel(name)
+		}
+		h = slogplus.NewPackageLevelHandler(h, slogplus.NewPackageLevels(level, levels))
+	}
+	return h, files, nil
+}
+
+// options 返回输出 s 使用的 Options
+func (c *Config) options(s Sink, floor slog.Level) *slogplus.Options {
+	opts := &slogplus.Options{
+		Level:      floor,
+		AddSource:  c.AddSource,
+		Color:      c.Color,
+		RedactKeys: c.RedactKeys,
+		TimeFormat: c.TimeFormat,
+	}
+	if layout, ok := timeFormats[strings.ToLower(c.TimeFormat)]; ok {
+		opts.TimeFormat = layout
+	}
+	if s.Level != "" {
+		level, _ := slogplus.ParseLevel(s.Level)
+		opts.Level = max(level, floor)
+	}
+	format := c.Format
+	if s.Format != "" {
+		format = s.Format
+	}
+	opts.Format, _ = slogplus.ParseFormat(format)
+	return opts
+}
+
+// open 打开输出
+func (s *Sink) open() (io.Writer, error) {
+	switch strings.ToLower(s.Type) {
+	case "stderr":
+		return os.Stderr, nil
+	case "file":
+		maxAge, _ := time.ParseDuration(s.MaxAge)
+		return slogplus.NewFileWriter(s.Path, &slogplus.FileOptions{
+			Rotation: rotations[strings.ToLower(s.Rotation)],
+			MaxAge:   maxAge,
+			Symlink:  s.Symlink,
+		})
+	default:
+		return os.Stdout, nil
+	}
+}
+
+// closers 依次关闭所有文件，返回合并后的错误
+type closers []io.Closer
+
+func (cs closers) Close() error {
+	var errs []error
+	for _, c := range cs {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse([]byte(`{
+		"level": "warn",
+		"format": "json",
+		"packages": {"github.com/me/app/db": "debug"},
+		"sinks": [{"type": "file", "path": "/tmp/app.log", "rotation": "daily", "max_age": "168h"}]
+	}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Level != "warn" || cfg.Format != "json" || cfg.Packages["github.com/me/app/db"] != "debug" {
+		t.Errorf("配置解析错误: %+v", cfg)
+	}
+	if len(cfg.Sinks) != 1 || cfg.Sinks[0].Rotation != "daily" || cfg.Sinks[0].MaxAge != "168h" {
+		t.Errorf("输出解析错误: %+v", cfg.Sinks)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := &Config{
+		Level:      "loud",
+		Format:     "xml",
+		TimeFormat: "%Y-%m-%d",
+		Packages:   map[string]string{"db": "verbose"},
+		Sinks: []Sink{
+			{Type: "file"},
+			{Type: "kafka"},
+			{Type: "stdout", Rotation: "weekly", MaxAge: "7d"},
+		},
+	}
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("无效的配置应该返回错误")
+	}
+	for _, want := range []string{"loud", "xml", "%Y-%m-%d", "verbose", "第 1 个输出: file 类型必须设置 path", `"kafka"`, `"weekly"`, "max_age"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("错误应该包含 %q: %v", want, err)
+		}
+	}
+	if _, err := Parse([]byte(`{"level": "loud"}`), nil); err == nil {
+		t.Error("Parse 应该检查配置")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.yaml")
+	os.WriteFile(path, []byte("level: debug"), 0o644)
+
+	if _, err := Load(path, nil); err == nil || !strings.Contains(err.Error(), ".yaml") {
+		t.Errorf("YAML 文件没有传入解码函数时应该返回错误: %v", err)
+	}
+
+	// 用 JSON 模拟第三方库的解码函数
+	var got []byte
+	cfg, err := Load(path, func(data []byte, v any) error {
+		got = data
+		return json.Unmarshal([]byte(`{"level": "debug"}`), v)
+	})
+	if err != nil || string(got) != "level: debug" || cfg.Level != "debug" {
+		t.Errorf("Load() = %+v, %v", cfg, err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	all := filepath.Join(dir, "all.log")
+	errs := filepath.Join(dir, "error.log")
+	cfg := &Config{
+		Level:      "info",
+		Format:     "json",
+		RedactKeys: []string{"password"},
+		Sinks: []Sink{
+			{Type: "file", Path: all, Format: "text"},
+			{Type: "file", Path: errs, Level: "error"},
+		},
+	}
+	h, closer, err := cfg.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(h)
+	logger.Debug("debug")
+	logger.Info("info", "password", "hunter2")
+	logger.Error("error")
+	if err := closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(all)
+	out := string(data)
+	if strings.Contains(out, "msg=debug") || !strings.Contains(out, "msg=info") || !strings.Contains(out, "msg=error") {
+		t.Errorf("文本输出应该只包含 info 及以上的记录: %s", out)
+	}
+	if strings.Contains(out, "hunter2") {
+		t.Errorf("属性应该被脱敏: %s", out)
+	}
+	data, _ = os.ReadFile(errs)
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 1 || !strings.Contains(lines[0], `"msg":"error"`) {
+		t.Errorf("错误文件应该只包含一条 JSON 记录: %s", data)
+	}
+}
+
+func TestBuild_Packages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	cfg := &Config{
+		Level:    "warn",
+		Packages: map[string]string{"github.com/IAmMrChen/slogplus/config": "debug"},
+		Sinks:    []Sink{{Type: "file", Path: path}},
+	}
+	h, closer, err := cfg.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closer.Close()
+
+	if !h.Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatal("包级别低于全局级别时应该启用")
+	}
+	slog.New(h).Debug("from config")
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "msg=from config") {
+		t.Errorf("按包设置的级别应该生效: %s", data)
+	}
+}
@@ -0,0 +1,319 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/IAmMrChen/slogplus"
+)
+
+// ReloadMessage 是重新加载配置后输出的记录的消息
+const ReloadMessage = "日志配置已重新加载"
+
+// ReloadFailedMessage 是重新加载配置失败时输出的记录的消息
+const ReloadFailedMessage = "日志配置重新加载失败"
+
+// errWatcherClosed 是 Watcher 关闭后写入记录返回的错误
+var errWatcherClosed = errors.New("config: Watcher 已关闭")
+
+// WatchOptions 定义 Watch 的配置
+type WatchOptions struct {
+	// Unmarshal 解码配置文件的函数，见 Load
+	Unmarshal Unmarshaler
+
+	// Interval 检查文件是否变化的间隔，默认 1 秒
+	Interval time.Duration
+
+	// OnError 重新加载失败时调用，此时继续使用原配置；
+	// 默认通过原配置输出一条 ReloadFailedMessage 记录
+	OnError func(error)
+}
+
+// Watcher 监视配置文件，文件内容变化时按新配置重建 Handler 并原子地替换，
+// 级别、格式和输出都会更新，无需重启进程
+//
+// 为了不引入 fsnotify 等依赖，Watcher 按 Interval 轮询文件的修改时间和大小，
+// 变化后比较文件内容，内容相同时不会重新加载；
+// Kubernetes ConfigMap 这类通过替换符号链接更新的文件同样适用
+type Watcher struct {
+	path string
+	opts WatchOptions
+	cur  atomic.Pointer[tree]
+
+	mu      sync.Mutex // 串行化重新加载
+	data    []byte     // 当前配置的文件内容
+	modTime time.Time
+	size    int64
+	closed  atomic.Bool
+
+	done       chan struct{}
+	wg         sync.WaitGroup
+	unregister func()
+}
+
+// tree 是按一份配置构建的 Handler 及其打开的文件
+type tree struct {
+	cfg    *Config
+	h      slog.Handler
+	closer io.Closer
+
+	// 写入记录时持有读锁，替换后持有写锁关闭文件，保证正在写入的记录不会写到已关闭的文件
+	mu     sync.RWMutex
+	closed bool
+}
+
+// Watch 加载配置文件并开始监视，首次加载失败时返回错误
+//
+//	w, err := config.Watch("log.yaml", &config.WatchOptions{Unmarshal: yaml.Unmarshal})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer w.Close()
+//	slog.SetDefault(slog.New(w.Handler()))
+func Watch(path string, opts *WatchOptions) (*Watcher, error) {
+	w := &Watcher{path: path, done: make(chan struct{})}
+	if opts != nil {
+		w.opts = *opts
+	}
+	if w.opts.Interval <= 0 {
+		w.opts.Interval = time.Second
+	}
+	if err := w.load(false); err != nil {
+		return nil, err
+	}
+
+	w.unregister = slogplus.RegisterReloader(w.Reload)
+	w.wg.Add(1)
+	go w.run()
+	return w, nil
+}
+
+// Handler 返回始终使用最新配置的 Handler，通过 WithAttrs、WithGroup 派生的 Handler 同样会跟随配置更新
+func (w *Watcher) Handler() slog.Handler {
+	return &handler{w: w}
+}
+
+// Config 返回当前生效的配置，不应修改
+func (w *Watcher) Config() *Config {
+	return w.cur.Load().cfg
+}
+
+// Reload 立即重新加载配置文件，即使内容没有变化也会重建 Handler 并重新打开文件；
+// 失败时继续使用原配置。Watcher 通过 slogplus.RegisterReloader 注册了 Reload，
+// slogplus.HandleSignals 收到 SIGHUP 时会调用
+func (w *Watcher) Reload() error {
+	return w.load(true)
+}
+
+// Close 停止监视并关闭打开的文件，之后写入的记录返回错误
+func (w *Watcher) Close() error {
+	if !w.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	w.unregister()
+	close(w.done)
+	w.wg.Wait()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.cur.Load().close()
+}
+
+func (w *Watcher) run() {
+	defer w.wg.Done()
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.load(false); err != nil {
+				w.fail(err)
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// load 在文件变化或 force 为 true 时重新加载配置
+func (w *Watcher) load(force bool) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed.Load() {
+		return errWatcherClosed
+	}
+
+	fi, err := os.Stat(w.path)
+	if err != nil {
+		return err
+	}
+	old := w.cur.Load()
+	if old != nil && !force && fi.ModTime().Equal(w.modTime) && fi.Size() == w.size {
+		return nil
+	}
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return err
+	}
+	w.modTime, w.size = fi.ModTime(), fi.Size()
+	if old != nil && !force && bytes.Equal(data, w.data) {
+		return nil
+	}
+
+	cfg, err := w.parse(data)
+	if err != nil {
+		return err
+	}
+	h, closer, err := cfg.Build()
+	if err != nil {
+		return err
+	}
+	w.data = data
+	t := &tree{cfg: cfg, h: h, closer: closer}
+	w.cur.Store(t)
+	if old == nil {
+		return nil
+	}
+
+	old.close()
+	ctx := context.Background()
+	if h.Enabled(ctx, slog.LevelInfo) {
+		level, _ := parseLevel(cfg.Level)
+		format := cfg.Format
+		if format == "" {
+			format = "text"
+		}
+		r := slog.NewRecord(time.Now(), slog.LevelInfo, ReloadMessage, 0)
+		r.AddAttrs(slog.String("path", w.path), slog.Group("config",
+			slog.String("level", level.String()),
+			slog.String("format", format),
+			slog.Int("sinks", max(len(cfg.Sinks), 1)),
+		))
+		t.handle(ctx, r)
+	}
+	return nil
+}
+
+// parse 按文件扩展名或 Unmarshal 解码配置
+func (w *Watcher) parse(data []byte) (*Config, error) {
+	if w.opts.Unmarshal == nil {
+		if err := checkExt(w.path); err != nil {
+			return nil, err
+		}
+	}
+	return Parse(data, w.opts.Unmarshal)
+}
+
+// fail 报告后台重新加载的错误
+func (w *Watcher) fail(err error) {
+	if w.opts.OnError != nil {
+		w.opts.OnError(err)
+		return
+	}
+	t := w.cur.Load()
+	ctx := context.Background()
+	if t.h.Enabled(ctx, slog.LevelError) {
+		r := slog.NewRecord(time.Now(), slog.LevelError, ReloadFailedMessage, 0)
+		r.AddAttrs(slog.String("path", w.path), slog.String("error", err.Error()))
+		t.handle(ctx, r)
+	}
+}
+
+// handle 在读锁内写入一条记录
+func (t *tree) handle(ctx context.Context, r slog.Record) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.closed {
+		return errWatcherClosed
+	}
+	return t.h.Handle(ctx, r)
+}
+
+// close 等待正在写入的记录完成后关闭文件
+func (t *tree) close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	return t.closer.Close()
+}
+
+// handler 是 Watcher.Handler 返回的 Handler，ops 记录派生时的 WithAttrs 和 WithGroup，
+// 配置更新后在新的 Handler 上重放一次并缓存
+type handler struct {
+	w     *Watcher
+	ops   []func(slog.Handler) slog.Handler
+	cache atomic.Pointer[derived]
+}
+
+// derived 是在某份配置上重放 ops 得到的 Handler
+type derived struct {
+	t *tree
+	h slog.Handler
+}
+
+// resolve 返回在 t 上重放 ops 得到的 Handler
+func (h *handler) resolve(t *tree) slog.Handler {
+	if len(h.ops) == 0 {
+		return t.h
+	}
+	if d := h.cache.Load(); d != nil && d.t == t {
+		return d.h
+	}
+	nh := t.h
+	for _, op := range h.ops {
+		nh = op(nh)
+	}
+	h.cache.Store(&derived{t: t, h: nh})
+	return nh
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.resolve(h.w.cur.Load()).Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	for {
+		t := h.w.cur.Load()
+		t.mu.RLock()
+		if t.closed {
+			t.mu.RUnlock()
+			if h.w.closed.Load() {
+				return errWatcherClosed
+			}
+			continue // 已被替换，使用新的配置
+		}
+		err := h.resolve(t).Handle(ctx, r)
+		t.mu.RUnlock()
+		return err
+	}
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+}
+
+func (h *handler) with(op func(slog.Handler) slog.Handler) *handler {
+	ops := make([]func(slog.Handler) slog.Handler, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &handler{w: h.w, ops: append(ops, op)}
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeConfig 写入 JSON 配置，输出到 out
+func writeConfig(t *testing.T, path, out, level, format string) {
+	t.Helper()
+	data := fmt.Sprintf(`{"level": %q, "format": %q, "sinks": [{"type": "file", "path": %q}]}`, level, format, out)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitReload 等待 Watcher 替换掉 old
+func waitReload(t *testing.T, w *Watcher, old *tree) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for w.cur.Load() == old {
+		if time.Now().After(deadline) {
+			t.Fatal("配置没有重新加载")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.json")
+	first, second := filepath.Join(dir, "first.log"), filepath.Join(dir, "second.log")
+	writeConfig(t, path, first, "info", "text")
+
+	w, err := Watch(path, &WatchOptions{Interval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	logger := slog.New(w.Handler()).With("app", "demo").WithGroup("req")
+	logger.Debug("dropped")
+	logger.Info("before", "id", 1)
+
+	old := w.cur.Load()
+	writeConfig(t, path, second, "debug", "json")
+	waitReload(t, w, old)
+	logger.Debug("after", "id", 2)
+
+	data, _ := os.ReadFile(first)
+	if out := string(data); strings.Contains(out, "dropped") || !strings.Contains(out, "app=demo msg=before req.id=1") {
+		t.Errorf("重新加载前应该使用原配置: %s", out)
+	}
+	data, _ = os.ReadFile(second)
+	out := string(data)
+	if !strings.Contains(out, `"msg":"`+ReloadMessage+`"`) || !strings.Contains(out, `"config":{"level":"DEBUG","format":"json","sinks":1}`) {
+		t.Errorf("重新加载后应该输出一条记录: %s", out)
+	}
+	if !strings.Contains(out, `"msg":"after","app":"demo","req.id":2`) {
+		t.Errorf("派生的 Logger 应该使用新的级别、格式和输出: %s", out)
+	}
+	if w.Config().Format != "json" {
+		t.Errorf("Config() = %+v", w.Config())
+	}
+}
+
+func TestWatch_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.json")
+	out := filepath.Join(dir, "app.log")
+	writeConfig(t, path, out, "info", "text")
+
+	var mu sync.Mutex
+	var errs []error
+	w, err := Watch(path, &WatchOptions{Interval: 10 * time.Millisecond, OnError: func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	old := w.cur.Load()
+	writeConfig(t, path, out, "loud", "text")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		n := len(errs)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("无效的配置应该报告错误")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if w.cur.Load() != old {
+		t.Error("无效的配置不应该替换原配置")
+	}
+	if !strings.Contains(errs[0].Error(), "loud") {
+		t.Errorf("错误应该说明原因: %v", errs[0])
+	}
+
+	if _, err := Watch(filepath.Join(dir, "missing.json"), nil); err == nil {
+		t.Error("首次加载失败时应该返回错误")
+	}
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.json")
+	out := filepath.Join(dir, "app.log")
+	writeConfig(t, path, out, "info", "text")
+
+	w, err := Watch(path, &WatchOptions{Interval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(w.Handler())
+
+	// 模拟 logrotate 移走文件，Reload 应该重新打开原路径
+	os.Rename(out, out+".1")
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("reopened")
+	data, _ := os.ReadFile(out)
+	if !strings.Contains(string(data), "msg=reopened") {
+		t.Errorf("Reload 应该重新打开文件: %s", data)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Handler().Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "closed", 0)); !errors.Is(err, errWatcherClosed) {
+		t.Errorf("关闭后写入应该返回错误: %v", err)
+	}
+}
+
+func TestWatcher_Concurrent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.json")
+	out := filepath.Join(dir, "app.log")
+	writeConfig(t, path, out, "info", "text")
+
+	w, err := Watch(path, &WatchOptions{Interval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	logger := slog.New(w.Handler()).With("k", "v")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if err := logger.Handler().Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "msg", 0)); err != nil {
+					t.Errorf("替换配置时写入不应该失败: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		if err := w.Reload(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+}
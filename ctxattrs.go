@@ -0,0 +1,129 @@
+package slogplus
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// ctxAttrsKey 是 context 中属性的键
+type ctxAttrsKey struct{}
+
+// AppendCtx 返回附加了属性的 context，参数与 slog.Logger.With 相同
+// 之后使用该 context 记录的日志（InfoContext 等）都会带上这些属性，无需在调用链中传递 Logger:
+//
+//	ctx = slogplus.AppendCtx(ctx, "user_id", user.ID, "request_id", reqID)
+//	slog.InfoContext(ctx, "order created") // ... msg="order created" user_id=42 request_id=...
+//
+// 属性位于记录自身的属性之前，与 WithGroup 一起使用时属于当前分组
+func AppendCtx(ctx context.Context, args ...any) context.Context {
+	if len(args) == 0 {
+		return ctx
+	}
+	r := slog.NewRecord(time.Time{}, 0, "", 0)
+	r.Add(args...)
+	prev := CtxAttrs(ctx)
+	attrs := make([]slog.Attr, len(prev), len(prev)+r.NumAttrs())
+	copy(attrs, prev)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return context.WithValue(ctx, ctxAttrsKey{}, attrs)
+}
+
+// CtxAttrs 返回 context 中通过 AppendCtx 附加的属性，返回的切片不能修改
+func CtxAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(ctxAttrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// ctxSnapshot 是某一时刻的调用方状态，通过 context 交给之后才编码记录的 Handler，
+// 使 PushScope 的全局属性和 ctx_remaining 与同步写入时一致，例如 Async 在入队时记录
+type ctxSnapshot struct {
+	ambient     []slog.Attr   // PushScope 添加的全局属性
+	remaining   time.Duration // context 的剩余时长，hasDeadline 为 false 时无效
+	hasDeadline bool
+}
+
+type ctxSnapshotKey struct{}
+
+// takeSnapshot 记录当前的调用方状态，ctx 中已有快照（例如经过了另一层 Async）时沿用该快照
+func takeSnapshot(ctx context.Context) ctxSnapshot {
+	if s, ok := snapshotFrom(ctx); ok {
+		return s
+	}
+	s := ctxSnapshot{ambient: ambientAttrs()}
+	if ctx != nil {
+		if deadline, ok := ctx.Deadline(); ok {
+			s.remaining, s.hasDeadline = time.Until(deadline), true
+		}
+	}
+	return s
+}
+
+// snapshotFrom 返回 context 中的调用方状态
+func snapshotFrom(ctx context.Context) (ctxSnapshot, bool) {
+	if ctx == nil {
+		return ctxSnapshot{}, false
+	}
+	s, ok := ctx.Value(ctxSnapshotKey{}).(ctxSnapshot)
+	return s, ok
+}
+
+// detachCtxAttrs 将全局属性和 context 中的属性移入记录，返回的 context 中不再带有这些属性，
+// 供需要在内层 Handler 之前处理全部属性的包装 Handler 使用，内层不会再次添加
+func detachCtxAttrs(ctx context.Context, r slog.Record) (context.Context, slog.Record) {
+	r = withCtxAttrs(ctx, r)
+	s := takeSnapshot(ctx)
+	s.ambient = nil
+	ctx = context.WithValue(ctx, ctxAttrsKey{}, []slog.Attr(nil))
+	return context.WithValue(ctx, ctxSnapshotKey{}, s), r
+}
+
+// withCtxAttrs 将 PushScope 添加的全局属性和 context 中的属性添加到记录自身的属性之前
+// context 中带有快照时（例如经过 Async）使用快照中的全局属性
+func withCtxAttrs(ctx context.Context, r slog.Record) slog.Record {
+	global, attrs := ambientAttrs(), CtxAttrs(ctx)
+	if s, ok := snapshotFrom(ctx); ok {
+		global = s.ambient
+	}
+	if len(global) == 0 && len(attrs) == 0 {
+		return r
+	}
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(global...)
+	nr.AddAttrs(attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(a)
+		return true
+	})
+	return nr
+}
+
+// CtxRemainingKey 是 context 剩余时长的属性名
+const CtxRemainingKey = "ctx_remaining"
+
+// withCtxRemaining 在 context 带有截止时间时附加 ctx_remaining 属性，context 中带有快照时使用快照中的剩余时长
+func withCtxRemaining(ctx context.Context, r slog.Record) slog.Record {
+	if ctx == nil {
+		return r
+	}
+	var remaining time.Duration
+	if s, ok := snapshotFrom(ctx); ok {
+		if !s.hasDeadline {
+			return r
+		}
+		remaining = s.remaining
+	} else if deadline, ok := ctx.Deadline(); ok {
+		remaining = time.Until(deadline)
+	} else {
+		return r
+	}
+	r = r.Clone()
+	r.AddAttrs(slog.Duration(CtxRemainingKey, remaining.Round(time.Millisecond)))
+	return r
+}
@@ -0,0 +1,73 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendCtx(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, &Options{TimeFormat: "-"})).With("app", "demo")
+
+	ctx := AppendCtx(context.Background(), "request_id", "r-1")
+	ctx = AppendCtx(ctx, slog.Int("user_id", 42))
+	sibling := AppendCtx(AppendCtx(context.Background(), "request_id", "r-1"), "user_id", 7)
+
+	logger.InfoContext(ctx, "order created", "order", 9)
+	logger.InfoContext(sibling, "other")
+	logger.Info("no ctx")
+
+	want := "- INFO app=demo msg=order created request_id=r-1 user_id=42 order=9\n" +
+		"- INFO app=demo msg=other request_id=r-1 user_id=7\n" +
+		"- INFO app=demo msg=no ctx\n"
+	if got := buf.String(); got != want {
+		t.Errorf("context 属性错误:\n%s", got)
+	}
+	if len(CtxAttrs(ctx)) != 2 || CtxAttrs(nil) != nil || AppendCtx(ctx) != ctx {
+		t.Error("CtxAttrs 错误")
+	}
+}
+
+func TestAppendCtx_JSONFilter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, &Options{
+		Format: FormatJSON,
+		Filter: MustParseFilter(`attrs["tenant"] == "acme"`),
+	}))
+	logger.InfoContext(AppendCtx(context.Background(), "tenant", "acme"), "kept")
+	logger.InfoContext(AppendCtx(context.Background(), "tenant", "other"), "dropped")
+
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("应该只输出一条记录: %v\n%s", err, buf.String())
+	}
+	if rec["msg"] != "kept" || rec["tenant"] != "acme" {
+		t.Errorf("过滤表达式应该可以引用 context 属性: %s", buf.String())
+	}
+}
+
+func TestCtxRemaining(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, &Options{TimeFormat: "-", CtxRemaining: slog.LevelWarn}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	expired, cancel2 := context.WithDeadline(context.Background(), time.Now().Add(-1500*time.Millisecond))
+	defer cancel2()
+
+	logger.InfoContext(ctx, "info")
+	logger.WarnContext(ctx, "slow")
+	logger.ErrorContext(expired, "timeout")
+	logger.Error("no ctx")
+
+	lines := strings.Split(buf.String(), "\n")
+	if lines[0] != "- INFO msg=info" || !strings.HasPrefix(lines[1], "- WARN msg=slow ctx_remaining=") ||
+		lines[2] != "- ERROR msg=timeout ctx_remaining=-1.5s" || lines[3] != "- ERROR msg=no ctx" {
+		t.Errorf("ctx_remaining 错误:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,36 @@
+package slogplus
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// minLevelKey 是请求级日志级别在 context 中的键
+type minLevelKey struct{}
+
+// WithMinLevel 返回带有日志级别的 context，使用该 context 记录日志时 Handler 用 level 代替 Options.Level，
+// 例如只为带有调试请求头或在允许列表中的用户的请求输出 DEBUG，其余请求仍为 INFO:
+//
+//	if r.Header.Get("X-Debug-Log") == token {
+//		ctx = slogplus.WithMinLevel(ctx, slog.LevelDebug)
+//	}
+//	slog.DebugContext(ctx, "cache lookup", "key", key)
+//
+// level 也可以高于 Options.Level，用于临时屏蔽某个请求的日志
+func WithMinLevel(ctx context.Context, level slog.Level) context.Context {
+	ctxLevelUsed.Store(true)
+	return context.WithValue(ctx, minLevelKey{}, level)
+}
+
+// ctxLevelUsed 记录进程中是否调用过 WithMinLevel，没有调用过时 Enabled 不需要查找 context
+var ctxLevelUsed atomic.Bool
+
+// MinLevelFromContext 返回 WithMinLevel 设置的日志级别
+func MinLevelFromContext(ctx context.Context) (slog.Level, bool) {
+	if ctx == nil || !ctxLevelUsed.Load() {
+		return 0, false
+	}
+	level, ok := ctx.Value(minLevelKey{}).(slog.Level)
+	return level, ok
+}
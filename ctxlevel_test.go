@@ -0,0 +1,34 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestWithMinLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, &Options{TimeFormat: "-"}))
+
+	debugCtx := WithMinLevel(context.Background(), slog.LevelDebug)
+	quietCtx := WithMinLevel(context.Background(), slog.LevelError)
+	logger.DebugContext(context.Background(), "hidden")
+	logger.DebugContext(debugCtx, "debug request")
+	logger.WarnContext(quietCtx, "quiet request")
+	logger.Info("normal")
+
+	if got := buf.String(); got != "- DEBUG msg=debug request\n- INFO msg=normal\n" {
+		t.Errorf("context 中的级别应该优先: %q", got)
+	}
+	if _, ok := MinLevelFromContext(context.Background()); ok {
+		t.Error("没有设置时应该返回 false")
+	}
+
+	// 包装的 Handler 同样生效
+	buf.Reset()
+	slog.New(Filter(New(&buf, &Options{TimeFormat: "-"}), func(context.Context, slog.Record) bool { return true })).DebugContext(debugCtx, "wrapped")
+	if got := buf.String(); got != "- DEBUG msg=wrapped\n" {
+		t.Errorf("包装的 Handler 应该使用 context 中的级别: %q", got)
+	}
+}
@@ -0,0 +1,62 @@
+package slogplus
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// DeadlineWriter 是支持按记录设置截止时间的输出，例如 NetWriter、SyslogWriter
+// Handler 将 context 的截止时间和 Options.WriteTimeout 中较早的一个传给 WriteDeadline，
+// 输出在截止时间之前写不完时按自身的策略暂存或丢弃记录，保证日志不会拖慢请求
+type DeadlineWriter interface {
+	io.Writer
+
+	// WriteDeadline 写入一条记录，deadline 为零值时等同于 Write
+	WriteDeadline(p []byte, deadline time.Time) (int, error)
+}
+
+// ErrWriteTimeout 表示记录在截止时间之前没有写完而被丢弃
+var ErrWriteTimeout = errors.New("slogplus: 写入超时")
+
+// TimeoutPolicy 定义记录超过截止时间后的处理方式
+type TimeoutPolicy int
+
+const (
+	// TimeoutQueue 暂存记录，稍后在后台补发
+	TimeoutQueue TimeoutPolicy = iota
+
+	// TimeoutDrop 丢弃记录并返回 ErrWriteTimeout
+	TimeoutDrop
+)
+
+// write 写入一条记录，输出实现了 DeadlineWriter 时带上截止时间
+// 只使用 context 的截止时间而不是取消信号，请求取消后记录的日志仍然正常输出
+func (h *Handler) write(ctx context.Context, p []byte) (int, error) {
+	dw, ok := h.out.(DeadlineWriter)
+	if !ok {
+		return h.out.Write(p)
+	}
+	return dw.WriteDeadline(p, h.deadline(ctx))
+}
+
+// deadline 返回 context 的截止时间和 WriteTimeout 中较早的一个，都没有时返回零值
+func (h *Handler) deadline(ctx context.Context) time.Time {
+	var deadline time.Time
+	if ctx != nil {
+		deadline, _ = ctx.Deadline()
+	}
+	if d := h.opts.WriteTimeout; d > 0 {
+		deadline = earliest(deadline, time.Now().Add(d))
+	}
+	return deadline
+}
+
+// earliest 返回两个截止时间中较早的一个，零值表示没有截止时间
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || !b.IsZero() && b.Before(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,55 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// deadlineRecorder 记录每次写入收到的截止时间
+type deadlineRecorder struct {
+	bytes.Buffer
+	deadlines []time.Time
+}
+
+func (w *deadlineRecorder) WriteDeadline(p []byte, deadline time.Time) (int, error) {
+	w.deadlines = append(w.deadlines, deadline)
+	return w.Write(p)
+}
+
+func TestHandler_WriteDeadline(t *testing.T) {
+	w := &deadlineRecorder{}
+	logger := slog.New(New(w, &Options{WriteTimeout: time.Hour}))
+
+	logger.Info("no ctx deadline")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	want, _ := ctx.Deadline()
+	logger.InfoContext(ctx, "ctx deadline")
+
+	if len(w.deadlines) != 2 {
+		t.Fatalf("应该调用 WriteDeadline: %d", len(w.deadlines))
+	}
+	if d := time.Until(w.deadlines[0]); d < 59*time.Minute || d > time.Hour {
+		t.Errorf("没有 context 截止时间时应该使用 WriteTimeout: %v", d)
+	}
+	if !w.deadlines[1].Equal(want) {
+		t.Errorf("应该使用较早的 context 截止时间: %v != %v", w.deadlines[1], want)
+	}
+}
+
+func TestHandler_WriteDeadlineCanceled(t *testing.T) {
+	w := &deadlineRecorder{}
+	logger := slog.New(New(w, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	logger.InfoContext(ctx, "canceled")
+
+	if len(w.deadlines) != 1 || !w.deadlines[0].IsZero() || w.Len() == 0 {
+		t.Errorf("取消的 context 不应该影响输出: %v", w.deadlines)
+	}
+}
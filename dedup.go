@@ -0,0 +1,187 @@
+package slogplus
+
+import (
+	"context"
+	"errors"
+	"hash/maphash"
+	"log/slog"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DedupOptions 定义去重 Handler 的配置
+type DedupOptions struct {
+	// Window 去重窗口，默认 10 秒
+	// 一条记录输出后，窗口内相同的记录不再输出，窗口结束时输出一条带有 repeated=N 的记录
+	Window time.Duration
+
+	// Keys 判断记录是否相同所依据的属性名，级别和消息总是参与比较
+	// 为空时比较记录的所有属性；With 添加的属性不参与比较
+	Keys []string
+
+	// MaxEntries 同时跟踪的最大记录数，超出后新的记录不再去重，默认 10000
+	MaxEntries int
+
+	// Stats 设置后报告被合并的记录数
+	Stats *PipelineStats
+}
+
+// RepeatedKey 是去重汇总记录中重复次数的属性名
+const RepeatedKey = "repeated"
+
+// NewDedup 返回合并重复记录的 Handler，用于抑制重试风暴中成千上万条相同的错误:
+// 第一条记录立即输出，Window 内相同的记录被合并，窗口结束时输出最后一条重复记录并附加 repeated=N
+//
+//	h := slogplus.NewDedup(base, &slogplus.DedupOptions{Window: 30 * time.Second, Keys: []string{"error"}})
+//
+// 同一个 Handler 派生出的所有 Handler 共享去重状态
+func NewDedup(next slog.Handler, opts *DedupOptions) slog.Handler {
+	d := &deduper{entries: make(map[uint64]*dedupEntry), seed: maphash.MakeSeed()}
+	if opts != nil {
+		d.opts = *opts
+	}
+	if d.opts.Window <= 0 {
+		d.opts.Window = 10 * time.Second
+	}
+	if d.opts.MaxEntries <= 0 {
+		d.opts.MaxEntries = 10000
+	}
+	return &dedupHandler{next: next, d: d}
+}
+
+// deduper 是所有派生 Handler 共享的去重状态
+type deduper struct {
+	opts    DedupOptions
+	seed    maphash.Seed
+	mu      sync.Mutex
+	entries map[uint64]*dedupEntry
+}
+
+// dedupEntry 是窗口内的一条记录
+type dedupEntry struct {
+	count int64        // 被合并的记录数
+	last  slog.Record  // 最后一条重复记录
+	next  slog.Handler // 最后一条重复记录所在的 Handler
+	timer *time.Timer
+}
+
+// key 计算记录的去重键
+func (d *deduper) key(r slog.Record) uint64 {
+	var h maphash.Hash
+	h.SetSeed(d.seed)
+	h.WriteString(strconv.Itoa(int(r.Level)))
+	h.WriteByte(0)
+	h.WriteString(r.Message)
+	r.Attrs(func(a slog.Attr) bool {
+		if len(d.opts.Keys) > 0 && !slices.Contains(d.opts.Keys, a.Key) {
+			return true
+		}
+		h.WriteByte(0)
+		h.WriteString(a.Key)
+		h.WriteByte('=')
+		h.WriteString(a.Value.Resolve().String())
+		return true
+	})
+	return h.Sum64()
+}
+
+// seen 判断记录在窗口内是否已经输出过，是时合并该记录
+func (d *deduper) seen(next slog.Handler, r slog.Record) bool {
+	k := d.key(r)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if e := d.entries[k]; e != nil {
+		e.count++
+		e.last = r.Clone()
+		e.next = next
+		d.opts.Stats.Deduped(1)
+		return true
+	}
+	if len(d.entries) >= d.opts.MaxEntries {
+		return false
+	}
+	e := &dedupEntry{}
+	e.timer = time.AfterFunc(d.opts.Window, func() { d.expire(k, e) })
+	d.entries[k] = e
+	return false
+}
+
+// expire 在窗口结束时移除记录并输出汇总
+func (d *deduper) expire(k uint64, e *dedupEntry) {
+	d.mu.Lock()
+	if d.entries[k] != e {
+		d.mu.Unlock()
+		return
+	}
+	delete(d.entries, k)
+	d.mu.Unlock()
+	e.emit()
+}
+
+// emit 输出重复记录的汇总，没有重复时不输出
+func (e *dedupEntry) emit() error {
+	if e.count == 0 {
+		return nil
+	}
+	r := e.last
+	r.AddAttrs(slog.Int64(RepeatedKey, e.count))
+	ctx := context.Background()
+	if !e.next.Enabled(ctx, r.Level) {
+		return nil
+	}
+	return e.next.Handle(ctx, r)
+}
+
+// flush 立即结束所有窗口并输出汇总
+func (d *deduper) flush() error {
+	d.mu.Lock()
+	entries := d.entries
+	d.entries = make(map[uint64]*dedupEntry)
+	d.mu.Unlock()
+
+	var errs []error
+	for _, e := range entries {
+		e.timer.Stop()
+		errs = append(errs, e.emit())
+	}
+	return errors.Join(errs...)
+}
+
+type dedupHandler struct {
+	next slog.Handler
+	d    *deduper
+}
+
+func (h *dedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *dedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.d.seen(h.next, r) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *dedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &dedupHandler{next: h.next.WithAttrs(attrs), d: h.d}
+}
+
+func (h *dedupHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &dedupHandler{next: h.next.WithGroup(name), d: h.d}
+}
+
+// Flush 立即输出所有窗口内重复记录的汇总，并刷新被包装的 Handler
+func (h *dedupHandler) Flush() error {
+	err := h.d.flush()
+	if f, ok := h.next.(Flusher); ok {
+		err = errors.Join(err, f.Flush())
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package slogplus
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDedup(t *testing.T) {
+	var buf syncBuffer
+	stats := &PipelineStats{}
+	h := NewDedup(New(&buf, &Options{TimeFormat: "-"}), &DedupOptions{Window: time.Hour, Keys: []string{"err"}, Stats: stats})
+	logger := slog.New(h).With("app", "demo")
+
+	for i := 0; i < 1000; i++ {
+		logger.Error("重试失败", "err", errors.New("connection refused"), "attempt", i)
+	}
+	logger.Error("重试失败", "err", errors.New("timeout"), "attempt", 0)
+	logger.Warn("重试失败", "err", errors.New("connection refused"))
+
+	want := "- ERROR app=demo msg=重试失败 err=connection refused attempt=0\n" +
+		"- ERROR app=demo msg=重试失败 err=timeout attempt=0\n" +
+		"- WARN app=demo msg=重试失败 err=connection refused\n"
+	if got := buf.String(); got != want {
+		t.Errorf("窗口内的重复记录应该被合并:\n%s", got)
+	}
+	if stats.Snapshot().Deduped != 999 {
+		t.Errorf("去重统计错误: %+v", stats.Snapshot())
+	}
+
+	if err := h.(Flusher).Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); !strings.HasSuffix(got, "- ERROR app=demo msg=重试失败 err=connection refused attempt=999 repeated=999\n") ||
+		strings.Count(got, "repeated=") != 1 {
+		t.Errorf("汇总记录错误:\n%s", got)
+	}
+}
+
+func TestDedup_Window(t *testing.T) {
+	var buf syncBuffer
+	logger := slog.New(NewDedup(New(&buf, &Options{TimeFormat: "-"}), &DedupOptions{Window: 20 * time.Millisecond}))
+
+	logger.Info("tick")
+	logger.Info("tick")
+	logger.Info("tick")
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(buf.String(), "repeated=2") {
+		if time.Now().After(deadline) {
+			t.Fatalf("窗口结束时应该输出汇总: %q", buf.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	logger.Info("tick")
+	if got := buf.String(); got != "- INFO msg=tick\n- INFO msg=tick repeated=2\n- INFO msg=tick\n" {
+		t.Errorf("窗口结束后应该重新输出: %q", got)
+	}
+}
+
+func TestDedup_MaxEntries(t *testing.T) {
+	var buf syncBuffer
+	logger := slog.New(NewDedup(New(&buf, nil), &DedupOptions{Window: time.Hour, MaxEntries: 1}))
+	logger.Info("a")
+	logger.Info("b")
+	logger.Info("b")
+	if n := strings.Count(buf.String(), "msg=b"); n != 2 {
+		t.Errorf("超出 MaxEntries 后不应该去重: %d", n)
+	}
+}
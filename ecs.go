@@ -0,0 +1,67 @@
+package slogplus
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// ecsVersion 输出遵循的 ECS 版本
+const ecsVersion = "8.11.0"
+
+// appendECS 按 Elastic Common Schema 格式追加一条记录
+// {"@timestamp":"...","log.level":"info","message":"...","ecs.version":"8.11.0",...}
+func (h *Handler) appendECS(buf []byte, r slog.Record) []byte {
+	buf = append(buf, '{')
+	if !r.Time.IsZero() {
+		buf = append(buf, `"@timestamp":"`...)
+		buf = r.Time.UTC().AppendFormat(buf, "2006-01-02T15:04:05.000Z07:00")
+		buf = append(buf, '"', ',')
+	}
+	buf = append(buf, `"log.level":`...)
+	buf = appendJSONString(buf, strings.ToLower(h.levelName(r.Level)))
+	buf = append(buf, `,"message":`...)
+	buf = appendJSONString(buf, r.Message)
+	buf = append(buf, `,"ecs.version":"`+ecsVersion+`"`...)
+
+	if h.opts.Hostname != "" {
+		buf = append(buf, `,"host.name":`...)
+		buf = appendJSONString(buf, h.opts.Hostname)
+	}
+
+	if h.opts.AddSource && r.PC != 0 {
+		f := h.frame(r.PC)
+		if f.File != "" {
+			buf = append(buf, `,"log.origin.file.name":`...)
+			buf = appendJSONString(buf, f.File)
+			buf = append(buf, `,"log.origin.file.line":`...)
+			buf = strconv.AppendInt(buf, int64(f.Line), 10)
+			if f.Function != "" {
+				buf = append(buf, `,"log.origin.function":`...)
+				buf = appendJSONString(buf, f.Function)
+			}
+		}
+	}
+
+	buf = h.appendBound(buf, FormatECS)
+	r.Attrs(func(a slog.Attr) bool {
+		buf = h.appendECSAttr(buf, h.groups, a)
+		return true
+	})
+
+	return append(buf, '}', '\n')
+}
+
+// appendECSAttr 追加 ECS 字段，顶层的 error 值映射为 error.message 和 error.type
+func (h *Handler) appendECSAttr(buf []byte, groups []string, a slog.Attr) []byte {
+	if len(groups) == 0 && (a.Key == "error" || a.Key == "err") && a.Value.Kind() == slog.KindAny && !isNilValue(a.Value) {
+		if err, ok := a.Value.Any().(error); ok {
+			buf = append(buf, `,"error.message":`...)
+			buf = appendJSONString(buf, err.Error())
+			buf = append(buf, `,"error.type":`...)
+			return appendJSONString(buf, fmt.Sprintf("%T", err))
+		}
+	}
+	return h.appendJSONAttr(buf, groups, a, appendJSONDottedKey, true)
+}
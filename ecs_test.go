@@ -0,0 +1,45 @@
+package slogplus
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHandler_ECS(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{Format: FormatECS, AddSource: true, Hostname: "web-1"})
+
+	logger.With("service.name", "api").Error("request failed",
+		"error", errors.New("timeout"),
+		"http.response.status_code", 504,
+	)
+
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("输出应该是合法的 JSON: %v\n%s", err, buf.String())
+	}
+	want := map[string]any{
+		"log.level":                 "error",
+		"message":                   "request failed",
+		"ecs.version":               ecsVersion,
+		"host.name":                 "web-1",
+		"service.name":              "api",
+		"error.message":             "timeout",
+		"error.type":                "*errors.errorString",
+		"http.response.status_code": float64(504),
+	}
+	for k, v := range want {
+		if m[k] != v {
+			t.Errorf("%s = %v, 期望 %v", k, m[k], v)
+		}
+	}
+	if _, err := time.Parse(time.RFC3339, m["@timestamp"].(string)); err != nil {
+		t.Errorf("@timestamp 格式不正确: %v", m["@timestamp"])
+	}
+	if name, _ := m["log.origin.file.name"].(string); name == "" {
+		t.Errorf("开启 AddSource 后应该包含 log.origin.file.name")
+	}
+}
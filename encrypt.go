@@ -0,0 +1,201 @@
+package slogplus
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+)
+
+// Encrypter 加密属性值，用于 NewEncryptHandler
+// 可以接入 KMS、age 等外部方案，返回的密文按标准 base64 编码后输出
+type Encrypter interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+}
+
+// AESGCM 使用 AES-GCM 加密，密文格式为 12 字节随机 nonce 加上密文和认证标签
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM 创建 AES-GCM 加密器，key 的长度为 16、24 或 32 字节，分别对应 AES-128、AES-192、AES-256
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("slogplus: 无效的 AES 密钥: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCM{aead: aead}, nil
+}
+
+// Encrypt 实现 Encrypter
+func (c *AESGCM) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plaintext)+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt 解密日志中 base64 编码的密文，用于安全团队取证
+func (c *AESGCM) Decrypt(ciphertext string) ([]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("slogplus: 密文不是有效的 base64: %w", err)
+	}
+	n := c.aead.NonceSize()
+	if len(b) < n {
+		return nil, errors.New("slogplus: 密文过短")
+	}
+	return c.aead.Open(nil, b[:n], b[n:], nil)
+}
+
+// EncryptOptions 定义字段加密的配置
+type EncryptOptions struct {
+	// Encrypter 加密器，通常为 NewAESGCM 的返回值
+	Encrypter Encrypter
+
+	// Keys 需要加密的属性名，规则与 Options.RedactKeys 相同，例如 "ssn"、"patient.*"
+	// 匹配分组或 Object、map 等复合值时，整个值按 JSON 编码后加密
+	Keys []string
+}
+
+// NewEncryptHandler 返回加密指定属性后交给 next 的 Handler，属性值输出为 base64 密文，
+// 只有持有密钥的人才能还原，适用于需要保留原始信息供取证的日志
+// 加密失败时属性值输出为 [REDACTED]，不会泄露明文
+//
+// next 为 slogplus Handler 时加密与脱敏在同一阶段进行，AppendCtx、PushScope 和 Baggage 添加的属性同样被加密；
+// 其它 Handler 在外层加密记录、WithAttrs、AppendCtx 和 PushScope 的属性，next 自行添加的属性不在此列
+func NewEncryptHandler(next slog.Handler, opts *EncryptOptions) (slog.Handler, error) {
+	if opts == nil || opts.Encrypter == nil {
+		return nil, errors.New("slogplus: 字段加密需要设置 Encrypter")
+	}
+	e := &encryptor{enc: opts.Encrypter, keys: newKeyPatterns(opts.Keys), json: New(nil, &Options{Format: FormatJSON})}
+	if h, ok := next.(*Handler); ok {
+		return h.withEncryptor(e), nil
+	}
+	return &encryptHandler{next: next, e: e}, nil
+}
+
+// withEncryptor 返回在脱敏阶段加密属性的 Handler，已经通过 WithAttrs 添加的属性一并加密
+func (h *Handler) withEncryptor(e *encryptor) *Handler {
+	nh := h.clone()
+	rw := &attrRewriter{}
+	if h.rewriter != nil {
+		*rw = *h.rewriter
+	}
+	rw.encrypt = e
+	nh.rewriter = rw
+	nh.attrs = make([]boundAttr, len(h.attrs))
+	for i, ba := range h.attrs {
+		nh.attrs[i] = boundAttr{groups: ba.groups, Attr: e.attr(ba.groups, ba.Attr)}
+	}
+	return nh
+}
+
+type encryptor struct {
+	enc  Encrypter
+	keys keyPatterns
+	json *Handler // 编码分组和复合值的明文
+}
+
+// attr 加密匹配的属性，未匹配的分组和 map 递归处理；匹配的分组、Object 和 map 等复合值按 JSON 编码后整体加密
+func (e *encryptor) attr(groups []string, a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	if e.keys.match(groups, a.Key) {
+		return e.seal(a)
+	}
+	if g, ok := mapGroup(a.Value, 0); ok {
+		a.Value = g
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], a.Key)
+		}
+		attrs := a.Value.Group()
+		out := make([]slog.Attr, len(attrs))
+		for i, ga := range attrs {
+			out[i] = e.attr(groups, ga)
+		}
+		a.Value = slog.GroupValue(out...)
+	}
+	return a
+}
+
+// seal 将属性值替换为 base64 密文，加密失败时输出 [REDACTED]
+func (e *encryptor) seal(a slog.Attr) slog.Attr {
+	ct, err := e.enc.Encrypt(e.plaintext(a.Value))
+	if err != nil {
+		return slog.String(a.Key, RedactedValue)
+	}
+	return slog.String(a.Key, base64.StdEncoding.EncodeToString(ct))
+}
+
+// plaintext 返回属性值的明文，分组和 map、切片、结构体按 JSON 编码，其余值使用 Value.String
+func (e *encryptor) plaintext(v slog.Value) []byte {
+	if v.Kind() == slog.KindGroup {
+		return e.json.appendJSONValue(nil, v)
+	}
+	if v.Kind() == slog.KindAny && !isNilValue(v) {
+		switch rv := reflect.ValueOf(v.Any()); rv.Kind() {
+		case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+			if _, ok := v.Any().([]byte); !ok {
+				return e.json.appendJSONValue(nil, v)
+			}
+		}
+	}
+	return []byte(v.String())
+}
+
+type encryptHandler struct {
+	next   slog.Handler
+	e      *encryptor
+	groups []string
+}
+
+func (h *encryptHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *encryptHandler) Handle(ctx context.Context, r slog.Record) error {
+	// 先取出 context 和 PushScope 的属性一起加密，避免 next 再以明文添加
+	ctx, r = detachCtxAttrs(ctx, r)
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(h.e.attr(h.groups, a))
+		return true
+	})
+	return h.next.Handle(ctx, nr)
+}
+
+func (h *encryptHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		out[i] = h.e.attr(h.groups, a)
+	}
+	return &encryptHandler{next: h.next.WithAttrs(out), e: h.e, groups: h.groups}
+}
+
+func (h *encryptHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	groups := append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &encryptHandler{next: h.next.WithGroup(name), e: h.e, groups: groups}
+}
+
+// Flush 实现 Flusher
+func (h *encryptHandler) Flush() error {
+	if f, ok := h.next.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
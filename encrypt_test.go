@@ -0,0 +1,120 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestEncryptHandler(t *testing.T) {
+	c, err := NewAESGCM(bytes.Repeat([]byte("k"), 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	h, err := NewEncryptHandler(New(&buf, &Options{TimeFormat: "-"}), &EncryptOptions{Encrypter: c, Keys: []string{"ssn", "patient.*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slog.New(h).With("ssn", "123-45-6789").WithGroup("patient").Info("就诊", "name", "alice", "dept", 3)
+
+	m := regexp.MustCompile(`^- INFO ssn=(\S+) msg=就诊 patient.name=(\S+) patient.dept=(\S+)\n$`).FindStringSubmatch(buf.String())
+	if m == nil {
+		t.Fatalf("输出错误: %q", buf.String())
+	}
+	for i, want := range []string{"123-45-6789", "alice", "3"} {
+		pt, err := c.Decrypt(m[i+1])
+		if err != nil || string(pt) != want {
+			t.Errorf("解密错误: %q %v", pt, err)
+		}
+	}
+	if _, err := c.Decrypt("bm9wZQ=="); err == nil {
+		t.Error("篡改的密文应该解密失败")
+	}
+}
+
+func TestEncryptHandler_GroupValue(t *testing.T) {
+	c, _ := NewAESGCM(bytes.Repeat([]byte("k"), 32))
+	var buf bytes.Buffer
+	h, _ := NewEncryptHandler(New(&buf, &Options{TimeFormat: "-"}), &EncryptOptions{Encrypter: c, Keys: []string{"patient", "tags"}})
+	slog.New(h).Info("就诊",
+		slog.Group("patient", "name", "alice", "dept", 3),
+		"tags", map[string]int{"a": 1},
+	)
+
+	m := regexp.MustCompile(`^- INFO msg=就诊 patient=(\S+) tags=(\S+)\n$`).FindStringSubmatch(buf.String())
+	if m == nil {
+		t.Fatalf("匹配的分组和 map 应该整体加密: %q", buf.String())
+	}
+	for i, want := range []string{`{"name":"alice","dept":3}`, `{"a":1}`} {
+		pt, err := c.Decrypt(m[i+1])
+		if err != nil || string(pt) != want {
+			t.Errorf("解密错误: %q %v", pt, err)
+		}
+	}
+}
+
+func TestEncryptHandler_CtxAndMap(t *testing.T) {
+	c, _ := NewAESGCM(bytes.Repeat([]byte("k"), 32))
+	opts := &EncryptOptions{Encrypter: c, Keys: []string{"ssn", "user.ssn", "phase"}}
+	ctx := AppendCtx(context.Background(), "ssn", "111-22-3333")
+	pop := PushScope("phase", "migrate")
+	defer pop()
+
+	check := func(name, out string) {
+		t.Helper()
+		for _, plain := range []string{"111-22-3333", "222-33-4444", "migrate"} {
+			if strings.Contains(out, plain) {
+				t.Errorf("%s: 不应该输出明文 %s: %s", name, plain, out)
+			}
+		}
+		m := regexp.MustCompile(`"user":\{"n":1,"ssn":"([^"]+)"\}`).FindStringSubmatch(out)
+		if m == nil {
+			t.Fatalf("%s: map 中匹配的键应该被加密: %s", name, out)
+		}
+		if pt, err := c.Decrypt(m[1]); err != nil || string(pt) != "222-33-4444" {
+			t.Errorf("%s: 解密错误: %q %v", name, pt, err)
+		}
+	}
+
+	// next 为 slogplus Handler 时在脱敏阶段加密
+	var buf bytes.Buffer
+	h, _ := NewEncryptHandler(New(&buf, &Options{Format: FormatJSON}), opts)
+	slog.New(h).InfoContext(ctx, "x", "user", map[string]any{"n": 1, "ssn": "222-33-4444"})
+	check("Handler", buf.String())
+
+	// 其它 Handler 在外层加密
+	var sb syncBuffer
+	ah := Async(New(&sb, &Options{Format: FormatJSON}), nil)
+	h, _ = NewEncryptHandler(ah, opts)
+	slog.New(h).InfoContext(ctx, "x", "user", map[string]any{"n": 1, "ssn": "222-33-4444"})
+	ah.Close()
+	check("Async", sb.String())
+	if strings.Count(sb.String(), `"ssn"`) != 2 || strings.Count(sb.String(), `"phase"`) != 1 {
+		t.Errorf("context 和全局属性不应该重复输出: %s", sb.String())
+	}
+}
+
+type failingEncrypter struct{}
+
+func (failingEncrypter) Encrypt([]byte) ([]byte, error) { return nil, errors.New("kms 不可用") }
+
+func TestEncryptHandler_Errors(t *testing.T) {
+	if _, err := NewEncryptHandler(New(nil, nil), &EncryptOptions{}); err == nil {
+		t.Error("没有 Encrypter 应该返回错误")
+	}
+	if _, err := NewAESGCM([]byte("short")); err == nil {
+		t.Error("无效的密钥长度应该返回错误")
+	}
+
+	var buf bytes.Buffer
+	h, _ := NewEncryptHandler(New(&buf, &Options{TimeFormat: "-"}), &EncryptOptions{Encrypter: failingEncrypter{}, Keys: []string{"ssn"}})
+	slog.New(h).Info("x", "ssn", "123")
+	if got := buf.String(); got != "- INFO msg=x ssn=[REDACTED]\n" {
+		t.Errorf("加密失败时不应该输出明文: %q", got)
+	}
+}
@@ -0,0 +1,134 @@
+package slogplus
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SetupFromEnv 和 OptionsFromEnv 读取的环境变量
+const (
+	EnvLogLevel      = "LOG_LEVEL"       // 日志级别，例如 debug、WARN、INFO+2 或 RegisterLevel 注册的名称
+	EnvLogFormat     = "LOG_FORMAT"      // 输出格式，例如 text、json、console，见 ParseFormat
+	EnvLogTimeFormat = "LOG_TIME_FORMAT" // 时间格式，Go 时间布局或 rfc3339、rfc3339nano、datetime
+	EnvLogAddSource  = "LOG_ADD_SOURCE"  // 是否输出源代码位置，true、false、1、0
+	EnvLogOutput     = "LOG_OUTPUT"      // 输出位置，stdout（默认）、stderr 或文件路径
+)
+
+// envTimeFormats 是 LOG_TIME_FORMAT 支持的格式名称
+var envTimeFormats = map[string]string{
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"datetime":    time.DateTime,
+}
+
+// SetupFromEnv 按环境变量设置全局默认 Logger，容器中不修改代码即可调整日志配置
+//
+//	LOG_LEVEL=debug LOG_FORMAT=json LOG_OUTPUT=/var/log/app.log ./app
+//
+// 未设置的变量使用默认配置；值无效的变量被忽略，其余配置仍然生效，返回的错误列出所有无效的变量
+//
+// 级别保存在 slog.LevelVar 中，ReloadAll（以及 HandleSignals 收到 SIGHUP 时）重新读取 LOG_LEVEL
+func SetupFromEnv() error {
+	opts, out, err := OptionsFromEnv(nil)
+	level := new(slog.LevelVar)
+	if opts.Level != nil {
+		level.Set(opts.Level.Level())
+	}
+	opts.Level = level
+	Setup(out, opts)
+
+	envReloader.mu.Lock()
+	defer envReloader.mu.Unlock()
+	if envReloader.unregister != nil {
+		envReloader.unregister()
+	}
+	envReloader.unregister = RegisterReloader(func() error { return reloadEnvLevel(level) })
+	return err
+}
+
+// envReloader 是最近一次 SetupFromEnv 注册的重新加载函数，再次调用时替换
+var envReloader struct {
+	mu         sync.Mutex
+	unregister func()
+}
+
+// reloadEnvLevel 按 LOG_LEVEL 设置 v，未设置时保持原级别
+func reloadEnvLevel(v *slog.LevelVar) error {
+	s := strings.TrimSpace(os.Getenv(EnvLogLevel))
+	if s == "" {
+		return nil
+	}
+	level, err := ParseLevel(s)
+	if err != nil {
+		return fmt.Errorf("slogplus: 环境变量 %s=%q 无效: %w", EnvLogLevel, s, err)
+	}
+	v.Set(level)
+	return nil
+}
+
+// OptionsFromEnv 在 base 的基础上应用环境变量中的配置，返回新的 Options 和输出，base 不会被修改
+// LOG_OUTPUT 为文件路径时以追加方式打开文件，返回的输出为 *FileWriter
+func OptionsFromEnv(base *Options) (*Options, io.Writer, error) {
+	var opts Options
+	if base != nil {
+		opts = *base
+	}
+	var out io.Writer = os.Stdout
+	var errs []error
+	invalid := func(name, value string, err error) {
+		errs = append(errs, fmt.Errorf("slogplus: 环境变量 %s=%q 无效: %w", name, value, err))
+	}
+
+	if v := strings.TrimSpace(os.Getenv(EnvLogLevel)); v != "" {
+		if level, err := ParseLevel(v); err != nil {
+			invalid(EnvLogLevel, v, err)
+		} else {
+			opts.Level = level
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvLogFormat)); v != "" {
+		if f, err := ParseFormat(v); err != nil {
+			invalid(EnvLogFormat, v, err)
+		} else {
+			opts.Format, opts.FormatVar = f, nil
+		}
+	}
+	if v := os.Getenv(EnvLogTimeFormat); v != "" {
+		if layout, ok := envTimeFormats[strings.ToLower(v)]; ok {
+			v = layout
+		}
+		if err := checkTimeFormat(v); err != nil {
+			invalid(EnvLogTimeFormat, v, err)
+		} else {
+			opts.TimeFormat = v
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvLogAddSource)); v != "" {
+		if b, err := strconv.ParseBool(v); err != nil {
+			invalid(EnvLogAddSource, v, err)
+		} else {
+			opts.AddSource = b
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvLogOutput)); v != "" {
+		switch strings.ToLower(v) {
+		case "stdout":
+		case "stderr":
+			out = os.Stderr
+		default:
+			if w, err := NewFileWriter(v, nil); err != nil {
+				invalid(EnvLogOutput, v, err)
+			} else {
+				out = w
+			}
+		}
+	}
+	return &opts, out, errors.Join(errs...)
+}
@@ -0,0 +1,88 @@
+package slogplus
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOptionsFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	t.Setenv(EnvLogLevel, "debug")
+	t.Setenv(EnvLogFormat, "JSON")
+	t.Setenv(EnvLogTimeFormat, "rfc3339")
+	t.Setenv(EnvLogAddSource, "true")
+	t.Setenv(EnvLogOutput, path)
+
+	base := &Options{Color: true}
+	opts, out, err := OptionsFromEnv(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Level.Level() != slog.LevelDebug || opts.Format != FormatJSON || opts.TimeFormat != time.RFC3339 || !opts.AddSource || !opts.Color {
+		t.Errorf("配置错误: %+v", opts)
+	}
+	if base.Format != "" || base.AddSource {
+		t.Error("不应该修改 base")
+	}
+
+	w, ok := out.(*FileWriter)
+	if !ok {
+		t.Fatalf("文件路径应该返回 FileWriter: %T", out)
+	}
+	NewLogger(w, opts).Debug("hello")
+	w.Close()
+	b, _ := os.ReadFile(path)
+	if !strings.Contains(string(b), `"msg":"hello"`) {
+		t.Errorf("应该写入文件: %s", b)
+	}
+}
+
+func TestOptionsFromEnv_Invalid(t *testing.T) {
+	t.Setenv(EnvLogLevel, "verbose")
+	t.Setenv(EnvLogFormat, "xml")
+	t.Setenv(EnvLogAddSource, "maybe")
+	t.Setenv(EnvLogTimeFormat, "yyyy-MM-dd HH:mm:ss")
+	t.Setenv(EnvLogOutput, "stderr")
+
+	opts, out, err := OptionsFromEnv(&Options{Level: slog.LevelWarn})
+	if err == nil {
+		t.Fatal("无效的环境变量应该返回错误")
+	}
+	for _, name := range []string{EnvLogLevel, EnvLogFormat, EnvLogAddSource, EnvLogTimeFormat} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("错误应该包含 %s: %v", name, err)
+		}
+	}
+	if opts.Level.Level() != slog.LevelWarn || opts.Format != FormatText || opts.TimeFormat != "" || out != os.Stderr {
+		t.Errorf("无效的变量应该被忽略，其余配置仍然生效: %+v %v", opts, out)
+	}
+}
+
+func TestSetupFromEnv(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	t.Setenv(EnvLogLevel, "warn")
+	if err := SetupFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if slog.Default().Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("默认 Logger 应该使用 LOG_LEVEL")
+	}
+
+	t.Setenv(EnvLogLevel, "debug")
+	if err := ReloadAll(); err != nil {
+		t.Fatal(err)
+	}
+	if !slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("ReloadAll 应该重新读取 LOG_LEVEL")
+	}
+	t.Setenv(EnvLogLevel, "loud")
+	if err := ReloadAll(); err == nil || !strings.Contains(err.Error(), "loud") {
+		t.Errorf("无效的 LOG_LEVEL 应该返回错误: %v", err)
+	}
+	envReloader.unregister()
+}
@@ -0,0 +1,76 @@
+package slogplus
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// ErrorKey 是 Err 属性的名称
+const ErrorKey = "error"
+
+// Err 返回错误属性，输出错误信息、错误类型以及错误链最内层的根因，代替手写的 slog.String("error", err.Error())
+//
+//	logger.Error("保存失败", slogplus.Err(err))
+//	// msg=保存失败 error={msg=保存订单: write /data/a: no space left on device type=*fs.PathError cause=no space left on device}
+//
+// type 跳过 fmt.Errorf、errors.New 等没有区分意义的包装类型，cause 只在错误被包装过时输出；
+// 属性值仍然实现 error，错误指纹和 Sentry 上报可以识别。err 为 nil 时返回空属性，不会输出
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+	return slog.Any(ErrorKey, errorValue{err})
+}
+
+// errorValue 按分组输出错误链
+type errorValue struct {
+	error
+}
+
+// Unwrap 返回原始错误，使 errors.Is 和 errors.As 可以穿透
+func (e errorValue) Unwrap() error { return e.error }
+
+// LogValue 实现 slog.LogValuer
+func (e errorValue) LogValue() slog.Value {
+	msg := e.Error()
+	attrs := make([]slog.Attr, 1, 3)
+	attrs[0] = slog.String("msg", msg)
+	if t := errorType(e.error); t != "" {
+		attrs = append(attrs, slog.String("type", t))
+	}
+	if root := rootError(e.error); root != e.error && root.Error() != msg {
+		attrs = append(attrs, slog.String("cause", root.Error()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// genericErrorTypes 是标准库中只负责包装或携带消息的错误类型
+var genericErrorTypes = map[string]bool{
+	"*errors.errorString": true,
+	"*errors.joinError":   true,
+	"*fmt.wrapError":      true,
+	"*fmt.wrapErrors":     true,
+}
+
+// errorType 返回错误链中第一个有区分意义的错误类型，全部为通用类型时返回空字符串
+func errorType(err error) string {
+	for ; err != nil; err = unwrapOne(err) {
+		if t := fmt.Sprintf("%T", err); !genericErrorTypes[t] {
+			return t
+		}
+	}
+	return ""
+}
+
+// unwrapOne 返回被包装的错误，errors.Join 等包装多个错误时返回第一个
+func unwrapOne(err error) error {
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return u.Unwrap()
+	case interface{ Unwrap() []error }:
+		if errs := u.Unwrap(); len(errs) > 0 {
+			return errs[0]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package slogplus
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestErr(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{Format: FormatJSON, TimeFormat: "-", ErrorFingerprint: true})
+
+	err := fmt.Errorf("保存订单: %w", &fs.PathError{Op: "write", Path: "/data/a", Err: fs.ErrPermission})
+	logger.Error("保存失败", Err(err))
+
+	got := buf.String()
+	want := `"error":{"msg":"保存订单: write /data/a: permission denied","type":"*fs.PathError","cause":"permission denied"}`
+	if !strings.Contains(got, want) || !strings.Contains(got, FingerprintKey) {
+		t.Errorf("输出错误: %s", got)
+	}
+
+	a := Err(err)
+	if !errors.Is(a.Value.Any().(error), fs.ErrPermission) {
+		t.Error("属性值应该可以通过 errors.Is 穿透")
+	}
+	if !Err(nil).Equal(slog.Attr{}) {
+		t.Error("nil 错误应该返回空属性")
+	}
+}
+
+func TestErr_Plain(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{TimeFormat: "-"})
+
+	logger.Error("失败", Err(errors.New("超时")))
+	if got := buf.String(); got != "- ERROR msg=失败 error={msg=超时}\n" {
+		t.Errorf("没有包装的通用错误只应该输出 msg: %q", got)
+	}
+}
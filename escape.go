@@ -0,0 +1,39 @@
+package slogplus
+
+import "unicode/utf8"
+
+// jsonSafe 标记可以原样写入 JSON 字符串的 ASCII 字节
+var jsonSafe = func() (t [utf8.RuneSelf]bool) {
+	for c := ' '; c < utf8.RuneSelf; c++ {
+		t[c] = c != '"' && c != '\\'
+	}
+	return t
+}()
+
+// logfmtSafe 标记可以出现在不加引号的 logfmt 值中的 ASCII 字节
+var logfmtSafe = func() (t [utf8.RuneSelf]bool) {
+	for c := '!'; c < 0x7f; c++ {
+		t[c] = c != '=' && c != '"' && c != '\\'
+	}
+	return t
+}()
+
+// logfmtQuotedSafe 标记可以原样写入带引号 logfmt 值的 ASCII 字节
+var logfmtQuotedSafe = func() (t [utf8.RuneSelf]bool) {
+	for c := ' '; c < 0x7f; c++ {
+		t[c] = c != '"' && c != '\\'
+	}
+	return t
+}()
+
+// safeASCIIPrefix 返回 s 从 i 开始连续满足 safe 的 ASCII 字节结束的位置
+func safeASCIIPrefix(s string, i int, safe *[utf8.RuneSelf]bool) int {
+	for i < len(s) {
+		c := s[i]
+		if c >= utf8.RuneSelf || !safe[c] {
+			break
+		}
+		i++
+	}
+	return i
+}
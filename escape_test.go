@@ -0,0 +1,88 @@
+package slogplus
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var escapeSamples = []string{
+	"",
+	"plain ascii text",
+	`quote " and backslash \`,
+	"line\nbreak\ttab\rcr\x00nul\x1fus\x7fdel",
+	"key=value",
+	"中文日志 with ascii",
+	"emoji 🚀 and   sep  ",
+	"bad utf8 \xff\xfe end",
+	strings.Repeat("long run of safe bytes ", 20) + "\"",
+}
+
+func TestAppendJSONString(t *testing.T) {
+	for _, s := range escapeSamples {
+		b := appendJSONString(nil, s)
+		var got string
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Errorf("%q: 输出不是合法的 JSON 字符串 %s: %v", s, b, err)
+			continue
+		}
+		if want := string([]rune(s)); got != want {
+			t.Errorf("%q: 解码结果 %q, 期望 %q", s, got, want)
+		}
+	}
+}
+
+func TestAppendLogfmtString_RoundTrip(t *testing.T) {
+	for _, s := range escapeSamples {
+		b := appendLogfmtString(nil, s)
+		if !needsLogfmtQuote(s) {
+			if string(b) != s {
+				t.Errorf("%q: 不需要引号的值应该原样输出: %s", s, b)
+			}
+			continue
+		}
+		got, err := strconv.Unquote(string(b))
+		if err != nil {
+			t.Errorf("%q: 输出无法解析 %s: %v", s, b, err)
+			continue
+		}
+		if want := string([]rune(s)); got != want {
+			t.Errorf("%q: 解码结果 %q, 期望 %q", s, got, want)
+		}
+	}
+}
+
+// escapeBenchText 是一条典型的需要转义的日志消息
+var escapeBenchText = `GET /api/v1/users?id=42 "Mozilla/5.0 (X11; Linux x86_64)" took 12ms` + "\n\t用户不存在"
+
+func BenchmarkAppendJSONString(b *testing.B) {
+	buf := make([]byte, 0, 256)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = appendJSONString(buf[:0], escapeBenchText)
+	}
+}
+
+func BenchmarkStdJSONMarshalString(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		json.Marshal(escapeBenchText)
+	}
+}
+
+func BenchmarkAppendLogfmtString(b *testing.B) {
+	buf := make([]byte, 0, 256)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = appendLogfmtString(buf[:0], escapeBenchText)
+	}
+}
+
+func BenchmarkStdAppendQuote(b *testing.B) {
+	buf := make([]byte, 0, 256)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = strconv.AppendQuote(buf[:0], escapeBenchText)
+	}
+}
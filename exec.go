@@ -0,0 +1,85 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"errors"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// ExecOptions 定义子进程输出捕获的配置
+type ExecOptions struct {
+	// Name child 属性的值，默认为可执行文件名
+	Name string
+
+	// StdoutLevel、StderrLevel 标准输出和标准错误的级别，默认分别为 Info 和 Warn
+	StdoutLevel slog.Leveler
+	StderrLevel slog.Leveler
+
+	// MaxLineSize 单行的最大字节数，默认 64KB
+	MaxLineSize int
+
+	// PartialTimeout 不完整的行等待该时间后直接输出，默认 1 秒
+	PartialTimeout time.Duration
+}
+
+// CaptureCmd 将 cmd 的标准输出和标准错误按行写入 logger，每行一条记录，
+// 带有 child=<名称> 和 stream=stdout|stderr 属性，必须在 cmd.Start 之前调用
+// cmd.Wait 返回后调用返回的 flush，输出最后不完整的行
+func CaptureCmd(logger *slog.Logger, cmd *exec.Cmd, opts *ExecOptions) (flush func(), err error) {
+	if cmd.Stdout != nil || cmd.Stderr != nil {
+		return nil, errors.New("slogplus: cmd 已经设置了 Stdout 或 Stderr")
+	}
+	var o ExecOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Name == "" {
+		o.Name = filepath.Base(cmd.Path)
+	}
+	if o.StdoutLevel == nil {
+		o.StdoutLevel = slog.LevelInfo
+	}
+	if o.StderrLevel == nil {
+		o.StderrLevel = slog.LevelWarn
+	}
+	if o.PartialTimeout <= 0 {
+		o.PartialTimeout = time.Second
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	logger = logger.With("child", o.Name)
+	stdout := &LineWriter{
+		Logger:         logger.With("stream", "stdout"),
+		Level:          o.StdoutLevel.Level(),
+		MaxLineSize:    o.MaxLineSize,
+		PartialTimeout: o.PartialTimeout,
+	}
+	stderr := &LineWriter{
+		Logger:         logger.With("stream", "stderr"),
+		Level:          o.StderrLevel.Level(),
+		MaxLineSize:    o.MaxLineSize,
+		PartialTimeout: o.PartialTimeout,
+	}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+
+	return func() {
+		stdout.Close()
+		stderr.Close()
+	}, nil
+}
+
+// RunCmd 捕获 cmd 的输出（见 CaptureCmd）并运行 cmd，等待其结束
+func RunCmd(logger *slog.Logger, cmd *exec.Cmd, opts *ExecOptions) error {
+	flush, err := CaptureCmd(logger, cmd, opts)
+	if err != nil {
+		return err
+	}
+	defer flush()
+	return cmd.Run()
+}
@@ -0,0 +1,59 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestRunCmd(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("找不到 sh")
+	}
+
+	var buf syncBuffer
+	logger := NewLogger(&buf, &Options{Format: FormatJSON})
+	cmd := exec.Command(sh, "-c", `echo hello; echo oops >&2; printf 'no newline'`)
+	if err := RunCmd(logger, cmd, &ExecOptions{Name: "worker", StderrLevel: slog.LevelError}); err != nil {
+		t.Fatal(err)
+	}
+
+	var recs []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatal(err)
+		}
+		recs = append(recs, m)
+	}
+	if len(recs) != 3 {
+		t.Fatalf("应该输出 3 条记录: %s", buf.String())
+	}
+	byMsg := map[string]map[string]any{}
+	for _, r := range recs {
+		if r["child"] != "worker" {
+			t.Errorf("缺少 child 属性: %v", r)
+		}
+		byMsg[r["msg"].(string)] = r
+	}
+	if r := byMsg["hello"]; r == nil || r["stream"] != "stdout" || r["level"] != "INFO" {
+		t.Errorf("标准输出错误: %v", r)
+	}
+	if r := byMsg["oops"]; r == nil || r["stream"] != "stderr" || r["level"] != "ERROR" {
+		t.Errorf("标准错误错误: %v", r)
+	}
+	if byMsg["no newline"] == nil {
+		t.Errorf("结束时应该输出不完整的行: %s", buf.String())
+	}
+
+	cmd = exec.Command(sh, "-c", "true")
+	cmd.Stdout = &buf
+	if _, err := CaptureCmd(logger, cmd, nil); err == nil {
+		t.Error("已经设置输出时应该返回错误")
+	}
+}
@@ -0,0 +1,84 @@
+package slogplus
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// Fanout 返回将每条记录分发给多个 Handler 的 Handler，例如同时输出到终端（文本）和文件（JSON）
+// 每个 Handler 按自身的 Enabled 决定是否处理，某个 Handler 出错不影响其它 Handler，
+// 错误合并后返回；nil 会被忽略
+//
+//	slog.SetDefault(slog.New(slogplus.Fanout(
+//		slogplus.New(os.Stderr, &slogplus.Options{Format: slogplus.FormatConsole}),
+//		slogplus.New(file, &slogplus.Options{Format: slogplus.FormatJSON, Level: slog.LevelDebug}),
+//	)))
+func Fanout(handlers ...slog.Handler) slog.Handler {
+	hs := make([]slog.Handler, 0, len(handlers))
+	for _, h := range handlers {
+		if h != nil {
+			hs = append(hs, h)
+		}
+	}
+	return &fanoutHandler{handlers: hs}
+}
+
+type fanoutHandler struct {
+	handlers []slog.Handler
+}
+
+func (h *fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, c := range h.handlers {
+		if c.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, c := range h.handlers {
+		if !c.Enabled(ctx, r.Level) {
+			continue
+		}
+		// 每个 Handler 使用独立的副本，避免其中一个修改属性影响其它 Handler
+		if err := c.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	hs := make([]slog.Handler, len(h.handlers))
+	for i, c := range h.handlers {
+		hs[i] = c.WithAttrs(attrs)
+	}
+	return &fanoutHandler{handlers: hs}
+}
+
+func (h *fanoutHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	hs := make([]slog.Handler, len(h.handlers))
+	for i, c := range h.handlers {
+		hs[i] = c.WithGroup(name)
+	}
+	return &fanoutHandler{handlers: hs}
+}
+
+// Flush 刷新所有实现了 Flusher 的 Handler
+func (h *fanoutHandler) Flush() error {
+	var errs []error
+	for _, c := range h.handlers {
+		if f, ok := c.(Flusher); ok {
+			if err := f.Flush(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,66 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// errHandler 总是返回错误
+type errHandler struct{ slog.Handler }
+
+func (h errHandler) Handle(context.Context, slog.Record) error { return errors.New("boom") }
+
+func TestFanout(t *testing.T) {
+	var text, js bytes.Buffer
+	h := Fanout(
+		New(&text, &Options{TimeFormat: "-"}),
+		nil,
+		New(&js, &Options{Format: FormatJSON, Level: slog.LevelDebug}),
+	)
+	logger := slog.New(h).With("app", "demo").WithGroup("req")
+
+	logger.Debug("debug only json", "id", 1)
+	logger.Info("both", "id", 2)
+
+	if strings.Contains(text.String(), "debug only json") {
+		t.Error("文本输出不应该处理 Debug 记录")
+	}
+	if !strings.Contains(text.String(), "app=demo msg=both req.id=2") {
+		t.Errorf("文本输出错误: %s", text.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(js.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("JSON 输出应该有 2 条记录: %s", js.String())
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec["app"] != "demo" || rec["req.id"] != float64(2) {
+		t.Errorf("JSON 输出错误: %s", lines[1])
+	}
+
+	if h.Enabled(context.Background(), slog.LevelDebug-4) {
+		t.Error("所有 Handler 都不处理时应该返回 false")
+	}
+}
+
+func TestFanout_Errors(t *testing.T) {
+	var buf bytes.Buffer
+	h := Fanout(errHandler{New(&buf, nil)}, New(&buf, nil), errHandler{New(&buf, nil)})
+
+	err := h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0))
+	if err == nil || strings.Count(err.Error(), "boom") != 2 {
+		t.Errorf("应该合并所有错误: %v", err)
+	}
+	if !strings.Contains(buf.String(), "msg=hello") {
+		t.Error("出错的 Handler 不应该影响其它 Handler")
+	}
+}
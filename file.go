@@ -0,0 +1,239 @@
+package slogplus
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rotation 定义按时间切割文件的周期
+type Rotation int
+
+const (
+	// RotateNone 不切割，直接写入指定路径
+	RotateNone Rotation = iota
+
+	// RotateDaily 每天切割一次，文件名形如 app-2025-11-14.log
+	RotateDaily
+
+	// RotateHourly 每小时切割一次，文件名形如 app-2025-11-14-15.log
+	RotateHourly
+)
+
+// layout 返回文件名中的时间格式
+func (r Rotation) layout() string {
+	if r == RotateHourly {
+		return "2006-01-02-15"
+	}
+	return "2006-01-02"
+}
+
+// start 返回 t 所在周期的开始时间
+func (r Rotation) start(t time.Time) time.Time {
+	y, m, d := t.Date()
+	if r == RotateHourly {
+		return time.Date(y, m, d, t.Hour(), 0, 0, 0, t.Location())
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// FileOptions 定义 FileWriter 的配置
+type FileOptions struct {
+	// Rotation 切割周期，默认不切割
+	Rotation Rotation
+
+	// MaxAge 切割后的文件保留时长，超过的文件在切割时删除，0 表示全部保留
+	MaxAge time.Duration
+
+	// Symlink 是否在原路径创建指向当前文件的符号链接，便于 tail -F
+	Symlink bool
+
+	// Perm 新建文件的权限，默认 0644
+	Perm os.FileMode
+}
+
+// FileWriter 是按时间切割的日志文件输出，可以安全地并发使用
+// 开启切割后文件名在扩展名前加上日期，例如 /var/log/app.log 写入 /var/log/app-2025-11-14.log
+type FileWriter struct {
+	path string
+	opts FileOptions
+	now  func() time.Time
+
+	mu     sync.Mutex
+	f      *os.File
+	period time.Time // 当前文件所在周期的开始时间
+
+	unregister func()
+}
+
+// NewFileWriter 打开日志文件，目录不存在时自动创建
+// 文件在 ReloadAll（以及 HandleSignals 收到 SIGHUP 时）被重新打开，直到 Close
+func NewFileWriter(path string, opts *FileOptions) (*FileWriter, error) {
+	w := &FileWriter{path: path, now: time.Now}
+	if opts != nil {
+		w.opts = *opts
+	}
+	if w.opts.Perm == 0 {
+		w.opts.Perm = 0o644
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	if err := w.open(w.now()); err != nil {
+		return nil, err
+	}
+	w.unregister = RegisterReloader(w.Reopen)
+	return w, nil
+}
+
+// Write 写入一条记录，进入新周期时先切换文件
+func (w *FileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return 0, os.ErrClosed
+	}
+	if w.opts.Rotation != RotateNone {
+		if now := w.now(); !w.opts.Rotation.start(now).Equal(w.period) {
+			if err := w.open(now); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return w.f.Write(p)
+}
+
+// Filename 返回当前写入的文件路径
+func (w *FileWriter) Filename() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.filename(w.period)
+}
+
+// Flush 将文件内容同步到磁盘，实现 Flusher
+func (w *FileWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return nil
+	}
+	return w.f.Sync()
+}
+
+// Reopen 关闭并重新打开当前文件，用于 logrotate 移走文件后写入原路径的新文件，
+// 已关闭时不做任何操作
+func (w *FileWriter) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return nil
+	}
+	return w.open(w.now())
+}
+
+// Close 关闭当前文件
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return nil
+	}
+	w.unregister()
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
+
+// filename 返回周期对应的文件路径
+func (w *FileWriter) filename(period time.Time) string {
+	if w.opts.Rotation == RotateNone {
+		return w.path
+	}
+	ext := filepath.Ext(w.path)
+	return strings.TrimSuffix(w.path, ext) + "-" + period.Format(w.opts.Rotation.layout()) + ext
+}
+
+// open 打开 now 所在周期的文件，关闭旧文件并清理过期文件
+func (w *FileWriter) open(now time.Time) error {
+	period := w.opts.Rotation.start(now)
+	name := w.filename(period)
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, w.opts.Perm)
+	if err != nil {
+		return err
+	}
+	if w.f != nil {
+		w.f.Close()
+	}
+	w.f = f
+	w.period = period
+
+	if w.opts.Rotation == RotateNone {
+		return nil
+	}
+	if w.opts.Symlink {
+		if err := w.link(name); err != nil {
+			return err
+		}
+	}
+	if w.opts.MaxAge > 0 {
+		w.removeExpired(now)
+	}
+	return nil
+}
+
+// link 将原路径指向当前文件，已有的普通文件不会被覆盖
+func (w *FileWriter) link(name string) error {
+	if fi, err := os.Lstat(w.path); err == nil {
+		if fi.Mode()&os.ModeSymlink == 0 {
+			return fmt.Errorf("slogplus: %s 已存在且不是符号链接", w.path)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	// 先创建临时链接再重命名，保证原路径始终可用
+	tmp := w.path + ".tmp"
+	os.Remove(tmp)
+	if err := os.Symlink(filepath.Base(name), tmp); err != nil {
+		return err
+	}
+	return os.Rename(tmp, w.path)
+}
+
+// removeExpired 删除周期结束时间早于 now-MaxAge 的切割文件
+func (w *FileWriter) removeExpired(now time.Time) {
+	dir := filepath.Dir(w.path)
+	ext := filepath.Ext(w.path)
+	prefix := strings.TrimSuffix(filepath.Base(w.path), ext) + "-"
+	layout := w.opts.Rotation.layout()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	cutoff := now.Add(-w.opts.MaxAge)
+	for _, e := range entries {
+		name := e.Name()
+		if !e.Type().IsRegular() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		t, err := time.ParseInLocation(layout, stamp, now.Location())
+		if err != nil || t.Equal(w.period) {
+			continue
+		}
+		end := t.AddDate(0, 0, 1)
+		if w.opts.Rotation == RotateHourly {
+			end = t.Add(time.Hour)
+		}
+		if end.Before(cutoff) {
+			os.Remove(filepath.Join(dir, name))
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package slogplus
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileWriter_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	now := time.Date(2025, 11, 14, 23, 59, 0, 0, time.Local)
+
+	w, err := NewFileWriter(path, &FileOptions{Rotation: RotateDaily, MaxAge: 48 * time.Hour, Symlink: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.now = func() time.Time { return now }
+
+	// 已过期和未过期的旧文件
+	old := filepath.Join(dir, "app-2025-11-10.log")
+	recent := filepath.Join(dir, "app-2025-11-13.log")
+	for _, name := range []string{old, recent} {
+		if err := os.WriteFile(name, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w.Write([]byte("first\n"))
+	now = now.Add(2 * time.Minute)
+	w.Write([]byte("second\n"))
+
+	if got := w.Filename(); got != filepath.Join(dir, "app-2025-11-15.log") {
+		t.Errorf("Filename() = %s", got)
+	}
+	if b, _ := os.ReadFile(filepath.Join(dir, "app-2025-11-14.log")); string(b) != "first\n" {
+		t.Errorf("旧周期文件内容不正确: %q", b)
+	}
+	if b, _ := os.ReadFile(path); string(b) != "second\n" {
+		t.Errorf("符号链接应该指向当前文件: %q", b)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Error("过期文件应该被删除")
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Errorf("未过期的文件应该保留: %v", err)
+	}
+}
+
+func TestFileWriter_Hourly(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewFileWriter(filepath.Join(dir, "app.log"), &FileOptions{Rotation: RotateHourly})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	now := time.Date(2025, 11, 14, 15, 30, 0, 0, time.Local)
+	w.now = func() time.Time { return now }
+	w.Write([]byte("x\n"))
+	if got := filepath.Base(w.Filename()); got != "app-2025-11-14-15.log" {
+		t.Errorf("Filename() = %s", got)
+	}
+}
+
+func TestFileWriter_SymlinkExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(path, []byte("keep"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileWriter(path, &FileOptions{Rotation: RotateDaily, Symlink: true}); err == nil {
+		t.Error("原路径是普通文件时应该返回错误")
+	}
+	if b, _ := os.ReadFile(path); string(b) != "keep" {
+		t.Error("已有的普通文件不应该被覆盖")
+	}
+}
+
+func TestFileWriter_Reopen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	w, err := NewFileWriter(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("first\n"))
+
+	// 模拟 logrotate 移走文件
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReloadAll(); err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("second\n"))
+	if b, _ := os.ReadFile(path); string(b) != "second\n" {
+		t.Errorf("重新打开后应该写入原路径的新文件: %q", b)
+	}
+	if b, _ := os.ReadFile(path + ".1"); string(b) != "first\n" {
+		t.Errorf("移走的文件内容不正确: %q", b)
+	}
+
+	w.Close()
+	os.Remove(path)
+	if err := ReloadAll(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("关闭后不应该再重新打开文件")
+	}
+}
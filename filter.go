@@ -0,0 +1,51 @@
+package slogplus
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Filter 返回只把 keep 返回 true 的记录交给 h 的 Handler，用于丢弃健康检查、噪声路径等记录，
+// 无需自己实现完整的 Handler。keep 只能看到记录本身的属性，看不到 With 添加的属性；
+// 需要引用预设属性时使用 Options.Filter
+//
+//	h := slogplus.Filter(base, func(_ context.Context, r slog.Record) bool {
+//		drop := false
+//		r.Attrs(func(a slog.Attr) bool {
+//			drop = a.Key == "path" && a.Value.String() == "/healthz"
+//			return !drop
+//		})
+//		return !drop
+//	})
+//
+// 过滤表达式也可以直接使用: slogplus.Filter(base, slogplus.MustParseFilter(`level >= WARN`).Match)
+func Filter(h slog.Handler, keep func(ctx context.Context, r slog.Record) bool) slog.Handler {
+	return &filterHandler{next: h, keep: keep}
+}
+
+type filterHandler struct {
+	next slog.Handler
+	keep func(ctx context.Context, r slog.Record) bool
+}
+
+func (h *filterHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *filterHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.keep(ctx, r) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *filterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &filterHandler{next: h.next.WithAttrs(attrs), keep: h.keep}
+}
+
+func (h *filterHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &filterHandler{next: h.next.WithGroup(name), keep: h.keep}
+}
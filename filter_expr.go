@@ -0,0 +1,481 @@
+package slogplus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FilterExpr 是编译后的记录过滤表达式，可以安全地并发使用
+//
+// 表达式在加载时编译一次，之后每条记录只做求值，例如:
+//
+//	level >= WARN && attrs["component"] == "db"
+//	msg =~ "^timeout" || !attrs["internal"]
+//
+// 支持的语法:
+//   - 操作数: level、msg、attrs["key"]（分组属性使用 "group.key"）、
+//     字符串、数字、true、false 以及级别名称 DEBUG、INFO、WARN、ERROR 和 RegisterLevel 注册的名称
+//   - 比较: == != < <= > >=，两边都是数字时按数值比较，否则按字符串比较
+//   - 正则匹配: =~ !~，右边必须是字符串，编译时检查
+//   - 逻辑: && || ! 和括号；单独的操作数按真值判断，不存在的属性为假
+//
+// FilterExpr 实现了 encoding.TextUnmarshaler，可以直接写在配置文件中
+type FilterExpr struct {
+	src  string
+	eval filterCond
+}
+
+// filterCond 对一条记录求值
+type filterCond func(e *filterEnv) bool
+
+// filterOperand 返回操作数的值，属性不存在时 ok 为 false
+type filterOperand func(e *filterEnv) (v slog.Value, ok bool)
+
+// filterEnv 是求值时的记录上下文
+type filterEnv struct {
+	r      *slog.Record
+	groups []string    // 记录属性所在的分组
+	attrs  []boundAttr // 预设属性
+}
+
+// ParseFilter 编译过滤表达式
+func ParseFilter(expr string) (*FilterExpr, error) {
+	toks, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{toks: toks}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "多余的 %q", t.text)
+	}
+	return &FilterExpr{src: expr, eval: cond}, nil
+}
+
+// MustParseFilter 与 ParseFilter 相同，但编译失败时 panic
+func MustParseFilter(expr string) *FilterExpr {
+	f, err := ParseFilter(expr)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Match 判断记录是否满足表达式，属性从记录本身查找
+// 可以直接作为过滤或路由的判断函数使用
+func (f *FilterExpr) Match(_ context.Context, r slog.Record) bool {
+	return f.eval(&filterEnv{r: &r})
+}
+
+// match 判断记录是否满足表达式，同时查找 Handler 的预设属性
+func (f *FilterExpr) match(r *slog.Record, groups []string, attrs []boundAttr) bool {
+	return f.eval(&filterEnv{r: r, groups: groups, attrs: attrs})
+}
+
+// String 返回原始表达式
+func (f *FilterExpr) String() string {
+	return f.src
+}
+
+// MarshalText 实现 encoding.TextMarshaler
+func (f *FilterExpr) MarshalText() ([]byte, error) {
+	return []byte(f.src), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler
+func (f *FilterExpr) UnmarshalText(data []byte) error {
+	nf, err := ParseFilter(string(data))
+	if err != nil {
+		return err
+	}
+	*f = *nf
+	return nil
+}
+
+// lookup 按 group.key 查找属性，预设属性在前，同名时记录属性优先
+func (e *filterEnv) lookup(key string) (v slog.Value, ok bool) {
+	for _, ba := range e.attrs {
+		if rest, hit := trimGroups(key, ba.groups); hit {
+			if av, found := findAttr(ba.Attr, rest); found {
+				v, ok = av, true
+			}
+		}
+	}
+	rest, hit := trimGroups(key, e.groups)
+	if !hit {
+		return v, ok
+	}
+	e.r.Attrs(func(a slog.Attr) bool {
+		if av, found := findAttr(a, rest); found {
+			v, ok = av, true
+		}
+		return true
+	})
+	return v, ok
+}
+
+// trimGroups 去掉 key 开头的分组前缀
+func trimGroups(key string, groups []string) (string, bool) {
+	for _, g := range groups {
+		if !strings.HasPrefix(key, g) || len(key) <= len(g) || key[len(g)] != '.' {
+			return "", false
+		}
+		key = key[len(g)+1:]
+	}
+	return key, true
+}
+
+// findAttr 在属性（及其分组）中查找 key
+func findAttr(a slog.Attr, key string) (slog.Value, bool) {
+	v := a.Value.Resolve()
+	if v.Kind() != slog.KindGroup {
+		return v, a.Key == key
+	}
+	if a.Key != "" {
+		if !strings.HasPrefix(key, a.Key+".") {
+			return slog.Value{}, false
+		}
+		key = key[len(a.Key)+1:]
+	}
+	for _, ga := range v.Group() {
+		if gv, ok := findAttr(ga, key); ok {
+			return gv, true
+		}
+	}
+	return slog.Value{}, false
+}
+
+type filterTokenKind int
+
+const (
+	tokEOF filterTokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+}
+
+// lexFilter 将表达式切分为记号
+func lexFilter(s string) ([]filterToken, error) {
+	var toks []filterToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, filterToken{tokLParen, "(", i})
+			i++
+		case c == ')':
+			toks = append(toks, filterToken{tokRParen, ")", i})
+			i++
+		case c == '[':
+			toks = append(toks, filterToken{tokLBracket, "[", i})
+			i++
+		case c == ']':
+			toks = append(toks, filterToken{tokRBracket, "]", i})
+			i++
+		case c == '"':
+			j := i + 1
+			for ; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("slogplus: 过滤表达式第 %d 个字符: 字符串没有结束", i+1)
+			}
+			str, err := strconv.Unquote(s[i : j+1])
+			if err != nil {
+				return nil, fmt.Errorf("slogplus: 过滤表达式第 %d 个字符: 无效的字符串 %s", i+1, s[i:j+1])
+			}
+			toks = append(toks, filterToken{tokString, str, i})
+			i = j + 1
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '9':
+			j := i + 1
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+				j++
+			}
+			toks = append(toks, filterToken{tokNumber, s[i:j], i})
+			i = j
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i + 1
+			for j < len(s) && (s[j] == '_' || s[j] >= 'a' && s[j] <= 'z' || s[j] >= 'A' && s[j] <= 'Z' || s[j] >= '0' && s[j] <= '9') {
+				j++
+			}
+			toks = append(toks, filterToken{tokIdent, s[i:j], i})
+			i = j
+		default:
+			op := ""
+			for _, o := range []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "!~", "<", ">", "!"} {
+				if strings.HasPrefix(s[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("slogplus: 过滤表达式第 %d 个字符: 无效的字符 %q", i+1, c)
+			}
+			toks = append(toks, filterToken{tokOp, op, i})
+			i += len(op)
+		}
+	}
+	return append(toks, filterToken{kind: tokEOF, pos: len(s)}), nil
+}
+
+// filterParser 是递归下降解析器
+type filterParser struct {
+	toks []filterToken
+	i    int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.toks[p.i]
+}
+
+func (p *filterParser) next() filterToken {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *filterParser) errorf(t filterToken, format string, args ...any) error {
+	return fmt.Errorf("slogplus: 过滤表达式第 %d 个字符: %s", t.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *filterParser) isOp(op string) bool {
+	t := p.peek()
+	return t.kind == tokOp && t.text == op
+}
+
+func (p *filterParser) parseOr() (filterCond, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(e *filterEnv) bool { return l(e) || right(e) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterCond, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("&&") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(e *filterEnv) bool { return l(e) && right(e) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterCond, error) {
+	if p.isOp("!") {
+		p.next()
+		c, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(e *filterEnv) bool { return !c(e) }, nil
+	}
+	if p.peek().kind == tokLParen {
+		p.next()
+		c, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, p.errorf(t, "缺少 )")
+		}
+		return c, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterCond, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	if t.kind != tokOp || t.text == "&&" || t.text == "||" || t.text == "!" {
+		return func(e *filterEnv) bool { return filterTruthy(left(e)) }, nil
+	}
+	p.next()
+
+	if t.text == "=~" || t.text == "!~" {
+		rt := p.next()
+		if rt.kind != tokString {
+			return nil, p.errorf(rt, "%s 右边必须是字符串", t.text)
+		}
+		re, err := regexp.Compile(rt.text)
+		if err != nil {
+			return nil, p.errorf(rt, "无效的正则表达式: %v", err)
+		}
+		want := t.text == "=~"
+		return func(e *filterEnv) bool {
+			v, ok := left(e)
+			return ok && re.MatchString(v.String()) == want
+		}, nil
+	}
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	var test func(c int) bool
+	switch t.text {
+	case "==":
+		test = func(c int) bool { return c == 0 }
+	case "!=":
+		test = func(c int) bool { return c != 0 }
+	case "<":
+		test = func(c int) bool { return c < 0 }
+	case "<=":
+		test = func(c int) bool { return c <= 0 }
+	case ">":
+		test = func(c int) bool { return c > 0 }
+	case ">=":
+		test = func(c int) bool { return c >= 0 }
+	}
+	return func(e *filterEnv) bool {
+		lv, lok := left(e)
+		rv, rok := right(e)
+		if !lok || !rok {
+			// 不存在的属性只满足 !=
+			return t.text == "!=" && lok != rok
+		}
+		return test(filterCompare(lv, rv))
+	}, nil
+}
+
+func (p *filterParser) parseOperand() (filterOperand, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		v := slog.StringValue(t.text)
+		return func(*filterEnv) (slog.Value, bool) { return v, true }, nil
+	case tokNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, p.errorf(t, "无效的数字 %s", t.text)
+		}
+		v := slog.Float64Value(n)
+		return func(*filterEnv) (slog.Value, bool) { return v, true }, nil
+	case tokIdent:
+		switch t.text {
+		case "level":
+			return func(e *filterEnv) (slog.Value, bool) { return slog.IntValue(int(e.r.Level)), true }, nil
+		case "msg":
+			return func(e *filterEnv) (slog.Value, bool) { return slog.StringValue(e.r.Message), true }, nil
+		case "true", "false":
+			v := slog.BoolValue(t.text == "true")
+			return func(*filterEnv) (slog.Value, bool) { return v, true }, nil
+		case "attrs":
+			if lb := p.next(); lb.kind != tokLBracket {
+				return nil, p.errorf(lb, "attrs 后面需要 [")
+			}
+			kt := p.next()
+			if kt.kind != tokString {
+				return nil, p.errorf(kt, "属性名必须是字符串")
+			}
+			if rb := p.next(); rb.kind != tokRBracket {
+				return nil, p.errorf(rb, "缺少 ]")
+			}
+			key := kt.text
+			return func(e *filterEnv) (slog.Value, bool) { return e.lookup(key) }, nil
+		}
+		level, err := ParseLevel(t.text)
+		if err != nil {
+			return nil, p.errorf(t, "未知的标识符 %s", t.text)
+		}
+		v := slog.IntValue(int(level))
+		return func(*filterEnv) (slog.Value, bool) { return v, true }, nil
+	case tokEOF:
+		return nil, p.errorf(t, "表达式不完整")
+	}
+	return nil, p.errorf(t, "无效的 %q", t.text)
+}
+
+// filterNumber 返回数值类型的值
+func filterNumber(v slog.Value) (float64, bool) {
+	switch v.Kind() {
+	case slog.KindInt64:
+		return float64(v.Int64()), true
+	case slog.KindUint64:
+		return float64(v.Uint64()), true
+	case slog.KindFloat64:
+		return v.Float64(), true
+	case slog.KindDuration:
+		return float64(v.Duration()), true
+	}
+	return 0, false
+}
+
+// filterCompare 比较两个值，两边都是数字时按数值比较，否则按字符串比较
+func filterCompare(a, b slog.Value) int {
+	if x, ok := filterNumber(a); ok {
+		if y, ok := filterNumber(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(a.String(), b.String())
+}
+
+// filterTruthy 判断操作数的真值
+func filterTruthy(v slog.Value, ok bool) bool {
+	if !ok {
+		return false
+	}
+	switch v.Kind() {
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindString:
+		return v.String() != ""
+	case slog.KindAny:
+		return v.Any() != nil
+	}
+	if n, isNum := filterNumber(v); isNum {
+		return n != 0
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFilter(t *testing.T) {
+	r := slog.NewRecord(time.Now(), slog.LevelWarn, "timeout talking to db", 0)
+	r.AddAttrs(
+		slog.String("component", "db"),
+		slog.Int("status", 503),
+		slog.Bool("internal", false),
+		slog.Group("req", slog.String("path", "/healthz")),
+	)
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`level >= WARN`, true},
+		{`level > WARN`, false},
+		{`level >= WARN && attrs["component"] == "db"`, true},
+		{`level >= ERROR || attrs["component"] == "db"`, true},
+		{`attrs["component"] != "db"`, false},
+		{`attrs["status"] >= 500`, true},
+		{`attrs["status"] == 503`, true},
+		{`attrs["missing"] == "x"`, false},
+		{`attrs["missing"] != "x"`, true},
+		{`attrs["missing"]`, false},
+		{`!attrs["internal"]`, true},
+		{`attrs["req.path"] == "/healthz"`, true},
+		{`msg =~ "^timeout"`, true},
+		{`msg !~ "db$"`, false},
+		{`!(level < INFO) && (msg == "x" || attrs["status"] < 600)`, true},
+		{`level == -4`, false},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.expr)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if got := f.Match(context.Background(), r); got != tt.want {
+			t.Errorf("%s = %v, 期望 %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`level >=`,
+		`level >= LOUD`,
+		`(level >= WARN`,
+		`attrs[component] == "db"`,
+		`msg =~ "("`,
+		`msg =~ level`,
+		`level >= WARN extra`,
+		`"unterminated`,
+		`level # 1`,
+	} {
+		if _, err := ParseFilter(expr); err == nil {
+			t.Errorf("%q 应该编译失败", expr)
+		} else if !strings.HasPrefix(err.Error(), "slogplus: 过滤表达式") {
+			t.Errorf("%q 错误信息不正确: %v", expr, err)
+		}
+	}
+}
+
+func TestFilterExpr_UnmarshalText(t *testing.T) {
+	var cfg struct {
+		Filter *FilterExpr `json:"filter"`
+	}
+	if err := json.Unmarshal([]byte(`{"filter": "level >= ERROR"}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Filter.String() != "level >= ERROR" {
+		t.Errorf("String() = %q", cfg.Filter)
+	}
+	if err := json.Unmarshal([]byte(`{"filter": "level >="}`), &cfg); err == nil {
+		t.Error("无效表达式应该返回错误")
+	}
+}
+
+func TestHandler_Filter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{
+		Filter: MustParseFilter(`level >= WARN || attrs["svc.component"] == "db"`),
+	})
+
+	logger.Info("dropped")
+	logger.With("component", "db").WithGroup("svc").Info("dropped too")
+	logger.WithGroup("svc").With("component", "db").Info("kept by preset attr")
+	logger.Warn("kept by level")
+
+	out := buf.String()
+	if strings.Contains(out, "dropped") {
+		t.Errorf("不满足条件的记录应该被丢弃: %s", out)
+	}
+	if !strings.Contains(out, "kept by preset attr") || !strings.Contains(out, "kept by level") {
+		t.Errorf("满足条件的记录应该输出: %s", out)
+	}
+}
@@ -0,0 +1,48 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type skipKey struct{}
+
+func TestFilter(t *testing.T) {
+	var buf bytes.Buffer
+	h := Filter(New(&buf, &Options{TimeFormat: "-"}), func(ctx context.Context, r slog.Record) bool {
+		if ctx.Value(skipKey{}) != nil {
+			return false
+		}
+		keep := true
+		r.Attrs(func(a slog.Attr) bool {
+			keep = !(a.Key == "path" && a.Value.String() == "/healthz")
+			return keep
+		})
+		return keep
+	})
+	logger := slog.New(h).With("app", "demo")
+
+	logger.Info("request", "path", "/healthz")
+	logger.Info("request", "path", "/api")
+	logger.InfoContext(context.WithValue(context.Background(), skipKey{}, true), "skipped")
+
+	if got := buf.String(); got != "- INFO app=demo msg=request path=/api\n" {
+		t.Errorf("过滤结果错误: %q", got)
+	}
+}
+
+func TestFilter_Expr(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(Filter(New(&buf, nil), MustParseFilter(`level >= WARN || attrs["component"] == "db"`).Match))
+
+	logger.Info("ignored")
+	logger.Info("query", "component", "db")
+	logger.Warn("slow")
+
+	if strings.Contains(buf.String(), "ignored") || !strings.Contains(buf.String(), "msg=query") || !strings.Contains(buf.String(), "msg=slow") {
+		t.Errorf("过滤表达式结果错误: %s", buf.String())
+	}
+}
@@ -0,0 +1,131 @@
+package slogplus
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"runtime"
+	"strconv"
+)
+
+// FingerprintKey 是错误指纹属性的名称
+const FingerprintKey = "error.fingerprint"
+
+// fingerprintFrames 参与计算指纹的栈帧数
+const fingerprintFrames = 3
+
+// ErrorFingerprint 计算错误的稳定指纹，用于在日志平台中聚合相同的故障
+// 指纹由根因错误类型、去掉数字/ID/引号内容后的错误信息以及栈顶若干帧的函数名计算，
+// 错误实现了 Callers() []uintptr 时使用错误自带的调用栈，否则使用 pc 所在的函数
+func ErrorFingerprint(err error, pc uintptr) string {
+	if err == nil {
+		return ""
+	}
+
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%T\n", rootError(err))
+	h.Write(sanitizeErrorMessage(err.Error()))
+	h.Write([]byte{'\n'})
+
+	var pcs []uintptr
+	var c interface{ Callers() []uintptr }
+	if errors.As(err, &c) {
+		pcs = c.Callers()
+	} else if pc != 0 {
+		pcs = []uintptr{pc}
+	}
+	if len(pcs) > 0 {
+		frames := runtime.CallersFrames(pcs)
+		for i := 0; i < fingerprintFrames; i++ {
+			f, more := frames.Next()
+			h.Write([]byte(f.Function))
+			h.Write([]byte{'\n'})
+			if !more {
+				break
+			}
+		}
+	}
+
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// sanitizeErrorMessage 去掉错误信息中易变的部分
+// 包含数字的单词（ID、数量、地址、UUID 等）替换为 '#'，双引号和单引号中的内容替换为 '?'
+func sanitizeErrorMessage(msg string) []byte {
+	out := make([]byte, 0, len(msg))
+	for i := 0; i < len(msg); {
+		c := msg[i]
+		if c == '"' || c == '\'' {
+			if j := indexByteFrom(msg, c, i+1); j > 0 {
+				out = append(out, c, '?', c)
+				i = j + 1
+				continue
+			}
+		}
+		if isWordByte(c) {
+			j := i
+			digit := false
+			for j < len(msg) && (isWordByte(msg[j]) || msg[j] == '-') {
+				if msg[j] >= '0' && msg[j] <= '9' {
+					digit = true
+				}
+				j++
+			}
+			if digit {
+				out = append(out, '#')
+			} else {
+				out = append(out, msg[i:j]...)
+			}
+			i = j
+			continue
+		}
+		out = append(out, c)
+		i++
+	}
+	return out
+}
+
+func isWordByte(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func indexByteFrom(s string, c byte, from int) int {
+	for i := from; i < len(s); i++ {
+		if s[i] == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// rootError 返回错误链最内层的错误
+func rootError(err error) error {
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+}
+
+// fingerprintRecord 为记录中第一个错误属性计算指纹并追加 error.fingerprint 属性
+func fingerprintRecord(r slog.Record) slog.Record {
+	var err error
+	r.Attrs(func(a slog.Attr) bool {
+		if k := a.Value.Kind(); (k == slog.KindAny || k == slog.KindLogValuer) && !isNilValue(a.Value) {
+			if e, ok := a.Value.Any().(error); ok {
+				err = e
+				return false
+			}
+		}
+		return true
+	})
+	if err == nil {
+		return r
+	}
+	r = r.Clone()
+	r.AddAttrs(slog.String(FingerprintKey, ErrorFingerprint(err, r.PC)))
+	return r
+}
@@ -0,0 +1,63 @@
+package slogplus
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeErrorMessage(t *testing.T) {
+	tests := map[string]string{
+		"user 12345 not found":                                "user # not found",
+		`open "/tmp/a.txt": no such file`:                     `open "?": no such file`,
+		"request 550e8400-e29b-41d4-a716-446655440000 failed": "request # failed",
+		"dial tcp 10.0.0.1:5432: connection refused":          "dial tcp #.#.#.#:#: connection refused",
+	}
+	for in, want := range tests {
+		if got := string(sanitizeErrorMessage(in)); got != want {
+			t.Errorf("sanitizeErrorMessage(%q) = %q, 期望 %q", in, got, want)
+		}
+	}
+}
+
+func TestErrorFingerprint(t *testing.T) {
+	a := ErrorFingerprint(fmt.Errorf("load user 1: %w", fs.ErrNotExist), 0)
+	b := ErrorFingerprint(fmt.Errorf("load user 2: %w", fs.ErrNotExist), 0)
+	c := ErrorFingerprint(fmt.Errorf("load user 1: %w", errors.New("timeout")), 0)
+	if a == "" || a != b {
+		t.Errorf("只有 ID 不同的错误应该有相同的指纹: %s %s", a, b)
+	}
+	if a == c {
+		t.Errorf("不同的错误应该有不同的指纹")
+	}
+}
+
+func TestHandler_ErrorFingerprint(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{ErrorFingerprint: true})
+
+	logFailure := func(id int) {
+		logger.Error("query failed", "err", fmt.Errorf("row %d: %w", id, errors.New("deadlock")))
+	}
+	logFailure(1)
+	logFailure(2)
+	logger.Info("no error")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	fp := func(line string) string {
+		i := strings.Index(line, FingerprintKey+"=")
+		if i < 0 {
+			return ""
+		}
+		return line[i:]
+	}
+	if fp(lines[0]) == "" || fp(lines[0]) != fp(lines[1]) {
+		t.Errorf("相同位置的相同错误应该有相同的指纹: %q", lines[:2])
+	}
+	if fp(lines[2]) != "" {
+		t.Errorf("没有错误的记录不应该包含指纹: %s", lines[2])
+	}
+}
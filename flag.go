@@ -0,0 +1,78 @@
+package slogplus
+
+import (
+	"flag"
+	"log/slog"
+	"strings"
+)
+
+// LevelFlag 在 fs 中注册名为 name 的日志级别参数，fs 为 nil 时使用 flag.CommandLine
+// 参数接受 ParseLevel 支持的所有写法，无效的值由 flag 包报告；返回的 LevelVar 可以直接用作 Options.Level
+//
+//	level := slogplus.LevelFlag(nil, "log-level", slog.LevelInfo)
+//	format := slogplus.FormatFlag(nil, "log-format", slogplus.FormatText)
+//	flag.Parse()
+//	slogplus.Setup(os.Stdout, &slogplus.Options{Level: level, FormatVar: format})
+func LevelFlag(fs *flag.FlagSet, name string, def slog.Level) *slog.LevelVar {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	v := new(slog.LevelVar)
+	v.Set(def)
+	fs.Var(levelFlag{v}, name, "日志级别，例如 debug、info、warn、error 或 INFO+2")
+	return v
+}
+
+// FormatFlag 在 fs 中注册名为 name 的输出格式参数，fs 为 nil 时使用 flag.CommandLine
+// 返回的 FormatVar 可以直接用作 Options.FormatVar
+func FormatFlag(fs *flag.FlagSet, name string, def Format) *FormatVar {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	v := NewFormatVar(def)
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = f.String()
+	}
+	fs.Var(formatFlag{v}, name, "日志格式："+strings.Join(names, "、"))
+	return v
+}
+
+// levelFlag 将 slog.LevelVar 适配为 flag.Getter
+type levelFlag struct {
+	v *slog.LevelVar
+}
+
+func (f levelFlag) String() string {
+	if f.v == nil {
+		return ""
+	}
+	return levelName(f.v.Level())
+}
+
+func (f levelFlag) Set(s string) error {
+	level, err := ParseLevel(strings.TrimSpace(s))
+	if err != nil {
+		return err
+	}
+	f.v.Set(level)
+	return nil
+}
+
+func (f levelFlag) Get() any { return f.v.Level() }
+
+// formatFlag 将 FormatVar 适配为 flag.Getter
+type formatFlag struct {
+	v *FormatVar
+}
+
+func (f formatFlag) String() string {
+	if f.v == nil {
+		return ""
+	}
+	return f.v.Format().String()
+}
+
+func (f formatFlag) Set(s string) error { return f.v.UnmarshalText([]byte(s)) }
+
+func (f formatFlag) Get() any { return f.v.Format() }
@@ -0,0 +1,55 @@
+package slogplus
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLevelFlag(t *testing.T) {
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	level := LevelFlag(fs, "log-level", slog.LevelInfo)
+	format := FormatFlag(fs, "log-format", FormatText)
+
+	if level.Level() != slog.LevelInfo || format.Format() != FormatText {
+		t.Errorf("未设置参数时应该使用默认值: %v %v", level, format)
+	}
+	if err := fs.Parse([]string{"-log-level=debug", "-log-format", "JSON"}); err != nil {
+		t.Fatal(err)
+	}
+	if level.Level() != slog.LevelDebug || format.Format() != FormatJSON {
+		t.Errorf("参数解析错误: %v %v", level, format)
+	}
+	if got := fs.Lookup("log-level").Value.(flag.Getter).Get(); got != slog.LevelDebug {
+		t.Errorf("Get() = %v", got)
+	}
+
+	for _, args := range [][]string{{"-log-level=loud"}, {"-log-format=xml"}} {
+		if err := fs.Parse(args); err == nil {
+			t.Errorf("%v 应该返回错误", args)
+		}
+	}
+	if level.Level() != slog.LevelDebug || format.Format() != FormatJSON {
+		t.Error("无效的值不应该修改当前配置")
+	}
+}
+
+func TestLevelFlag_Usage(t *testing.T) {
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	LevelFlag(fs, "log-level", slog.LevelWarn)
+	FormatFlag(fs, "log-format", FormatJSON)
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+
+	out := buf.String()
+	for _, want := range []string{"(default WARN)", "(default json)", "console"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("帮助信息应该包含 %q: %s", want, out)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package slogplus
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// Flusher 是带缓冲、需要在进程退出前刷新的输出
+type Flusher interface {
+	Flush() error
+}
+
+// FlusherFunc 将函数适配为 Flusher
+type FlusherFunc func() error
+
+// Flush 实现 Flusher
+func (f FlusherFunc) Flush() error { return f() }
+
+// flushers 是全局注册的 Flusher 列表，按注册的相反顺序刷新
+var flushers struct {
+	mu   sync.Mutex
+	list []*flusherEntry
+}
+
+type flusherEntry struct {
+	f Flusher
+}
+
+// RegisterFlusher 注册一个在 FlushAll（以及 NotifyShutdown 收到信号时）被刷新的输出
+// slogplus 自带的缓冲输出会自动注册，返回的函数用于取消注册
+func RegisterFlusher(f Flusher) (unregister func()) {
+	e := &flusherEntry{f: f}
+	flushers.mu.Lock()
+	flushers.list = append(flushers.list, e)
+	flushers.mu.Unlock()
+
+	return func() {
+		flushers.mu.Lock()
+		defer flushers.mu.Unlock()
+		for i, x := range flushers.list {
+			if x == e {
+				flushers.list = append(flushers.list[:i], flushers.list[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// FlushAll 按注册的相反顺序刷新所有已注册的输出，返回合并后的错误
+// 包装在外层的 Handler（例如 Async）总是在其输出之后注册，先刷新外层才能把队列中的记录写入内层的缓冲
+func FlushAll() error {
+	flushers.mu.Lock()
+	list := make([]*flusherEntry, len(flushers.list))
+	copy(list, flushers.list)
+	flushers.mu.Unlock()
+
+	var errs []error
+	for _, e := range slices.Backward(list) {
+		if err := e.f.Flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FlushOnDone 在 ctx 结束（例如服务关闭时取消根 context）时刷新 f，f 为 nil 时调用 FlushAll，
+// 返回的 stop 函数取消等待；ctx 结束后 f 只会被刷新一次
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+//	defer stop()
+//	defer slogplus.FlushOnDone(ctx, nil)()
+func FlushOnDone(ctx context.Context, f Flusher) (stop func()) {
+	if f == nil {
+		f = FlusherFunc(FlushAll)
+	}
+	cancel := context.AfterFunc(ctx, func() { f.Flush() })
+	return func() { cancel() }
+}
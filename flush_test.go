@@ -0,0 +1,53 @@
+package slogplus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFlushAll(t *testing.T) {
+	var order []int
+	un1 := RegisterFlusher(FlusherFunc(func() error { order = append(order, 1); return nil }))
+	un2 := RegisterFlusher(FlusherFunc(func() error { order = append(order, 2); return errors.New("boom") }))
+	defer un1()
+
+	if err := FlushAll(); err == nil {
+		t.Errorf("应该返回刷新错误")
+	}
+	if len(order) != 2 || order[0] != 2 || order[1] != 1 {
+		t.Errorf("应该按注册的相反顺序刷新: %v", order)
+	}
+
+	un2()
+	order = nil
+	if err := FlushAll(); err != nil || len(order) != 1 {
+		t.Errorf("取消注册后不应该再刷新: %v %v", order, err)
+	}
+}
+
+func TestFlushOnDone(t *testing.T) {
+	flushed := make(chan struct{}, 2)
+	f := FlusherFunc(func() error { flushed <- struct{}{}; return nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	FlushOnDone(ctx, f)
+	cancel()
+	cancel()
+	select {
+	case <-flushed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("context 结束时应该刷新")
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	stop := FlushOnDone(ctx, f)
+	stop()
+	cancel()
+	select {
+	case <-flushed:
+		t.Error("stop 之后不应该刷新")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -0,0 +1,110 @@
+package slogplus
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Format 表示 Handler 的输出格式
+type Format string
+
+const (
+	// FormatText 默认的简洁文本格式: 2025/11/14 14:03:14 INFO msg=test key=value
+	FormatText Format = ""
+
+	// FormatConsole 面向人阅读的控制台格式，输出为终端时着色并对齐级别列
+	FormatConsole Format = "console"
+
+	// FormatJSON 每条记录一个 JSON 对象，分组属性使用 group.key 作为字段名
+	FormatJSON Format = "json"
+
+	// FormatGELF Graylog 扩展日志格式（GELF 1.1），每条记录一个 JSON 对象
+	FormatGELF Format = "gelf"
+
+	// FormatECS Elastic Common Schema 格式，可以直接写入 Elasticsearch 无需 ingest pipeline
+	FormatECS Format = "ecs"
+
+	// FormatSyslog RFC 5424 syslog 格式，属性输出为结构化数据
+	FormatSyslog Format = "syslog"
+
+	// FormatJournal systemd journald 原生协议，通常通过 NewJournalHandler 使用
+	FormatJournal Format = "journal"
+
+	// FormatGCP Google Cloud Logging 结构化日志格式，用于 Cloud Run、GKE 等环境
+	FormatGCP Format = "gcp"
+
+	// FormatMsgpack MessagePack 二进制格式，体积更小、下游解析更快，可使用 MsgpackDecoder 解码
+	FormatMsgpack Format = "msgpack"
+)
+
+// formats 所有支持的格式，用于解析和校验
+var formats = []Format{FormatText, FormatConsole, FormatJSON, FormatGELF, FormatECS, FormatSyslog, FormatJournal, FormatGCP, FormatMsgpack}
+
+// String 返回格式名称，FormatText 返回 "text"
+func (f Format) String() string {
+	if f == FormatText {
+		return "text"
+	}
+	return string(f)
+}
+
+// ParseFormat 解析格式名称（不区分大小写），"text" 和空字符串都表示 FormatText
+func ParseFormat(s string) (Format, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "text" {
+		return FormatText, nil
+	}
+	for _, f := range formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return FormatText, fmt.Errorf("slogplus: 未知的日志格式 %q", s)
+}
+
+// FormatVar 是一个可以在运行时切换的输出格式，可以安全地并发使用
+// 零值表示 FormatText
+type FormatVar struct {
+	v atomic.Pointer[Format]
+}
+
+// NewFormatVar 创建一个新的可变输出格式
+func NewFormatVar(f Format) *FormatVar {
+	v := new(FormatVar)
+	v.Set(f)
+	return v
+}
+
+// Format 返回当前格式
+func (v *FormatVar) Format() Format {
+	if p := v.v.Load(); p != nil {
+		return *p
+	}
+	return FormatText
+}
+
+// Set 设置格式
+func (v *FormatVar) Set(f Format) {
+	v.v.Store(&f)
+}
+
+// String 实现 fmt.Stringer
+func (v *FormatVar) String() string {
+	return fmt.Sprintf("FormatVar(%s)", v.Format())
+}
+
+// MarshalText 实现 encoding.TextMarshaler
+func (v *FormatVar) MarshalText() ([]byte, error) {
+	return []byte(v.Format().String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler
+func (v *FormatVar) UnmarshalText(data []byte) error {
+	f, err := ParseFormat(string(data))
+	if err != nil {
+		return err
+	}
+	v.Set(f)
+	return nil
+}
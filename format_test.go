@@ -0,0 +1,71 @@
+package slogplus
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{
+		"":        FormatText,
+		"text":    FormatText,
+		"JSON":    FormatJSON,
+		"console": FormatConsole,
+		"gelf":    FormatGELF,
+	} {
+		got, err := ParseFormat(in)
+		if err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Errorf("未知格式应该返回错误")
+	}
+}
+
+func TestHandler_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{Format: FormatJSON}).With("svc", "api").WithGroup("req")
+
+	logger.Info("hello \"world\"", "path", "/users", slog.Group("user", "id", 42))
+
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("输出应该是合法的 JSON: %v\n%s", err, buf.String())
+	}
+	if m["level"] != "INFO" || m["msg"] != `hello "world"` || m["svc"] != "api" || m["req.path"] != "/users" {
+		t.Errorf("JSON 字段不正确: %v", m)
+	}
+	if user, ok := m["req.user"].(map[string]any); !ok || user["id"] != float64(42) {
+		t.Errorf("分组值应该输出为嵌套对象: %v", m["req.user"])
+	}
+}
+
+func TestHandler_FormatVar(t *testing.T) {
+	var buf bytes.Buffer
+	fv := NewFormatVar(FormatText)
+	logger := NewLogger(&buf, &Options{FormatVar: fv}).With("k", "v")
+
+	logger.Info("text")
+	fv.Set(FormatJSON)
+	logger.Info("json")
+	fv.Set(FormatConsole)
+	logger.Info("console")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("应该输出 3 行: %q", buf.String())
+	}
+	if !strings.Contains(lines[0], "INFO k=v msg=text") {
+		t.Errorf("第一行应该是文本格式: %s", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "{") {
+		t.Errorf("第二行应该是 JSON 格式: %s", lines[1])
+	}
+	if !strings.Contains(lines[2], "INFO  k=v msg=console") {
+		t.Errorf("第三行应该是对齐的控制台格式: %s", lines[2])
+	}
+}
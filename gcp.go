@@ -0,0 +1,135 @@
+package slogplus
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+// GCPOptions 定义 Google Cloud Logging 结构化格式的配置
+type GCPOptions struct {
+	// ProjectID 用于生成 logging.googleapis.com/trace 字段，默认读取 GOOGLE_CLOUD_PROJECT 环境变量
+	ProjectID string
+
+	// Trace 从 context 中提取追踪信息（例如从 OpenTelemetry span 中获取）
+	// 未设置或返回空 traceID 时使用记录中的 trace_id、span_id 属性
+	Trace func(ctx context.Context) (traceID, spanID string, sampled bool)
+}
+
+// gcpSeverities 按 syslog 严重级别索引的 Cloud Logging severity
+var gcpSeverities = [...]string{"EMERGENCY", "ALERT", "CRITICAL", "ERROR", "WARNING", "NOTICE", "INFO", "DEBUG"}
+
+// gcpTraceKeys 作为追踪信息来源的属性名
+const (
+	gcpTraceIDKey = "trace_id"
+	gcpSpanIDKey  = "span_id"
+)
+
+// appendGCP 按 Google Cloud Logging 结构化日志格式追加一条记录
+func (h *Handler) appendGCP(buf []byte, ctx context.Context, r slog.Record) []byte {
+	var o GCPOptions
+	if h.opts.GCP != nil {
+		o = *h.opts.GCP
+	}
+
+	buf = append(buf, `{"severity":"`...)
+	buf = append(buf, gcpSeverities[syslogSeverity(r.Level)]...)
+	buf = append(buf, '"')
+	if !r.Time.IsZero() {
+		buf = append(buf, `,"time":"`...)
+		buf = r.Time.AppendFormat(buf, time.RFC3339Nano)
+		buf = append(buf, '"')
+	}
+	buf = append(buf, `,"message":`...)
+	buf = appendJSONString(buf, r.Message)
+
+	if h.opts.AddSource && r.PC != 0 {
+		f := h.frame(r.PC)
+		if f.File != "" {
+			buf = append(buf, `,"logging.googleapis.com/sourceLocation":{"file":`...)
+			buf = appendJSONString(buf, f.File)
+			// line 按 API 定义为 int64，以字符串形式输出
+			buf = append(buf, `,"line":"`...)
+			buf = strconv.AppendInt(buf, int64(f.Line), 10)
+			buf = append(buf, `","function":`...)
+			buf = appendJSONString(buf, f.Function)
+			buf = append(buf, '}')
+		}
+	}
+
+	// 追踪信息
+	var traceID, spanID string
+	var sampled bool
+	if o.Trace != nil && ctx != nil {
+		traceID, spanID, sampled = o.Trace(ctx)
+	}
+	fromAttrs := traceID == ""
+	if fromAttrs {
+		traceID, spanID = gcpTraceFromAttrs(h.attrs, r)
+	}
+	if traceID != "" {
+		project := o.ProjectID
+		if project == "" {
+			project = os.Getenv("GOOGLE_CLOUD_PROJECT")
+		}
+		buf = append(buf, `,"logging.googleapis.com/trace":"`...)
+		if project != "" {
+			buf = append(buf, "projects/"...)
+			buf = appendJSONEscaped(buf, project)
+			buf = append(buf, "/traces/"...)
+		}
+		buf = appendJSONEscaped(buf, traceID)
+		buf = append(buf, '"')
+		if spanID != "" {
+			buf = append(buf, `,"logging.googleapis.com/spanId":`...)
+			buf = appendJSONString(buf, spanID)
+		}
+		if sampled {
+			buf = append(buf, `,"logging.googleapis.com/trace_sampled":true`...)
+		}
+	}
+
+	for _, ba := range h.attrs {
+		if fromAttrs && len(ba.groups) == 0 && isGCPTraceKey(ba.Key) {
+			continue
+		}
+		buf = h.appendJSONAttr(buf, ba.groups, ba.Attr, appendJSONDottedKey, false)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		if fromAttrs && len(h.groups) == 0 && isGCPTraceKey(a.Key) {
+			return true
+		}
+		buf = h.appendJSONAttr(buf, h.groups, a, appendJSONDottedKey, false)
+		return true
+	})
+
+	return append(buf, '}', '\n')
+}
+
+func isGCPTraceKey(key string) bool {
+	return key == gcpTraceIDKey || key == gcpSpanIDKey
+}
+
+// gcpTraceFromAttrs 从顶层的 trace_id、span_id 属性中提取追踪信息
+func gcpTraceFromAttrs(attrs []boundAttr, r slog.Record) (traceID, spanID string) {
+	set := func(a slog.Attr) {
+		switch a.Key {
+		case gcpTraceIDKey:
+			traceID = a.Value.String()
+		case gcpSpanIDKey:
+			spanID = a.Value.String()
+		}
+	}
+	for _, ba := range attrs {
+		if len(ba.groups) == 0 {
+			set(ba.Attr)
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		set(a)
+		return true
+	})
+	return traceID, spanID
+}
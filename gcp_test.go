@@ -0,0 +1,68 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestHandler_GCP(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{
+		Format:    FormatGCP,
+		AddSource: true,
+		GCP:       &GCPOptions{ProjectID: "my-proj"},
+	})
+
+	logger.Warn("slow", "trace_id", "abc123", "span_id", "0001", "path", "/x")
+
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("输出应该是合法的 JSON: %v\n%s", err, buf.String())
+	}
+	want := map[string]any{
+		"severity":                      "WARNING",
+		"message":                       "slow",
+		"logging.googleapis.com/trace":  "projects/my-proj/traces/abc123",
+		"logging.googleapis.com/spanId": "0001",
+		"path":                          "/x",
+	}
+	for k, v := range want {
+		if m[k] != v {
+			t.Errorf("%s = %v, 期望 %v", k, m[k], v)
+		}
+	}
+	if _, ok := m["trace_id"]; ok {
+		t.Errorf("用作追踪信息的属性不应该重复输出")
+	}
+	loc, ok := m["logging.googleapis.com/sourceLocation"].(map[string]any)
+	if !ok || loc["file"] == "" || loc["line"] == "" {
+		t.Errorf("应该包含 sourceLocation: %v", m["logging.googleapis.com/sourceLocation"])
+	}
+}
+
+func TestHandler_GCPTraceFromContext(t *testing.T) {
+	type traceKey struct{}
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{
+		Format: FormatGCP,
+		GCP: &GCPOptions{
+			ProjectID: "p",
+			Trace: func(ctx context.Context) (string, string, bool) {
+				id, _ := ctx.Value(traceKey{}).(string)
+				return id, "s1", true
+			},
+		},
+	})
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "t1")
+	logger.ErrorContext(ctx, "boom")
+
+	var m map[string]any
+	json.Unmarshal(buf.Bytes(), &m)
+	if m["severity"] != "ERROR" || m["logging.googleapis.com/trace"] != "projects/p/traces/t1" ||
+		m["logging.googleapis.com/trace_sampled"] != true {
+		t.Errorf("应该从 context 中提取追踪信息: %s", buf.String())
+	}
+}
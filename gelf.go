@@ -0,0 +1,97 @@
+package slogplus
+
+import (
+	"log/slog"
+	"strconv"
+)
+
+// syslogSeverity 将 slog 级别映射为 syslog 严重级别
+// DEBUG=7，INFO=6，INFO+2=5(notice)，WARN=4，ERROR=3，更高级别依次为 2(critical)、1(alert)、0(emergency)
+func syslogSeverity(level slog.Level) int {
+	switch {
+	case level < slog.LevelInfo:
+		return 7
+	case level < slog.LevelInfo+2:
+		return 6
+	case level < slog.LevelWarn:
+		return 5
+	case level < slog.LevelError:
+		return 4
+	case level < slog.LevelError+4:
+		return 3
+	case level < slog.LevelError+8:
+		return 2
+	case level < slog.LevelError+12:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// appendGELF 按 GELF 1.1 格式追加一条记录
+func (h *Handler) appendGELF(buf []byte, r slog.Record) []byte {
+	buf = append(buf, `{"version":"1.1","host":`...)
+	buf = appendJSONString(buf, h.opts.Hostname)
+	buf = append(buf, `,"short_message":`...)
+	buf = appendJSONString(buf, r.Message)
+
+	if !r.Time.IsZero() {
+		// 秒为单位的 Unix 时间戳，保留毫秒
+		buf = append(buf, `,"timestamp":`...)
+		ms := r.Time.UnixMilli()
+		buf = strconv.AppendInt(buf, ms/1000, 10)
+		buf = append(buf, '.')
+		buf = appendInt(buf, int(ms%1000), 3)
+	}
+
+	buf = append(buf, `,"level":`...)
+	buf = strconv.AppendInt(buf, int64(syslogSeverity(r.Level)), 10)
+
+	if h.opts.AddSource && r.PC != 0 {
+		f := h.frame(r.PC)
+		if f.File != "" {
+			buf = append(buf, `,"_file":`...)
+			buf = appendJSONString(buf, f.File)
+			buf = append(buf, `,"_line":`...)
+			buf = strconv.AppendInt(buf, int64(f.Line), 10)
+		}
+	}
+
+	buf = h.appendBound(buf, FormatGELF)
+	r.Attrs(func(a slog.Attr) bool {
+		buf = h.appendJSONAttr(buf, h.groups, a, appendGELFKey, true)
+		return true
+	})
+
+	return append(buf, '}', '\n')
+}
+
+// appendGELFKey 追加 GELF 附加字段名
+// 字段名以 '_' 开头，只允许字母、数字、'_'、'.'、'-'，保留字段 _id 改为 _id_
+func appendGELFKey(buf []byte, groups []string, key string) []byte {
+	start := len(buf)
+	buf = append(buf, '_')
+	for _, g := range groups {
+		buf = appendGELFName(buf, g)
+		buf = append(buf, '.')
+	}
+	buf = appendGELFName(buf, key)
+	if string(buf[start:]) == "_id" {
+		buf = append(buf, '_')
+	}
+	return buf
+}
+
+func appendGELFName(buf []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9',
+			c == '_', c == '.', c == '-':
+			buf = append(buf, c)
+		default:
+			buf = append(buf, '_')
+		}
+	}
+	return buf
+}
@@ -0,0 +1,54 @@
+package slogplus
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestHandler_GELF(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{Format: FormatGELF, Hostname: "web-1"})
+
+	logger.With("id", 7).WithGroup("req").Warn("slow request", "path", "/api", "took ms", 120)
+
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("输出应该是合法的 JSON: %v\n%s", err, buf.String())
+	}
+
+	want := map[string]any{
+		"version":       "1.1",
+		"host":          "web-1",
+		"short_message": "slow request",
+		"level":         float64(4),
+		"_id_":          float64(7),
+		"_req.path":     "/api",
+		"_req.took_ms":  float64(120),
+	}
+	for k, v := range want {
+		if m[k] != v {
+			t.Errorf("%s = %v, 期望 %v", k, m[k], v)
+		}
+	}
+	if _, ok := m["timestamp"].(float64); !ok {
+		t.Errorf("应该包含数字类型的 timestamp: %v", m["timestamp"])
+	}
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	tests := map[slog.Level]int{
+		slog.LevelDebug:     7,
+		slog.LevelInfo:      6,
+		slog.LevelInfo + 2:  5,
+		slog.LevelWarn:      4,
+		slog.LevelError:     3,
+		slog.LevelError + 4: 2,
+	}
+	for level, want := range tests {
+		if got := syslogSeverity(level); got != want {
+			t.Errorf("syslogSeverity(%v) = %d, 期望 %d", level, got, want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"sync"
+)
+
+// GELFWriterOptions 定义 GELF UDP 输出的配置
+type GELFWriterOptions struct {
+	// ChunkSize 单个 UDP 包的最大字节数（含 12 字节分块头），默认 1420
+	ChunkSize int
+
+	// Compress 是否使用 gzip 压缩每条消息
+	Compress bool
+}
+
+// GELFWriter 将 GELF 消息通过 UDP 发送给 Graylog
+// 每次 Write 视为一条完整消息，超过 ChunkSize 时按 GELF 分块协议拆分
+type GELFWriter struct {
+	opts GELFWriterOptions
+	conn net.Conn
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+	zw  *gzip.Writer
+}
+
+// GELF 分块协议常量
+const (
+	gelfChunkHeaderSize = 12
+	gelfMaxChunks       = 128
+)
+
+// ErrGELFMessageTooLarge 表示消息超过了 GELF 允许的最大分块数
+var ErrGELFMessageTooLarge = errors.New("slogplus: GELF 消息超过 128 个分块")
+
+// NewGELFWriter 创建一个发送到 addr 的 GELF UDP 输出
+func NewGELFWriter(addr string, opts *GELFWriterOptions) (*GELFWriter, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	w := &GELFWriter{conn: conn}
+	if opts != nil {
+		w.opts = *opts
+	}
+	if w.opts.ChunkSize <= gelfChunkHeaderSize {
+		w.opts.ChunkSize = 1420
+	}
+	if w.opts.Compress {
+		w.zw = gzip.NewWriter(&w.buf)
+	}
+	return w, nil
+}
+
+// Write 发送一条 GELF 消息，末尾的换行符会被去掉
+func (w *GELFWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	msg := bytes.TrimRight(p, "\n")
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.zw != nil {
+		w.buf.Reset()
+		w.zw.Reset(&w.buf)
+		w.zw.Write(msg)
+		if err := w.zw.Close(); err != nil {
+			return 0, err
+		}
+		msg = w.buf.Bytes()
+	}
+
+	if len(msg) <= w.opts.ChunkSize {
+		if _, err := w.conn.Write(msg); err != nil {
+			return 0, err
+		}
+		return n, nil
+	}
+
+	payload := w.opts.ChunkSize - gelfChunkHeaderSize
+	count := (len(msg) + payload - 1) / payload
+	if count > gelfMaxChunks {
+		return 0, ErrGELFMessageTooLarge
+	}
+
+	chunk := make([]byte, 0, w.opts.ChunkSize)
+	var id [8]byte
+	binary.BigEndian.PutUint64(id[:], rand.Uint64())
+	for i := 0; i < count; i++ {
+		end := min((i+1)*payload, len(msg))
+		chunk = append(chunk[:0], 0x1e, 0x0f)
+		chunk = append(chunk, id[:]...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, msg[i*payload:end]...)
+		if _, err := w.conn.Write(chunk); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+// Close 关闭 UDP 连接
+func (w *GELFWriter) Close() error {
+	return w.conn.Close()
+}
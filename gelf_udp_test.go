@@ -0,0 +1,64 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGELFWriter_Chunked(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("无法监听 UDP: %v", err)
+	}
+	defer pc.Close()
+
+	w, err := NewGELFWriter(pc.LocalAddr().String(), &GELFWriterOptions{ChunkSize: 64, Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	logger := NewLogger(w, &Options{Format: FormatGELF, Hostname: "h"})
+	long := strings.Repeat("abcdefghij", 30)
+	logger.Info(long)
+
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	chunks := map[byte][]byte{}
+	var count byte
+	for {
+		b := make([]byte, 128)
+		n, _, err := pc.ReadFrom(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b = b[:n]
+		if b[0] != 0x1e || b[1] != 0x0f {
+			t.Fatalf("分块头不正确: %x", b[:2])
+		}
+		count = b[11]
+		chunks[b[10]] = b[gelfChunkHeaderSize:]
+		if len(chunks) == int(count) {
+			break
+		}
+	}
+
+	var msg []byte
+	for i := byte(0); i < count; i++ {
+		msg = append(msg, chunks[i]...)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, _ := io.ReadAll(zr)
+	if !strings.Contains(string(plain), `"short_message":"`+long+`"`) {
+		t.Errorf("重组后的消息不正确: %s", plain)
+	}
+}
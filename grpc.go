@@ -0,0 +1,60 @@
+package slogplus
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// GRPCOptions 定义 gRPC 元数据提取的配置
+type GRPCOptions struct {
+	// Keys 需要提取的元数据键（不区分大小写），例如 x-tenant、x-client-version
+	// 属性名为小写并将 '-' 替换为 '_'；authorization、cookie 始终输出为 [REDACTED]，以 -bin 结尾的二进制元数据被忽略
+	// x-request-id 通过 WithRequestID 存入 context，属性名为 request_id
+	Keys []string
+}
+
+// sensitiveMetadata 即使被加入 Keys 也始终脱敏的元数据键
+var sensitiveMetadata = map[string]bool{
+	"authorization": true,
+	"cookie":        true,
+}
+
+// GRPCMetadata 将 gRPC 请求元数据中配置的键通过 AppendCtx 存入 context，
+// 之后使用该 context 记录的日志都会带上这些属性，作用与 HTTPMiddleware 的 Headers 相同
+// slogplus 不依赖 gRPC，md 即 metadata.MD，在拦截器中使用:
+//
+//	opts := &slogplus.GRPCOptions{Keys: []string{"x-request-id", "x-tenant"}}
+//	grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+//		md, _ := metadata.FromIncomingContext(ctx)
+//		return h(slogplus.GRPCMetadata(ctx, md, opts), req)
+//	}))
+func GRPCMetadata(ctx context.Context, md map[string][]string, opts *GRPCOptions) context.Context {
+	if opts == nil || len(md) == 0 {
+		return ctx
+	}
+
+	var attrs []any
+	for _, key := range opts.Keys {
+		key = strings.ToLower(key)
+		if strings.HasSuffix(key, "-bin") {
+			continue
+		}
+		values := md[key]
+		if len(values) == 0 || values[0] == "" {
+			continue
+		}
+		v := strings.Join(values, ",")
+		switch {
+		case sensitiveMetadata[key]:
+			v = RedactedValue
+		case key == "x-request-id":
+			if validRequestID(v) {
+				ctx = WithRequestID(ctx, v)
+			}
+			continue
+		}
+		attrs = append(attrs, slog.String(strings.ReplaceAll(key, "-", "_"), v))
+	}
+	return AppendCtx(ctx, attrs...)
+}
@@ -0,0 +1,37 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestGRPCMetadata(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, &Options{TimeFormat: "-"}))
+
+	md := map[string][]string{
+		"x-request-id":  {"req-1"},
+		"x-tenant":      {"acme"},
+		"x-tags":        {"a", "b"},
+		"authorization": {"Bearer secret"},
+		"trace-bin":     {"\x00\x01"},
+	}
+	opts := &GRPCOptions{Keys: []string{"X-Request-ID", "x-tenant", "x-tags", "authorization", "trace-bin", "x-missing"}}
+	ctx := GRPCMetadata(context.Background(), md, opts)
+	logger.InfoContext(ctx, "rpc")
+
+	want := "- INFO msg=rpc request_id=req-1 x_tenant=acme x_tags=a,b authorization=[REDACTED]\n"
+	if got := buf.String(); got != want {
+		t.Errorf("元数据属性错误:\n got %q\nwant %q", got, want)
+	}
+	if RequestID(ctx) != "req-1" {
+		t.Error("x-request-id 应该存为请求 ID")
+	}
+
+	base := context.Background()
+	if GRPCMetadata(base, md, nil) != base || GRPCMetadata(base, nil, opts) != base {
+		t.Error("未配置或没有元数据时应该原样返回 context")
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"context"
 	"io"
 	"log/slog"
-	"runtime"
+	"os"
+	"regexp"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,11 +18,27 @@ import (
 // 使用 buffer pool 实现零内存分配，性能优于标准库 TextHandler
 type Handler struct {
 	opts   Options
-	mu     sync.Mutex
+	mu     *sync.Mutex // 只保护写入，由 WithAttrs、WithGroup 派生的 Handler 共享
 	out    io.Writer
-	pool   *sync.Pool
-	groups []string      // 分组名称
-	attrs  []slog.Attr   // 预设属性
+	pool   *bufferPool
+	groups []string                   // 分组名称
+	attrs  []boundAttr                // 预设属性
+	prefix atomic.Pointer[attrPrefix] // 预设属性编码后的缓存，见 appendBound
+	color  bool                       // 是否输出颜色（Color 开启且输出为终端）
+	tty    bool                       // 输出是否为终端
+
+	sourcePaths []sourceRewrite // 源码路径替换规则，最长前缀优先
+	levelNames  map[slog.Level]string
+	rewriter    *attrRewriter // 脱敏等属性改写，未配置时为 nil
+	timePlan    timePlan      // 编译后的 TimeFormat
+	level       levelReader   // Options.Level 的快速读取
+	stats       *handlerStats // 运行统计，由派生的 Handler 共享
+}
+
+// boundAttr 是通过 WithAttrs 添加的属性及其添加时所在的分组
+type boundAttr struct {
+	groups []string
+	slog.Attr
 }
 
 // Options 定义 Handler 的配置选项
@@ -35,157 +53,496 @@ type Options struct {
 	// AddSource 是否添加源代码位置信息
 	AddSource bool
 
+	// CallerSkip 源代码位置向外跳过的调用层数，用于统一的日志封装函数，
+	// 使源代码位置显示为封装函数的调用方；也可以在封装函数中调用 Helper
+	CallerSkip int
+
+	// SourcePaths 源码路径前缀替换表，最长的匹配前缀优先
+	// 用于 vendor 或 bazel 构建的二进制，将构建路径映射为仓库中的规范路径，
+	// 例如 {"/home/builder/go/src/": "", "bazel-out/k8-fastbuild/bin/": "github.com/acme/repo/"}
+	SourcePaths map[string]string
+
+	// SourceFormat 源代码位置中文件路径的输出形式，默认为完整路径
+	// 可选 SourcePackage（db/conn.go）、SourceFile（conn.go）、SourceModule（internal/db/conn.go）
+	SourceFormat SourceFormat
+
+	// SourceLink 源码位置的终端超链接模板，设置后在终端中以 OSC 8 超链接输出源码位置
+	// 支持 {path}（本地绝对路径）、{line}、{host} 占位符，可以使用 SourceLinkFile、SourceLinkVSCode，
+	// 也可以是其它编辑器的协议，例如 "idea://open?file={path}&line={line}"；仅文本格式生效
+	SourceLink string
+
+	// AllowKeys 设置后只输出列表中的属性，其余属性全部丢弃，时间、级别、消息和源代码位置不受影响
+	// 规则与 RedactKeys 相同；列出分组名时整个分组保留，列出 group.key 时只保留分组中的该属性
+	AllowKeys []string
+
+	// RedactKeys 需要脱敏的属性名，匹配的属性值输出为 [REDACTED]，不区分大小写
+	// 支持 * 和 ? 通配符，例如 "password"、"*token*"；含 '.' 的规则匹配完整路径，例如 "user.email"
+	// 在所有输出格式中生效，包括分组内的属性和 LogValuer 展开后的属性，匹配的分组整体脱敏
+	RedactKeys []string
+
+	// Masks 扫描消息和字符串属性值，将正则匹配的部分替换为 [REDACTED]，用于捕获混在自由文本中的凭据
+	// 正则带有捕获组时只替换第一个捕获组；可以使用 DefaultMasks 或 MaskBearerToken 等内置正则
+	Masks []*regexp.Regexp
+
+	// PII 设置后检测消息和字符串属性值中的邮箱、银行卡号和电话号码并部分掩码，例如 ****1234
+	PII *PII
+
+	// HMAC 设置后将指定属性的值替换为带密钥的截断哈希，使用户 ID 等字段可以关联但不暴露原始值
+	HMAC *HMAC
+
+	// MaxValueLen 大于 0 时，超过该字节数的字符串和 []byte 属性值被截断并追加 …(+N bytes)，
+	// 防止单个超大的属性撑大日志量或破坏下游解析；截断在脱敏之后进行
+	MaxValueLen int
+
+	// RevealSecrets 为 true 时 Secret 包装的值输出原始值而不是 [REDACTED]
+	// 仅用于本地调试，不要在生产环境开启；RedactKeys 等其它脱敏规则仍然生效
+	RevealSecrets bool
+
+	// Redactor 自定义脱敏逻辑，在 AllowKeys、RedactKeys、HMAC、Masks、PII 之后执行，多个时使用 Redactors 组合
+	Redactor Redactor
+
 	// ReplaceAttr 允许自定义属性的处理
 	// 如果返回空 Attr，该属性将被忽略
 	ReplaceAttr func(groups []string, a slog.Attr) slog.Attr
+
+	// Format 输出格式，默认为 FormatText
+	Format Format
+
+	// FormatVar 设置后覆盖 Format，用于在运行时切换输出格式
+	FormatVar *FormatVar
+
+	// Hostname 主机名，用于 GELF 等需要主机字段的格式，默认为 os.Hostname()
+	Hostname string
+
+	// Syslog FormatSyslog 格式的配置
+	Syslog *SyslogOptions
+
+	// GCP FormatGCP 格式的配置
+	GCP *GCPOptions
+
+	// ErrorFingerprint 是否为包含错误属性的记录添加 error.fingerprint 属性
+	// 相同类型、相似信息、相同位置的错误具有相同的指纹，便于日志平台聚合
+	ErrorFingerprint bool
+
+	// TeeRaw 设置后每条记录编码完成的字节会原样写入一份，无需重新编码
+	// 可用于环形缓冲区、调试管道等，写入错误会被忽略
+	TeeRaw io.Writer
+
+	// OmitNil 是否省略值为 nil 的属性
+	// 包括 slog.Any(nil)、零值 slog.Value 以及 nil 指针、map、切片等；
+	// 默认输出 key=<nil>（JSON 类格式为 null）
+	OmitNil bool
+
+	// Filter 设置后只输出满足表达式的记录，表达式可以引用预设属性
+	// 例如 MustParseFilter(`level >= WARN || attrs["component"] == "db"`)
+	Filter *FilterExpr
+
+	// Quota 按属性值统计每个窗口内的记录数和字节数，超出配额的记录被丢弃
+	Quota *Quota
+
+	// WriteTimeout 单条记录的写入时限，与 context 的截止时间取较早者
+	// 仅对实现了 DeadlineWriter 的输出（NetWriter、SyslogWriter 等）生效，
+	// 超时的记录按输出的策略暂存或丢弃，远程输出变慢时不会拖慢请求
+	WriteTimeout time.Duration
+
+	// MaxBufferSize 编码 buffer 放回缓存池的最大容量，默认为 DefaultMaxBufferSize（64KiB）
+	// 超长日志使用的大 buffer 在写入后直接丢弃，不会长期占用内存
+	MaxBufferSize int
+
+	// Capture 突发抓取模式，触发后临时放行 Debug 记录并写入单独的输出
+	Capture *Capture
+
+	// Numbers 浮点数、时长和大整数的输出形式，默认使用 Go 的最短表示
+	Numbers *NumberFormat
+
+	// Baggage 设置后将 OpenTelemetry baggage 条目复制到日志属性中
+	Baggage *Baggage
+
+	// CtxRemaining 设置后，达到该级别且 context 带有截止时间的记录会附加 ctx_remaining 属性，
+	// 值为距离截止时间的剩余时长（已超时为负数），用于排查超时问题，通常设置为 slog.LevelWarn
+	CtxRemaining slog.Leveler
+
+	// StackTrace 设置后，达到该级别的记录会附加当前 goroutine 的调用栈 stack，通常设置为 slog.LevelError
+	// 文本格式输出为多行，JSON 格式输出为数组；也可以使用 Stack() 手动附加
+	StackTrace slog.Leveler
+
+	// Humanize 设置后 FormatConsole 中的数字加千位分隔符，时长和字节大小输出为易读形式
+	// 只影响控制台格式，结构化输出保持原始数值
+	Humanize *Humanize
+
+	// LevelNames 覆盖级别的输出名称，例如 {slog.LevelInfo + 2: "NOTICE", LevelAudit: "AUDIT"}，
+	// 未列出的级别使用 RegisterLevel 注册的名称或 slog 的默认名称；键在创建 Handler 时求值
+	LevelNames map[slog.Leveler]string
+
+	// Logfmt 是否按 logfmt 规范输出
+	// 开启后时间和级别输出为 time=、level= 键值对，包含空格、'='、引号或控制字符的值会加引号并转义，
+	// 分组属性展开为 group.key=value，输出可以被 go-logfmt、Grafana 等工具正确解析
+	Logfmt bool
+
+	// Color 是否为级别名称着色并淡化时间戳
+	// 仅在输出为终端时生效，设置了 NO_COLOR 环境变量时禁用
+	Color bool
+}
+
+// New 创建一个新的 Handler，配置可以是 *Options，也可以是 WithLevel 等函数式配置项
+// 无效的配置项按默认值处理，需要得到错误时使用 NewE，错误说明见 Options.Validate
+func New(out io.Writer, opts ...Option) *Handler {
+	o := applyOptions(opts)
+	o.normalize()
+	return newHandler(out, o)
+}
+
+// NewE 与 New 相同，但配置无效时返回 Options.Validate 的错误，
+// 适用于配置来自配置文件、环境变量等外部输入的场景
+func NewE(out io.Writer, opts ...Option) (*Handler, error) {
+	o := applyOptions(opts)
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	return newHandler(out, o), nil
 }
 
-// New 创建一个新的 Handler
-func New(out io.Writer, opts *Options) *Handler {
+// newHandler 使用已经检查过的配置创建 Handler
+func newHandler(out io.Writer, opts Options) *Handler {
 	h := &Handler{
-		out: out,
-		pool: &sync.Pool{
-			New: func() interface{} {
-				// 预分配 256 字节，大多数日志都够用
-				b := make([]byte, 0, 256)
-				return &b
-			},
-		},
-	}
-	
-	if opts != nil {
-		h.opts = *opts
-	}
-	
+		opts:  opts,
+		mu:    new(sync.Mutex),
+		out:   out,
+		stats: new(handlerStats),
+	}
+
 	// 设置默认值
 	if h.opts.TimeFormat == "" {
 		h.opts.TimeFormat = "2006/01/02 15:04:05"
 	}
-	
+
+	if h.opts.Hostname == "" {
+		h.opts.Hostname, _ = os.Hostname()
+	}
+
+	h.pool = newBufferPool(h.opts.MaxBufferSize)
+	h.timePlan = compileTimePlan(h.opts.TimeFormat)
+	h.level = newLevelReader(h.opts.Level)
+	h.sourcePaths = newSourceRewrites(h.opts.SourcePaths)
+	h.rewriter = newAttrRewriter(&h.opts)
+	if len(h.opts.LevelNames) > 0 {
+		h.levelNames = make(map[slog.Level]string, len(h.opts.LevelNames))
+		for l, name := range h.opts.LevelNames {
+			h.levelNames[l.Level()] = name
+		}
+	}
+	h.tty = isTerminal(out)
+	h.color = h.opts.Color && h.tty
+
 	return h
 }
 
-// Enabled 判断是否应该记录该级别的日志
-func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
-	minLevel := slog.LevelInfo
-	if h.opts.Level != nil {
-		minLevel = h.opts.Level.Level()
+// Enabled 判断是否应该记录该级别的日志，context 中通过 WithMinLevel 设置的级别优先
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.opts.Capture.Active() && level >= h.opts.Capture.level() {
+		return true
+	}
+	return level >= h.minLevel(ctx)
+}
+
+// minLevel 返回主输出的最低级别
+func (h *Handler) minLevel(ctx context.Context) slog.Level {
+	if level, ok := MinLevelFromContext(ctx); ok {
+		return level
 	}
-	return level >= minLevel
+	return h.level.load()
 }
 
 // Handle 处理日志记录
 func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
 	// 从 pool 获取 buffer
-	bufp := h.pool.Get().(*[]byte)
-	buf := (*bufp)[:0] // 重置长度但保留容量
+	bufp := h.pool.get()
+	buf := *bufp
 	defer func() {
 		*bufp = buf
-		h.pool.Put(bufp)
+		h.pool.put(bufp)
 	}()
 
+	var ok bool
+	if buf, ok = h.encode(ctx, r, buf); !ok {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.opts.TeeRaw != nil {
+		// 镜像输出的错误不影响主输出
+		h.opts.TeeRaw.Write(buf)
+	}
+
+	n, err := h.write(ctx, buf)
+	h.stats.written(n, err, r.Level)
+	return err
+}
+
+// encode 处理并编码一条记录，追加到 buf；返回 false 表示记录被过滤、超出配额或只写入了 Capture
+// 编码在锁外并发进行，只有写入是串行的
+func (h *Handler) encode(ctx context.Context, r slog.Record, buf []byte) ([]byte, bool) {
+	r, ok := h.prepare(ctx, r)
+	if !ok {
+		h.stats.dropped.Add(1)
+		return buf, false
+	}
+
+	buf = h.appendRecord(buf, ctx, r)
+
+	if q := h.opts.Quota; q != nil {
+		ok, exceeded := q.allow(q.value(&r, h.groups, h.attrs), len(buf))
+		if len(exceeded) > 0 {
+			summaries := make([][]byte, len(exceeded))
+			for i, u := range exceeded {
+				summaries[i] = h.appendRecord(nil, ctx, q.summaryRecord(u))
+			}
+			h.mu.Lock()
+			for _, b := range summaries {
+				h.out.Write(b)
+			}
+			h.mu.Unlock()
+		}
+		if !ok {
+			h.stats.dropped.Add(1)
+			return buf, false
+		}
+	}
+
+	if h.opts.Capture.Active() {
+		h.opts.Capture.write(buf)
+		if r.Level < h.minLevel(ctx) {
+			return buf, false
+		}
+	}
+	return buf, true
+}
+
+// writeBatch 将多条编码后的记录合并为一次写入，levels 为各条记录的级别，用于统计
+// 输出为网络连接时使用 net.Buffers（writev），否则拼接为一个 buffer 后调用一次 Write
+func (h *Handler) writeBatch(bufs [][]byte, levels []slog.Level) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	if h.opts.TeeRaw != nil {
+		for _, b := range bufs {
+			h.opts.TeeRaw.Write(b)
+		}
+	}
+	if ok, err := writeVectored(h.out, bufs); ok {
+		var n int
+		for _, b := range bufs {
+			n += len(b)
+		}
+		h.stats.written(n, err, levels...)
+		return err
+	}
+
+	bufp := h.pool.get()
+	buf := *bufp
+	for _, b := range bufs {
+		buf = append(buf, b...)
+	}
+	// 批量写入的记录来自后台 goroutine，没有请求的截止时间，只受 WriteTimeout 限制
+	n, err := h.write(context.Background(), buf)
+	h.stats.written(n, err, levels...)
+	*bufp = buf
+	h.pool.put(bufp)
+	return err
+}
+
+// prepare 在编码前处理记录：添加 context 中的属性和错误指纹，按 Filter 过滤后脱敏，返回 false 表示丢弃
+func (h *Handler) prepare(ctx context.Context, r slog.Record) (slog.Record, bool) {
+	if h.opts.AddSource {
+		r = adjustCaller(r, h.opts.CallerSkip)
+	}
+	r = withCtxAttrs(ctx, r)
+	r = h.opts.Baggage.withBaggage(ctx, r)
+	if h.opts.CtxRemaining != nil && r.Level >= h.opts.CtxRemaining.Level() {
+		r = withCtxRemaining(ctx, r)
+	}
+	if h.opts.ErrorFingerprint {
+		r = fingerprintRecord(r)
+	}
+	if h.opts.StackTrace != nil && r.Level >= h.opts.StackTrace.Level() {
+		r = withStack(r)
+	}
+	if h.opts.Filter != nil {
+		// 取地址的副本只在设置了 Filter 时逃逸到堆上，未设置时 r 不分配内存
+		fr := r
+		if !h.opts.Filter.match(&fr, h.groups, h.attrs) {
+			return r, false
+		}
+	}
+	if h.rewriter != nil {
+		r = h.rewriter.record(h.groups, r)
+	}
+	return r, true
+}
+
+// appendRecord 按当前输出格式追加一条记录
+func (h *Handler) appendRecord(buf []byte, ctx context.Context, r slog.Record) []byte {
+	switch f := h.format(); f {
+	case FormatJSON:
+		return h.appendJSON(buf, r)
+	case FormatGELF:
+		return h.appendGELF(buf, r)
+	case FormatECS:
+		return h.appendECS(buf, r)
+	case FormatSyslog:
+		return h.appendSyslog(buf, r)
+	case FormatJournal:
+		return h.appendJournal(buf, r)
+	case FormatGCP:
+		return h.appendGCP(buf, ctx, r)
+	case FormatMsgpack:
+		return h.appendMsgpack(buf, r)
+	default:
+		return h.appendText(buf, r, f)
+	}
+}
+
+// format 返回当前的输出格式
+func (h *Handler) format() Format {
+	if h.opts.FormatVar != nil {
+		return h.opts.FormatVar.Format()
+	}
+	return h.opts.Format
+}
+
+// appendText 按文本格式追加一条记录，f 为 FormatText 或 FormatConsole
+func (h *Handler) appendText(buf []byte, r slog.Record, f Format) []byte {
+	console := f == FormatConsole
+	color := h.color || (console && h.tty)
+
 	// 1. 输出时间
 	if h.opts.TimeFormat != "" && !r.Time.IsZero() {
-		buf = h.appendTime(buf, r.Time)
+		if h.opts.Logfmt {
+			buf = append(buf, "time="...)
+			buf = appendLogfmtString(buf, string(h.appendTime(nil, r.Time)))
+		} else {
+			if color {
+				buf = append(buf, ansiDim...)
+			}
+			buf = h.appendTime(buf, r.Time)
+			if color {
+				buf = append(buf, ansiReset...)
+			}
+		}
 		buf = append(buf, ' ')
 	}
 
 	// 2. 输出日志级别
-	level := r.Level.String()
+	if h.opts.Logfmt {
+		buf = append(buf, "level="...)
+	}
+	if color {
+		buf = append(buf, levelColor(r.Level)...)
+	}
+	level := h.levelName(r.Level)
 	buf = append(buf, level...)
-	buf = append(buf, ' ') // 固定一个空格
+	if console {
+		// 控制台格式对齐级别列
+		for i := len(level); i < 5; i++ {
+			buf = append(buf, ' ')
+		}
+	}
+	if color {
+		buf = append(buf, ansiReset...)
+	}
 
 	// 3. 输出源代码位置（如果启用）
 	if h.opts.AddSource && r.PC != 0 {
-		fs := runtime.CallersFrames([]uintptr{r.PC})
-		f, _ := fs.Next()
+		f := callerFrame(r.PC)
 		if f.File != "" {
-			buf = append(buf, "source="...)
-			buf = append(buf, f.File...)
-			buf = append(buf, ':')
-			buf = strconv.AppendInt(buf, int64(f.Line), 10)
-			buf = append(buf, ' ')
+			file := h.sourceFile(f)
+			buf = append(buf, " source="...)
+			if h.opts.Logfmt {
+				buf = appendLogfmtString(buf, file+":"+strconv.Itoa(f.Line))
+			} else {
+				link := h.opts.SourceLink != "" && h.tty
+				if link {
+					buf = h.appendLinkStart(buf, f.File, f.Line)
+				}
+				buf = append(buf, file...)
+				buf = append(buf, ':')
+				buf = strconv.AppendInt(buf, int64(f.Line), 10)
+				if link {
+					buf = append(buf, osc8Start+osc8End...)
+				}
+			}
 		}
 	}
 
 	// 4. 输出预设的属性（通过 WithAttrs 添加的）
-	for _, attr := range h.attrs {
-		buf = h.appendAttr(buf, h.groups, attr)
-	}
+	buf = h.appendBound(buf, f)
 
 	// 5. 输出消息
-	buf = append(buf, "msg="...)
-	buf = append(buf, r.Message...)
+	buf = append(buf, " msg="...)
+	if h.opts.Logfmt {
+		buf = appendLogfmtString(buf, r.Message)
+	} else {
+		buf = append(buf, r.Message...)
+	}
 
 	// 6. 输出其他属性
 	r.Attrs(func(a slog.Attr) bool {
-		buf = h.appendAttr(buf, h.groups, a)
+		buf = h.appendAttr(buf, h.groups, a, console)
 		return true
 	})
 
 	// 7. 换行
-	buf = append(buf, '\n')
-
-	_, err := h.out.Write(buf)
-	return err
+	return append(buf, '\n')
 }
 
-// appendTime 追加格式化的时间
+// appendTime 追加格式化的时间，TimeFormat 由 New 编译为 timePlan，不支持的格式使用 time.AppendFormat
 func (h *Handler) appendTime(buf []byte, t time.Time) []byte {
-	// 对于标准格式，手动格式化以提高性能
-	if h.opts.TimeFormat == "2006/01/02 15:04:05" {
-		year, month, day := t.Date()
-		hour, min, sec := t.Clock()
-		
-		buf = appendInt(buf, year, 4)
-		buf = append(buf, '/')
-		buf = appendInt(buf, int(month), 2)
-		buf = append(buf, '/')
-		buf = appendInt(buf, day, 2)
-		buf = append(buf, ' ')
-		buf = appendInt(buf, hour, 2)
-		buf = append(buf, ':')
-		buf = appendInt(buf, min, 2)
-		buf = append(buf, ':')
-		buf = appendInt(buf, sec, 2)
-		return buf
+	if h.timePlan != nil {
+		return h.timePlan.append(buf, t)
 	}
-	
-	// 自定义格式使用标准库
-	return append(buf, t.Format(h.opts.TimeFormat)...)
+	return t.AppendFormat(buf, h.opts.TimeFormat)
 }
 
-// appendAttr 追加一个属性
-func (h *Handler) appendAttr(buf []byte, groups []string, a slog.Attr) []byte {
+// appendAttr 追加一个属性，console 表示当前为 FormatConsole
+func (h *Handler) appendAttr(buf []byte, groups []string, a slog.Attr, console bool) []byte {
 	// 调用 ReplaceAttr（如果设置）
 	if h.opts.ReplaceAttr != nil {
 		a = h.opts.ReplaceAttr(groups, a)
 	}
-	
+	a.Value = a.Value.Resolve()
+
 	// 空属性跳过
-	if a.Equal(slog.Attr{}) {
+	if a.Equal(slog.Attr{}) || h.omitNil(a.Value) {
 		return buf
 	}
-	
+
+	if h.opts.Logfmt {
+		return h.appendLogfmtAttr(buf, groups, a)
+	}
+
 	buf = append(buf, ' ')
-	
+
 	// 处理分组
 	for _, g := range groups {
 		buf = append(buf, g...)
 		buf = append(buf, '.')
 	}
-	
+
 	buf = append(buf, a.Key...)
 	buf = append(buf, '=')
+	if console && h.opts.Humanize != nil {
+		if b, ok := h.opts.Humanize.appendValue(buf, a); ok {
+			return b
+		}
+	}
 	return h.appendValue(buf, a.Value)
 }
 
 // appendValue 将值追加到 buffer
 func (h *Handler) appendValue(buf []byte, v slog.Value) []byte {
-	switch v.Kind() {
+	switch v = v.Resolve(); v.Kind() {
 	case slog.KindString:
 		return append(buf, v.String()...)
 	case slog.KindInt64:
@@ -193,10 +550,13 @@ func (h *Handler) appendValue(buf []byte, v slog.Value) []byte {
 	case slog.KindUint64:
 		return strconv.AppendUint(buf, v.Uint64(), 10)
 	case slog.KindFloat64:
-		return strconv.AppendFloat(buf, v.Float64(), 'g', -1, 64)
+		return h.opts.Numbers.appendFloat(buf, v.Float64())
 	case slog.KindBool:
 		return strconv.AppendBool(buf, v.Bool())
 	case slog.KindDuration:
+		if b, ok := h.opts.Numbers.appendDuration(buf, v.Duration(), true); ok {
+			return b
+		}
 		return append(buf, v.Duration().String()...)
 	case slog.KindTime:
 		return append(buf, v.Time().Format(time.RFC3339)...)
@@ -207,8 +567,12 @@ func (h *Handler) appendValue(buf []byte, v slog.Value) []byte {
 			return buf
 		}
 		buf = append(buf, '{')
-		for i, a := range attrs {
-			if i > 0 {
+		n := len(buf)
+		for _, a := range attrs {
+			if h.omitNil(a.Value) {
+				continue
+			}
+			if len(buf) > n {
 				buf = append(buf, ' ')
 			}
 			buf = append(buf, a.Key...)
@@ -218,6 +582,12 @@ func (h *Handler) appendValue(buf []byte, v slog.Value) []byte {
 		buf = append(buf, '}')
 		return buf
 	default:
+		if isNilValue(v) {
+			return append(buf, nilText...)
+		}
+		if c, ok := h.composite(v, 0); ok {
+			return h.appendValue(buf, c)
+		}
 		return append(buf, v.String()...)
 	}
 }
@@ -226,7 +596,7 @@ func (h *Handler) appendValue(buf []byte, v slog.Value) []byte {
 func appendInt(buf []byte, n int, width int) []byte {
 	start := len(buf)
 	buf = strconv.AppendInt(buf, int64(n), 10)
-	
+
 	// 如果需要，在前面填充 0
 	if actual := len(buf) - start; actual < width {
 		padding := width - actual
@@ -238,7 +608,7 @@ func appendInt(buf []byte, n int, width int) []byte {
 			buf[start+i] = '0'
 		}
 	}
-	
+
 	return buf
 }
 
@@ -247,16 +617,20 @@ func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	if len(attrs) == 0 {
 		return h
 	}
-	
-	newHandler := &Handler{
-		opts:   h.opts,
-		out:    h.out,
-		pool:   h.pool,
-		groups: h.groups,
-		attrs:  make([]slog.Attr, len(h.attrs)+len(attrs)),
-	}
+
+	newHandler := h.clone()
+	newHandler.attrs = make([]boundAttr, len(h.attrs), len(h.attrs)+len(attrs))
 	copy(newHandler.attrs, h.attrs)
-	copy(newHandler.attrs[len(h.attrs):], attrs)
+	for _, a := range attrs {
+		if h.rewriter != nil {
+			var ok bool
+			if a, ok = h.rewriter.attr(h.groups, a); !ok {
+				continue
+			}
+		}
+		// 脱敏之后展开，RevealSecrets 需要看到未展开的 Secret
+		newHandler.attrs = append(newHandler.attrs, boundAttr{groups: h.groups, Attr: resolveAttr(a)})
+	}
 	return newHandler
 }
 
@@ -265,16 +639,42 @@ func (h *Handler) WithGroup(name string) slog.Handler {
 	if name == "" {
 		return h
 	}
-	
-	newHandler := &Handler{
+
+	newHandler := h.clone()
+	newHandler.groups = make([]string, len(h.groups)+1)
+	copy(newHandler.groups, h.groups)
+	newHandler.groups[len(h.groups)] = name
+	return newHandler
+}
+
+// Flush 实现 Flusher，输出实现了 Flusher（例如 BufferedWriter）时刷新输出
+func (h *Handler) Flush() error {
+	f, ok := h.out.(Flusher)
+	if !ok {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return f.Flush()
+}
+
+// clone 复制 Handler 的配置，用于 WithAttrs 和 WithGroup
+func (h *Handler) clone() *Handler {
+	return &Handler{
 		opts:   h.opts,
+		mu:     h.mu,
 		out:    h.out,
 		pool:   h.pool,
-		groups: make([]string, len(h.groups)+1),
+		groups: h.groups,
 		attrs:  h.attrs,
+		color:  h.color,
+		tty:    h.tty,
+
+		sourcePaths: h.sourcePaths,
+		levelNames:  h.levelNames,
+		rewriter:    h.rewriter,
+		timePlan:    h.timePlan,
+		level:       h.level,
+		stats:       h.stats,
 	}
-	copy(newHandler.groups, h.groups)
-	newHandler.groups[len(h.groups)] = name
-	return newHandler
 }
-
@@ -5,6 +5,8 @@ import (
 	"io"
 	"log/slog"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -60,6 +62,18 @@ func TestHandler_WithAttrs(t *testing.T) {
 	}
 }
 
+func TestHandler_AttrSpacing(t *testing.T) {
+	var buf bytes.Buffer
+	handler := New(&buf, nil)
+	handler.opts.TimeFormat = ""
+	slog.New(handler).With("request_id", "12345").Info("test message", "key", "value")
+
+	want := "INFO request_id=12345 msg=test message key=value\n"
+	if buf.String() != want {
+		t.Errorf("属性之间应该以单个空格分隔: %q", buf.String())
+	}
+}
+
 func TestHandler_WithGroup(t *testing.T) {
 	var buf bytes.Buffer
 	handler := New(&buf, nil)
@@ -76,6 +90,94 @@ func TestHandler_WithGroup(t *testing.T) {
 	}
 }
 
+func TestHandler_WithAttrsBeforeGroup(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, nil).With("id", 7).WithGroup("req").With("method", "GET")
+
+	logger.Info("test", "path", "/")
+
+	output := buf.String()
+	for _, want := range []string{" id=7 ", " req.method=GET ", " req.path=/"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("输出应该包含 %q: %s", want, output)
+		}
+	}
+}
+
+// countValuer 记录 LogValue 的调用次数
+type countValuer struct{ n *int }
+
+func (v countValuer) LogValue() slog.Value {
+	*v.n++
+	return slog.IntValue(*v.n)
+}
+
+func TestHandler_WithAttrsPrefix(t *testing.T) {
+	var buf bytes.Buffer
+	format := NewFormatVar(FormatText)
+	n := 0
+	logger := NewLogger(&buf, &Options{TimeFormat: "-", FormatVar: format}).With("lazy", countValuer{&n}, "id", 7)
+
+	logger.Info("a")
+	logger.Info("b")
+	want := "- INFO lazy=1 id=7 msg=a\n- INFO lazy=1 id=7 msg=b\n"
+	if buf.String() != want {
+		t.Errorf("预设属性应该只编码一次:\n got %q\nwant %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	format.Set(FormatJSON)
+	logger.Info("c")
+	if !strings.Contains(buf.String(), `"msg":"c","lazy":1,"id":7}`) || n != 1 {
+		t.Errorf("切换格式后应该按新格式重新编码，LogValuer 只展开一次: %d %s", n, buf.String())
+	}
+}
+
+// serialWriter 检查 Write 是否被并发调用
+type serialWriter struct {
+	active     atomic.Int32
+	concurrent atomic.Bool
+	buf        bytes.Buffer
+}
+
+func (w *serialWriter) Write(p []byte) (int, error) {
+	if w.active.Add(1) > 1 {
+		w.concurrent.Store(true)
+	}
+	defer w.active.Add(-1)
+	return w.buf.Write(p)
+}
+
+func TestHandler_ConcurrentWrite(t *testing.T) {
+	w := &serialWriter{}
+	logger := NewLogger(w, &Options{TimeFormat: "-"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(l *slog.Logger) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				l.Info("test", "j", j)
+			}
+		}(logger.With("worker", i).WithGroup("g"))
+	}
+	wg.Wait()
+
+	if w.concurrent.Load() {
+		t.Error("派生的 Handler 不应该并发写入同一个输出")
+	}
+	lines := strings.Split(strings.TrimSuffix(w.buf.String(), "\n"), "\n")
+	if len(lines) != 1600 {
+		t.Fatalf("应该输出 1600 行，实际 %d 行", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "- INFO worker=") || !strings.Contains(line, " msg=test g.j=") {
+			t.Fatalf("输出行不完整: %q", line)
+		}
+	}
+}
+
 func TestHandler_MultipleTypes(t *testing.T) {
 	var buf bytes.Buffer
 	logger := NewLogger(&buf, nil)
@@ -142,11 +244,11 @@ func TestHandler_CustomTimeFormat(t *testing.T) {
 
 func TestHandler_NoTime(t *testing.T) {
 	var buf bytes.Buffer
-	
+
 	// 使用特殊标记来禁用时间
 	handler := New(&buf, nil)
 	handler.opts.TimeFormat = "" // 手动设置为空字符串
-	
+
 	logger := slog.New(handler)
 	logger.Info("test")
 
@@ -157,6 +259,19 @@ func TestHandler_NoTime(t *testing.T) {
 	}
 }
 
+func TestHandler_TeeRaw(t *testing.T) {
+	var out, tee bytes.Buffer
+	logger := NewLogger(&out, &Options{TeeRaw: &tee, Format: FormatJSON})
+
+	logger.Info("first", "k", 1)
+	logger.Debug("disabled")
+	logger.Warn("second")
+
+	if tee.String() != out.String() || strings.Count(tee.String(), "\n") != 2 {
+		t.Errorf("镜像输出应该与主输出完全一致:\n%s\n%s", out.String(), tee.String())
+	}
+}
+
 // 基准测试
 func BenchmarkHandler(b *testing.B) {
 	logger := NewLogger(io.Discard, nil)
@@ -197,6 +312,38 @@ func BenchmarkHandler_ManyAttrs(b *testing.B) {
 	}
 }
 
+func BenchmarkHandler_WithAttrs(b *testing.B) {
+	logger := NewLogger(io.Discard, nil).With("request_id", "12345", "user", slog.GroupValue(slog.Int("id", 42), slog.String("name", "alice")))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		logger.Info("test message", "key1", "value1")
+	}
+}
+
+func BenchmarkHandler_Parallel(b *testing.B) {
+	logger := NewLogger(io.Discard, nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info("test message", "key1", "value1", "key2", 42, "key3", true)
+		}
+	})
+}
+
+func BenchmarkHandler_Disabled(b *testing.B) {
+	logger := NewLogger(io.Discard, &Options{Level: NewLevelVar(slog.LevelInfo)})
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		logger.Debug("test message", "key1", "value1")
+	}
+}
+
 // 对比标准库性能
 func BenchmarkStdTextHandler(b *testing.B) {
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
@@ -207,4 +354,3 @@ func BenchmarkStdTextHandler(b *testing.B) {
 		logger.Info("test message", "key1", "value1", "key2", 42, "key3", true)
 	}
 }
-
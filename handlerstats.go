@@ -0,0 +1,97 @@
+package slogplus
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// HandlerStats 是 Handler 自创建以来的运行统计，用于监控日志系统自身的健康状况
+// 由 WithAttrs、WithGroup 派生的 Handler 共享同一份统计
+type HandlerStats struct {
+	Records     LevelCounts `json:"records"`      // 成功写入的记录数
+	Bytes       int64       `json:"bytes"`        // 成功写入的字节数
+	Dropped     int64       `json:"dropped"`      // 被 Filter、配额或异步队列丢弃的记录数
+	WriteErrors int64       `json:"write_errors"` // 写入失败的记录数
+	QueueDepth  int         `json:"queue_depth"`  // 异步队列的当前深度，只有 AsyncHandler 提供
+}
+
+// LevelCounts 按级别统计记录数，自定义级别计入不高于它的最近的标准级别，
+// 低于 Info 的都计入 Debug
+type LevelCounts struct {
+	Debug int64 `json:"debug"`
+	Info  int64 `json:"info"`
+	Warn  int64 `json:"warn"`
+	Error int64 `json:"error"`
+}
+
+// Total 返回所有级别的记录数之和
+func (c LevelCounts) Total() int64 {
+	return c.Debug + c.Info + c.Warn + c.Error
+}
+
+// StatsProvider 是可以报告运行统计的 Handler，*Handler 和 *AsyncHandler 都实现了该接口
+type StatsProvider interface {
+	Stats() HandlerStats
+}
+
+// handlerStats 是 Handler 的计数器，所有字段都是原子的
+type handlerStats struct {
+	records     [4]atomic.Int64 // 按 levelBucket 分桶
+	bytes       atomic.Int64
+	dropped     atomic.Int64
+	writeErrors atomic.Int64
+}
+
+// levelBucket 返回级别所在的统计分桶
+func levelBucket(level slog.Level) int {
+	switch {
+	case level < slog.LevelInfo:
+		return 0
+	case level < slog.LevelWarn:
+		return 1
+	case level < slog.LevelError:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// written 记录一次写入的结果，levels 为写入的各条记录的级别
+func (s *handlerStats) written(n int, err error, levels ...slog.Level) {
+	if err != nil {
+		s.writeErrors.Add(int64(len(levels)))
+		return
+	}
+	for _, level := range levels {
+		s.records[levelBucket(level)].Add(1)
+	}
+	s.bytes.Add(int64(n))
+}
+
+// Stats 返回 Handler 的运行统计
+func (h *Handler) Stats() HandlerStats {
+	s := h.stats
+	return HandlerStats{
+		Records: LevelCounts{
+			Debug: s.records[0].Load(),
+			Info:  s.records[1].Load(),
+			Warn:  s.records[2].Load(),
+			Error: s.records[3].Load(),
+		},
+		Bytes:       s.bytes.Load(),
+		Dropped:     s.dropped.Load(),
+		WriteErrors: s.writeErrors.Load(),
+	}
+}
+
+// Stats 返回被包装的 Handler 的运行统计（如果它实现了 StatsProvider），
+// 加上异步队列丢弃的记录数和当前深度
+func (h *AsyncHandler) Stats() HandlerStats {
+	var s HandlerStats
+	if p, ok := h.next.(StatsProvider); ok {
+		s = p.Stats()
+	}
+	s.Dropped += h.a.dropped.Load()
+	s.QueueDepth = len(h.a.queue)
+	return s
+}
@@ -0,0 +1,77 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// failingWriter 的每次写入都失败
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestHandler_Stats(t *testing.T) {
+	var buf bytes.Buffer
+	h := New(&buf, &Options{
+		Level:  slog.LevelDebug,
+		Filter: MustParseFilter(`attrs["skip"] != true`),
+	})
+	logger := slog.New(h).With("app", "demo")
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Log(context.Background(), slog.LevelInfo+2, "notice")
+	logger.Warn("warn")
+	logger.Error("error")
+	logger.Error("error")
+	logger.Info("skipped", "skip", true)
+
+	s := h.Stats()
+	want := LevelCounts{Debug: 1, Info: 2, Warn: 1, Error: 2}
+	if s.Records != want || s.Records.Total() != 6 {
+		t.Errorf("Records = %+v, 期望 %+v", s.Records, want)
+	}
+	if s.Bytes != int64(buf.Len()) {
+		t.Errorf("Bytes = %d, 期望 %d", s.Bytes, buf.Len())
+	}
+	if s.Dropped != 1 || s.WriteErrors != 0 {
+		t.Errorf("Dropped = %d, WriteErrors = %d", s.Dropped, s.WriteErrors)
+	}
+
+	fh := New(failingWriter{}, nil)
+	slog.New(fh).Info("lost")
+	if s := fh.Stats(); s.WriteErrors != 1 || s.Records.Total() != 0 || s.Bytes != 0 {
+		t.Errorf("写入失败应该计入 WriteErrors: %+v", s)
+	}
+}
+
+func TestAsyncHandler_Stats(t *testing.T) {
+	var buf bytes.Buffer
+	ah := Async(New(&buf, nil), &AsyncOptions{QueueSize: 16, MaxBatch: 8})
+	for i := 0; i < 10; i++ {
+		slog.New(ah).Info("test")
+	}
+	ah.Flush()
+	if s := ah.Stats(); s.Records.Info != 10 || s.Bytes != int64(buf.Len()) {
+		t.Errorf("应该包含被包装的 Handler 的统计: %+v", s)
+	}
+	ah.Close()
+
+	gate := &gateHandler{}
+	gated := Async(gate, &AsyncOptions{QueueSize: 2})
+	defer gated.Close()
+	gate.mu.Lock()
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+	for i := 0; i < 5; i++ {
+		gated.Handle(context.Background(), r)
+	}
+	s := gated.Stats()
+	gate.mu.Unlock()
+	// 后台 goroutine 可能已经取出一条记录阻塞在 gate 上
+	if n := s.Dropped + int64(s.QueueDepth); s.QueueDepth == 0 || s.Dropped == 0 || n != 4 && n != 5 {
+		t.Errorf("QueueDepth = %d, Dropped = %d", s.QueueDepth, s.Dropped)
+	}
+}
@@ -0,0 +1,33 @@
+package slogplus
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HMAC 将指定属性的值替换为带密钥的哈希（HMAC-SHA256，十六进制并截断），
+// 相同的值总是得到相同的结果，可以跨记录关联同一个用户而不保存原始标识
+// 密钥需要保密并长期不变，更换密钥后无法再与之前的记录关联
+type HMAC struct {
+	// Key HMAC 密钥，建议至少 32 字节
+	Key []byte
+
+	// Keys 需要哈希的属性名，规则与 Options.RedactKeys 相同，例如 "user_id"、"user.*"
+	Keys []string
+
+	// Len 保留的十六进制字符数，默认为 HashLen，最多 64
+	Len int
+}
+
+// sum 返回 s 的截断哈希
+func (m *HMAC) sum(s string) string {
+	mac := hmac.New(sha256.New, m.Key)
+	mac.Write([]byte(s))
+	out := hex.EncodeToString(mac.Sum(nil))
+	n := m.Len
+	if n <= 0 {
+		n = HashLen
+	}
+	return out[:min(n, len(out))]
+}
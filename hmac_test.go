@@ -0,0 +1,30 @@
+package slogplus
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestHMAC(t *testing.T) {
+	m := &HMAC{Key: []byte("k"), Keys: []string{"user_id", "user.*"}}
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{TimeFormat: "-", HMAC: m})
+	logger.Info("a", "user_id", 42, slog.Group("user", "email", "a@b.c"), "order", 7)
+	logger.Info("b", "user_id", "42")
+
+	sum := m.sum("42")
+	if len(sum) != HashLen || sum == m.sum("43") || sum == (&HMAC{Key: []byte("k2")}).sum("42") {
+		t.Errorf("哈希错误: %q", sum)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	want := "- INFO msg=a user_id=" + sum + " user={email=" + m.sum("a@b.c") + "} order=7"
+	if lines[0] != want {
+		t.Errorf("输出错误:\n got %q\nwant %q", lines[0], want)
+	}
+	if lines[1] != "- INFO msg=b user_id="+sum {
+		t.Errorf("相同的值应该得到相同的哈希: %q", lines[1])
+	}
+}
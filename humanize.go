@@ -0,0 +1,106 @@
+package slogplus
+
+import (
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Humanize 是 FormatConsole 的易读化配置，只影响控制台格式，JSON、logfmt 等结构化输出保持原始数值
+// 设置后整数和浮点数加千位分隔符（1,234,567），时长按量级保留有效位（1m32s、12.35ms），
+// 名称匹配 ByteKeys 的整数属性输出为字节大小（1.5MiB）
+type Humanize struct {
+	// Separator 千位分隔符，默认为 ","
+	Separator string
+
+	// ByteKeys 按字节大小输出的属性名，属性名等于其中之一或以 "_"+名称 结尾时匹配（不区分大小写），
+	// 默认为 bytes、size，例如 bytes、resp_bytes、body_size
+	ByteKeys []string
+}
+
+// defaultByteKeys 默认按字节大小输出的属性名
+var defaultByteKeys = []string{"bytes", "size"}
+
+// appendValue 追加易读化的值，不需要处理时返回 false
+func (hz *Humanize) appendValue(buf []byte, a slog.Attr) ([]byte, bool) {
+	v := a.Value
+	switch v.Kind() {
+	case slog.KindInt64:
+		if hz.byteKey(a.Key) {
+			return append(buf, formatBytes(v.Int64())...), true
+		}
+		return hz.appendDigits(buf, strconv.FormatInt(v.Int64(), 10)), true
+	case slog.KindUint64:
+		if hz.byteKey(a.Key) && v.Uint64() <= 1<<63-1 {
+			return append(buf, formatBytes(int64(v.Uint64()))...), true
+		}
+		return hz.appendDigits(buf, strconv.FormatUint(v.Uint64(), 10)), true
+	case slog.KindFloat64:
+		f := v.Float64()
+		if math.IsNaN(f) || math.Abs(f) >= 1e21 {
+			// 'f' 格式无法简洁表示
+			return buf, false
+		}
+		return hz.appendDigits(buf, strconv.FormatFloat(f, 'f', -1, 64)), true
+	case slog.KindDuration:
+		return append(buf, humanizeDuration(v.Duration()).String()...), true
+	}
+	return buf, false
+}
+
+// byteKey 判断属性是否按字节大小输出
+func (hz *Humanize) byteKey(key string) bool {
+	keys := hz.ByteKeys
+	if keys == nil {
+		keys = defaultByteKeys
+	}
+	key = strings.ToLower(key)
+	for _, k := range keys {
+		k = strings.ToLower(k)
+		if key == k || strings.HasSuffix(key, "_"+k) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendDigits 为十进制数的整数部分添加千位分隔符
+func (hz *Humanize) appendDigits(buf []byte, s string) []byte {
+	sep := hz.Separator
+	if sep == "" {
+		sep = ","
+	}
+	if s[0] == '-' {
+		buf = append(buf, '-')
+		s = s[1:]
+	}
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i:]
+	}
+	for i := 0; i < len(intPart); i++ {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			buf = append(buf, sep...)
+		}
+		buf = append(buf, intPart[i])
+	}
+	return append(buf, frac...)
+}
+
+// humanizeDuration 按量级舍入时长，保留 3~4 位有效数字，例如 1m32.123456s -> 1m32s
+func humanizeDuration(d time.Duration) time.Duration {
+	abs := d.Abs()
+	switch {
+	case abs >= time.Minute:
+		return d.Round(time.Second)
+	case abs >= time.Second:
+		return d.Round(10 * time.Millisecond)
+	case abs >= time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	case abs >= time.Microsecond:
+		return d.Round(10 * time.Nanosecond)
+	}
+	return d
+}
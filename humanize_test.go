@@ -0,0 +1,44 @@
+package slogplus
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestHumanize(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, &Options{TimeFormat: "-", Format: FormatConsole, Humanize: &Humanize{}}))
+	logger.Info("done",
+		"rows", 1234567,
+		"neg", -1000,
+		"small", 999,
+		"ratio", 12345.678,
+		"elapsed", 92*time.Second+123456*time.Microsecond,
+		"query", 12345678*time.Nanosecond,
+		"resp_bytes", 1536,
+		"size", uint64(3<<30),
+	)
+
+	want := "- INFO  msg=done rows=1,234,567 neg=-1,000 small=999 ratio=12,345.678 elapsed=1m32s query=12.35ms resp_bytes=1.5KiB size=3.0GiB\n"
+	if got := buf.String(); got != want {
+		t.Errorf("易读化输出错误:\n%s", got)
+	}
+}
+
+func TestHumanize_StructuredUnchanged(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, &Options{TimeFormat: "-", Humanize: &Humanize{Separator: "_", ByteKeys: []string{"len"}}}))
+	logger.Info("done", "rows", 1234567, "body_len", 2048)
+	if got := buf.String(); got != "- INFO msg=done rows=1234567 body_len=2048\n" {
+		t.Errorf("非控制台格式不应该易读化: %q", got)
+	}
+
+	buf.Reset()
+	logger = slog.New(New(&buf, &Options{TimeFormat: "-", Format: FormatConsole, Humanize: &Humanize{Separator: "_", ByteKeys: []string{"len"}}}))
+	logger.Info("done", "rows", 1234567, "body_len", 2048)
+	if got := buf.String(); got != "- INFO  msg=done rows=1_234_567 body_len=2.0KiB\n" {
+		t.Errorf("自定义配置错误: %q", got)
+	}
+}
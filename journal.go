@@ -0,0 +1,118 @@
+package slogplus
+
+import (
+	"encoding/binary"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// appendJournal 按 journald 原生协议追加一条记录
+// 每个字段一行 KEY=value，值包含换行时使用 KEY\n<长度><值>\n 的二进制格式
+func (h *Handler) appendJournal(buf []byte, r slog.Record) []byte {
+	buf = append(buf, "PRIORITY="...)
+	buf = strconv.AppendInt(buf, int64(syslogSeverity(r.Level)), 10)
+	buf = append(buf, '\n')
+	buf = appendJournalField(buf, "MESSAGE", r.Message)
+
+	ident := filepath.Base(os.Args[0])
+	if h.opts.Syslog != nil && h.opts.Syslog.AppName != "" {
+		ident = h.opts.Syslog.AppName
+	}
+	buf = appendJournalField(buf, "SYSLOG_IDENTIFIER", ident)
+
+	if r.PC != 0 {
+		f := h.frame(r.PC)
+		if f.File != "" {
+			buf = appendJournalField(buf, "CODE_FILE", f.File)
+			buf = append(buf, "CODE_LINE="...)
+			buf = strconv.AppendInt(buf, int64(f.Line), 10)
+			buf = append(buf, '\n')
+			if f.Function != "" {
+				buf = appendJournalField(buf, "CODE_FUNC", f.Function)
+			}
+		}
+	}
+
+	buf = h.appendBound(buf, FormatJournal)
+	r.Attrs(func(a slog.Attr) bool {
+		buf = h.appendJournalAttr(buf, h.groups, a)
+		return true
+	})
+	return buf
+}
+
+// appendJournalAttr 追加属性字段，字段名转换为大写，分组使用 '_' 连接
+func (h *Handler) appendJournalAttr(buf []byte, groups []string, a slog.Attr) []byte {
+	if h.opts.ReplaceAttr != nil && a.Value.Kind() != slog.KindGroup {
+		a = h.opts.ReplaceAttr(groups, a)
+	}
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) || h.omitNil(a.Value) {
+		return buf
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], a.Key)
+		}
+		for _, ga := range a.Value.Group() {
+			buf = h.appendJournalAttr(buf, groups, ga)
+		}
+		return buf
+	}
+
+	key := journalFieldName(append(groups[:len(groups):len(groups)], a.Key))
+	var value string
+	if a.Value.Kind() == slog.KindString {
+		value = a.Value.String()
+	} else {
+		value = string(h.appendValue(nil, a.Value))
+	}
+	return appendJournalField(buf, key, value)
+}
+
+// journalFieldName 生成 journald 字段名
+// 只允许大写字母、数字和 '_'，不能以 '_' 或数字开头，最长 64 个字符
+func journalFieldName(parts []string) string {
+	var b strings.Builder
+	for i, p := range parts {
+		if i > 0 {
+			b.WriteByte('_')
+		}
+		for j := 0; j < len(p); j++ {
+			c := p[j]
+			switch {
+			case c >= 'a' && c <= 'z':
+				b.WriteByte(c - 'a' + 'A')
+			case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+				b.WriteByte(c)
+			default:
+				b.WriteByte('_')
+			}
+		}
+	}
+	name := strings.TrimLeft(b.String(), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "F_" + name
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// appendJournalField 追加一个字段
+func appendJournalField(buf []byte, key, value string) []byte {
+	buf = append(buf, key...)
+	if strings.IndexByte(value, '\n') < 0 {
+		buf = append(buf, '=')
+		buf = append(buf, value...)
+		return append(buf, '\n')
+	}
+	buf = append(buf, '\n')
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(len(value)))
+	buf = append(buf, value...)
+	return append(buf, '\n')
+}
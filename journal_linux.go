@@ -0,0 +1,28 @@
+//go:build linux && !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"net"
+)
+
+// journalSocket 是 journald 原生协议的 socket 路径
+const journalSocket = "/run/systemd/journal/socket"
+
+// NewJournalHandler 创建直接写入 systemd journald 的 Handler
+// 记录以 PRIORITY、MESSAGE、CODE_FILE/CODE_LINE/CODE_FUNC 以及大写属性字段写入 journal，
+// opts.Syslog.AppName 用作 SYSLOG_IDENTIFIER。单条记录不能超过 socket 的最大报文长度
+func NewJournalHandler(opts *Options) (*Handler, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journalSocket, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	o.Format = FormatJournal
+	o.FormatVar = nil
+	return New(conn, &o), nil
+}
@@ -0,0 +1,51 @@
+package slogplus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestHandler_Journal(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{Format: FormatJournal, Syslog: &SyslogOptions{AppName: "api"}})
+
+	logger.With("user-id", 42).WithGroup("req").Error("failed", "_path", "/x", "body", "a\nb")
+
+	out := buf.String()
+	for _, want := range []string{
+		"PRIORITY=3\n",
+		"MESSAGE=failed\n",
+		"SYSLOG_IDENTIFIER=api\n",
+		"CODE_FILE=",
+		"CODE_LINE=",
+		"USER_ID=42\n",
+		"REQ__PATH=/x\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("输出应该包含 %q: %q", want, out)
+		}
+	}
+
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], 3)
+	if !strings.Contains(out, "REQ_BODY\n"+string(size[:])+"a\nb\n") {
+		t.Errorf("包含换行的值应该使用二进制格式: %q", out)
+	}
+}
+
+func TestJournalFieldName(t *testing.T) {
+	tests := map[string][]string{
+		"USER_ID":   {"user_id"},
+		"HTTP_PATH": {"http", "path"},
+		"PRIVATE":   {"__private"},
+		"F_1ST":     {"1st"},
+		"A_B":       {"a.b"},
+	}
+	for want, parts := range tests {
+		if got := journalFieldName(parts); got != want {
+			t.Errorf("journalFieldName(%v) = %s, 期望 %s", parts, got, want)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package slogplus
+
+import (
+	"encoding/json"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// appendJSON 按 JSON 格式追加一条记录
+// {"time":"...","level":"INFO","source":"file.go:42","msg":"...","key":"value"}
+func (h *Handler) appendJSON(buf []byte, r slog.Record) []byte {
+	buf = append(buf, '{')
+	if h.opts.TimeFormat != "" && !r.Time.IsZero() {
+		buf = append(buf, `"time":"`...)
+		buf = r.Time.AppendFormat(buf, time.RFC3339Nano)
+		buf = append(buf, '"', ',')
+	}
+	buf = append(buf, `"level":`...)
+	buf = appendJSONString(buf, h.levelName(r.Level))
+
+	if h.opts.AddSource && r.PC != 0 {
+		f := h.frame(r.PC)
+		if f.File != "" {
+			buf = append(buf, `,"source":"`...)
+			buf = appendJSONEscaped(buf, f.File)
+			buf = append(buf, ':')
+			buf = strconv.AppendInt(buf, int64(f.Line), 10)
+			buf = append(buf, '"')
+		}
+	}
+
+	buf = append(buf, `,"msg":`...)
+	buf = appendJSONString(buf, r.Message)
+
+	buf = h.appendBound(buf, FormatJSON)
+	r.Attrs(func(a slog.Attr) bool {
+		buf = h.appendJSONAttr(buf, h.groups, a, appendJSONDottedKey, false)
+		return true
+	})
+
+	return append(buf, '}', '\n')
+}
+
+// jsonKeyFunc 追加 JSON 字段名（不含引号），groups 为属性所在的分组
+type jsonKeyFunc func(buf []byte, groups []string, key string) []byte
+
+// appendJSONAttr 追加一个 JSON 字段，格式为 ,"key":value
+// flatten 为 true 时分组属性展开为多个字段，否则输出为嵌套对象
+func (h *Handler) appendJSONAttr(buf []byte, groups []string, a slog.Attr, key jsonKeyFunc, flatten bool) []byte {
+	if h.opts.ReplaceAttr != nil && a.Value.Kind() != slog.KindGroup {
+		a = h.opts.ReplaceAttr(groups, a)
+	}
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) || h.omitNil(a.Value) {
+		return buf
+	}
+
+	if flatten && a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], a.Key)
+		}
+		for _, ga := range a.Value.Group() {
+			buf = h.appendJSONAttr(buf, groups, ga, key, flatten)
+		}
+		return buf
+	}
+
+	buf = append(buf, ',', '"')
+	buf = key(buf, groups, a.Key)
+	buf = append(buf, '"', ':')
+	return h.appendJSONValue(buf, a.Value)
+}
+
+// appendJSONDottedKey 以 group.key 形式追加字段名
+func appendJSONDottedKey(buf []byte, groups []string, key string) []byte {
+	for _, g := range groups {
+		buf = appendJSONEscaped(buf, g)
+		buf = append(buf, '.')
+	}
+	return appendJSONEscaped(buf, key)
+}
+
+// appendJSONValue 将值以 JSON 形式追加到 buffer
+func (h *Handler) appendJSONValue(buf []byte, v slog.Value) []byte {
+	switch v.Kind() {
+	case slog.KindString:
+		return appendJSONString(buf, v.String())
+	case slog.KindInt64:
+		if h.opts.Numbers.largeInt(v.Int64()) {
+			buf = append(buf, '"')
+			buf = strconv.AppendInt(buf, v.Int64(), 10)
+			return append(buf, '"')
+		}
+		return strconv.AppendInt(buf, v.Int64(), 10)
+	case slog.KindUint64:
+		if h.opts.Numbers.largeUint(v.Uint64()) {
+			buf = append(buf, '"')
+			buf = strconv.AppendUint(buf, v.Uint64(), 10)
+			return append(buf, '"')
+		}
+		return strconv.AppendUint(buf, v.Uint64(), 10)
+	case slog.KindFloat64:
+		f := v.Float64()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return appendJSONString(buf, strconv.FormatFloat(f, 'g', -1, 64))
+		}
+		return h.opts.Numbers.appendFloat(buf, f)
+	case slog.KindBool:
+		return strconv.AppendBool(buf, v.Bool())
+	case slog.KindDuration:
+		if b, ok := h.opts.Numbers.appendDuration(buf, v.Duration(), false); ok {
+			return b
+		}
+		// 与标准库 JSONHandler 一致，输出纳秒数
+		return strconv.AppendInt(buf, int64(v.Duration()), 10)
+	case slog.KindTime:
+		buf = append(buf, '"')
+		buf = v.Time().AppendFormat(buf, time.RFC3339Nano)
+		return append(buf, '"')
+	case slog.KindGroup:
+		buf = append(buf, '{')
+		n := len(buf)
+		for _, a := range v.Group() {
+			buf = h.appendJSONAttr(buf, nil, a, appendJSONDottedKey, false)
+		}
+		if len(buf) > n {
+			// 去掉第一个字段前的逗号
+			copy(buf[n:], buf[n+1:])
+			buf = buf[:len(buf)-1]
+		}
+		return append(buf, '}')
+	case slog.KindLogValuer:
+		return h.appendJSONValue(buf, v.Resolve())
+	default:
+		if isNilValue(v) {
+			return append(buf, "null"...)
+		}
+		x := v.Any()
+		if err, ok := x.(error); ok {
+			return appendJSONString(buf, err.Error())
+		}
+		b, err := json.Marshal(x)
+		if err != nil {
+			return appendJSONString(buf, v.String())
+		}
+		return append(buf, b...)
+	}
+}
+
+// appendJSONString 追加带引号的 JSON 字符串
+func appendJSONString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	buf = appendJSONEscaped(buf, s)
+	return append(buf, '"')
+}
+
+// appendJSONEscaped 追加转义后的 JSON 字符串内容（不含引号）
+func appendJSONEscaped(buf []byte, s string) []byte {
+	start := 0
+	for i := 0; i < len(s); {
+		// 按字节类别表跳过不需要转义的连续字节，整段复制
+		if i = safeASCIIPrefix(s, i, &jsonSafe); i == len(s) {
+			break
+		}
+		c := s[i]
+		if c < utf8.RuneSelf {
+			buf = append(buf, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				buf = append(buf, '\\', c)
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			default:
+				buf = append(buf, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, `\ufffd`...)
+			i++
+			start = i
+			continue
+		}
+		// U+2028 和 U+2029 在 JavaScript 中是换行符，需要转义
+		if r == '\u2028' || r == '\u2029' {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, '\\', 'u', '2', '0', '2', hexDigits[r&0xf])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	return append(buf, s[start:]...)
+}
@@ -0,0 +1,350 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// KafkaMessage 是发送到 Kafka 的一条消息
+type KafkaMessage struct {
+	Topic    string
+	Key      []byte // KeyAttr 对应的属性值，没有该属性时为 nil
+	Value    []byte // 按 Options 编码后的记录，设置了 Codec 时为一批记录按行拼接后压缩的数据
+	Encoding string // Value 的压缩算法名称，未压缩时为空，可以写入消息头供消费端解压
+	Time     time.Time
+}
+
+// KafkaProducer 是 Kafka 生产者的最小接口
+// slogplus 不依赖具体的客户端库，使用 sarama、franz-go、confluent-kafka-go 等时实现该接口即可，
+// 例如 franz-go:
+//
+//	func (p *franzProducer) Produce(ctx context.Context, msgs []slogplus.KafkaMessage) error {
+//		recs := make([]*kgo.Record, len(msgs))
+//		for i, m := range msgs {
+//			recs[i] = &kgo.Record{Topic: m.Topic, Key: m.Key, Value: m.Value, Timestamp: m.Time}
+//		}
+//		return p.client.ProduceSync(ctx, recs...).FirstErr()
+//	}
+type KafkaProducer interface {
+	// Produce 同步发送一批消息，返回错误表示这批消息投递失败
+	Produce(ctx context.Context, msgs []KafkaMessage) error
+}
+
+// KafkaOptions 定义 Kafka 输出的配置
+type KafkaOptions struct {
+	// Topic 目标 topic，必须设置
+	Topic string
+
+	// KeyAttr 作为消息 key 的属性名，例如 "request_id"，使同一请求的日志进入同一分区
+	KeyAttr string
+
+	// Options 记录的编码配置，默认为 &Options{Format: FormatJSON}
+	Options *Options
+
+	// BatchSize 每批最多的消息数，默认 100
+	BatchSize int
+
+	// QueueSize 等待发送的最大消息数，队列满时丢弃新消息，默认 10000
+	QueueSize int
+
+	// FlushInterval 发送间隔，默认 1 秒
+	FlushInterval time.Duration
+
+	// Timeout 单批发送的超时时间，默认 10 秒
+	Timeout time.Duration
+
+	// Retry 重试策略，为 nil 时使用默认策略
+	Retry *RetryPolicy
+
+	// Codec 设置后按批压缩，默认不压缩
+	// 每批中 key 相同的记录按行拼接后压缩为一条消息，同一 key 的记录仍然进入同一分区，消费端解压后按行拆分，
+	// FormatMsgpack 的记录直接首尾相接，消费端解压后用 MsgpackDecoder 逐条解码；
+	// 集群要求的 Kafka 协议层压缩（snappy、lz4 等）由 Kafka 客户端完成，这里用于消费端需要自行解压的场景
+	Codec Codec
+
+	// OnError 投递失败（重试耗尽）时的回调，msgs 为失败的消息
+	OnError func(err error, msgs []KafkaMessage)
+
+	// Stats 设置后报告发送队列深度和发送延迟，名称为 "kafka"
+	Stats *PipelineStats
+}
+
+// KafkaHandler 将记录编码后批量发送到 Kafka
+type KafkaHandler struct {
+	h *Handler
+	p *kafkaSink
+}
+
+// kafkaSink 是所有派生 Handler 共享的发送队列
+type kafkaSink struct {
+	producer KafkaProducer
+	opts     KafkaOptions
+
+	queue   chan KafkaMessage
+	flushCh chan chan error
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+
+	format func() Format // 当前的输出格式，决定记录之间的分隔方式
+
+	dropped    atomic.Int64
+	lastErr    error
+	unregister func()
+}
+
+// ErrKafkaQueueFull 表示 Kafka 发送队列已满，消息被丢弃
+var ErrKafkaQueueFull = errors.New("slogplus: Kafka 发送队列已满")
+
+// NewKafkaHandler 创建 Kafka 输出 Handler，使用完毕后需要调用 Close
+func NewKafkaHandler(producer KafkaProducer, opts *KafkaOptions) (*KafkaHandler, error) {
+	s := &kafkaSink{producer: producer}
+	if opts != nil {
+		s.opts = *opts
+	}
+	o := &s.opts
+	if o.Topic == "" {
+		return nil, errors.New("slogplus: 必须设置 Kafka Topic")
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 100
+	}
+	if o.QueueSize <= 0 {
+		o.QueueSize = 10000
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = time.Second
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 10 * time.Second
+	}
+	if o.Retry == nil {
+		o.Retry = &RetryPolicy{}
+	}
+
+	hopts := Options{Format: FormatJSON}
+	if o.Options != nil {
+		hopts = *o.Options
+	}
+
+	h := New(nil, &hopts)
+	s.format = h.format
+	s.queue = make(chan KafkaMessage, o.QueueSize)
+	s.flushCh = make(chan chan error)
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	s.unregister = RegisterFlusher(s)
+	go s.loop()
+
+	return &KafkaHandler{h: h, p: s}, nil
+}
+
+// Enabled 实现 slog.Handler
+func (h *KafkaHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.h.Enabled(ctx, level)
+}
+
+// Handle 编码记录并放入发送队列，不会阻塞
+func (h *KafkaHandler) Handle(ctx context.Context, r slog.Record) error {
+	r, ok := h.h.prepare(ctx, r)
+	if !ok {
+		return nil
+	}
+
+	value := h.h.appendRecord(make([]byte, 0, 256), ctx, r)
+	// msgpack 是二进制格式，末尾的 0x0a 是数据的一部分，只有按行输出的格式需要去掉换行符
+	if h.p.format() != FormatMsgpack {
+		value = bytes.TrimSuffix(value, []byte{'\n'})
+	}
+	msg := KafkaMessage{
+		Topic: h.p.opts.Topic,
+		Key:   h.key(r),
+		Value: value,
+		Time:  r.Time,
+	}
+	select {
+	case h.p.queue <- msg:
+		h.p.opts.Stats.QueueDepth("kafka", len(h.p.queue))
+		return nil
+	default:
+		h.p.dropped.Add(1)
+		return ErrKafkaQueueFull
+	}
+}
+
+// key 返回 KeyAttr 对应的属性值，记录中的属性优先于预设属性
+func (h *KafkaHandler) key(r slog.Record) []byte {
+	name := h.p.opts.KeyAttr
+	if name == "" {
+		return nil
+	}
+	var key []byte
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == name {
+			key = []byte(a.Value.Resolve().String())
+			return false
+		}
+		return true
+	})
+	if key != nil {
+		return key
+	}
+	for i := len(h.h.attrs) - 1; i >= 0; i-- {
+		if a := h.h.attrs[i].Attr; a.Key == name {
+			return []byte(a.Value.Resolve().String())
+		}
+	}
+	return nil
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *KafkaHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &KafkaHandler{h: h.h.WithAttrs(attrs).(*Handler), p: h.p}
+}
+
+// WithGroup 实现 slog.Handler
+func (h *KafkaHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &KafkaHandler{h: h.h.WithGroup(name).(*Handler), p: h.p}
+}
+
+// Flush 立即发送队列中的所有消息
+func (h *KafkaHandler) Flush() error {
+	return h.p.Flush()
+}
+
+// Dropped 返回因队列已满而丢弃的消息数
+func (h *KafkaHandler) Dropped() int64 {
+	return h.p.dropped.Load()
+}
+
+// Close 发送剩余消息并停止发送
+func (h *KafkaHandler) Close() error {
+	var err error
+	h.p.once.Do(func() {
+		h.p.unregister()
+		close(h.p.stop)
+		<-h.p.done
+		err = h.p.lastErr
+	})
+	return err
+}
+
+// Flush 实现 Flusher
+func (s *kafkaSink) Flush() error {
+	ch := make(chan error, 1)
+	select {
+	case s.flushCh <- ch:
+		return <-ch
+	case <-s.done:
+		return nil
+	}
+}
+
+func (s *kafkaSink) loop() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]KafkaMessage, 0, s.opts.BatchSize)
+	send := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		start := time.Now()
+		msgs, err := s.compressBatch(batch)
+		if err == nil {
+			err = s.produce(msgs)
+		}
+		s.opts.Stats.Latency("kafka", time.Since(start))
+		if err != nil && s.opts.OnError != nil {
+			s.opts.OnError(err, append([]KafkaMessage(nil), msgs...))
+		}
+		clear(batch)
+		batch = batch[:0]
+		return err
+	}
+	drain := func() error {
+		var errs []error
+		for {
+			select {
+			case msg := <-s.queue:
+				batch = append(batch, msg)
+				if len(batch) >= s.opts.BatchSize {
+					errs = append(errs, send())
+				}
+			default:
+				errs = append(errs, send())
+				return errors.Join(errs...)
+			}
+		}
+	}
+
+	for {
+		select {
+		case msg := <-s.queue:
+			batch = append(batch, msg)
+			if len(batch) >= s.opts.BatchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case ch := <-s.flushCh:
+			ch <- drain()
+		case <-s.stop:
+			s.lastErr = drain()
+			return
+		}
+	}
+}
+
+// compressBatch 将一批消息中 key 相同的记录按行拼接后整体压缩为一条消息，没有设置 Codec 时原样返回
+// FormatMsgpack 的记录自带长度信息，不加分隔符直接拼接；压缩失败时返回原来的消息和错误
+func (s *kafkaSink) compressBatch(batch []KafkaMessage) ([]KafkaMessage, error) {
+	if s.opts.Codec == nil || s.opts.Codec == NoCompression {
+		return batch, nil
+	}
+	sep := s.format() != FormatMsgpack
+	var out []KafkaMessage
+	var values [][]byte
+	index := make(map[string]int) // key -> out 中的下标，保持每个 key 第一次出现的顺序
+	for _, m := range batch {
+		i, ok := index[string(m.Key)]
+		if !ok {
+			i = len(out)
+			index[string(m.Key)] = i
+			out = append(out, KafkaMessage{Topic: m.Topic, Key: m.Key, Time: m.Time})
+			values = append(values, nil)
+		} else if sep {
+			values[i] = append(values[i], '\n')
+		}
+		values[i] = append(values[i], m.Value...)
+	}
+	for i := range out {
+		value, encoding, err := compress(s.opts.Codec, values[i])
+		if err != nil {
+			return batch, err
+		}
+		out[i].Value, out[i].Encoding = value, encoding
+	}
+	return out, nil
+}
+
+// produce 带超时和重试地发送一批消息
+func (s *kafkaSink) produce(batch []KafkaMessage) error {
+	return s.opts.Retry.Do(context.Background(), func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+		defer cancel()
+		return s.producer.Produce(ctx, batch)
+	})
+}
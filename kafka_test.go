@@ -0,0 +1,168 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type fakeKafkaProducer struct {
+	mu      sync.Mutex
+	batches [][]KafkaMessage
+	err     error
+}
+
+func (p *fakeKafkaProducer) Produce(_ context.Context, msgs []KafkaMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	p.batches = append(p.batches, append([]KafkaMessage(nil), msgs...))
+	return nil
+}
+
+func TestKafkaHandler(t *testing.T) {
+	p := &fakeKafkaProducer{}
+	h, err := NewKafkaHandler(p, &KafkaOptions{Topic: "logs", KeyAttr: "request_id", BatchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	logger := slog.New(h)
+	logger.With("request_id", "r-1").Info("first")
+	logger.Info("second", "request_id", "r-2")
+	logger.Info("third")
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.batches) != 2 || len(p.batches[0]) != 2 || len(p.batches[1]) != 1 {
+		t.Fatalf("应该按 BatchSize 分批发送: %v", p.batches)
+	}
+	msgs := append(p.batches[0], p.batches[1]...)
+	for i, want := range []string{"r-1", "r-2", ""} {
+		m := msgs[i]
+		if m.Topic != "logs" || string(m.Key) != want {
+			t.Errorf("#%d topic=%s key=%q, 期望 key=%q", i, m.Topic, m.Key, want)
+		}
+		var v map[string]any
+		if err := json.Unmarshal(m.Value, &v); err != nil {
+			t.Errorf("#%d 默认应该编码为 JSON: %v %s", i, err, m.Value)
+		}
+	}
+}
+
+func TestKafkaHandler_OnError(t *testing.T) {
+	p := &fakeKafkaProducer{err: errors.New("broker down")}
+	var failed []KafkaMessage
+	h, err := NewKafkaHandler(p, &KafkaOptions{
+		Topic:   "logs",
+		Retry:   &RetryPolicy{MaxAttempts: 1},
+		OnError: func(err error, msgs []KafkaMessage) { failed = append(failed, msgs...) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slog.New(h).Error("lost")
+	if err := h.Close(); err == nil {
+		t.Error("投递失败时 Close 应该返回错误")
+	}
+	if len(failed) != 1 {
+		t.Errorf("OnError 应该收到失败的消息: %v", failed)
+	}
+}
+
+func TestNewKafkaHandler_RequiresTopic(t *testing.T) {
+	if _, err := NewKafkaHandler(&fakeKafkaProducer{}, nil); err == nil {
+		t.Error("未设置 Topic 时应该返回错误")
+	}
+}
+
+func TestKafkaHandler_Codec(t *testing.T) {
+	p := &fakeKafkaProducer{}
+	h, err := NewKafkaHandler(p, &KafkaOptions{Topic: "logs", KeyAttr: "request_id", Codec: Snappy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(h)
+	logger.Info("a", "request_id", "r-1")
+	logger.Info("b", "request_id", "r-2")
+	logger.Info("c", "request_id", "r-1")
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 一批中 key 相同的记录合并后整体压缩
+	if len(p.batches) != 1 || len(p.batches[0]) != 2 {
+		t.Fatalf("应该按 key 合并为两条消息: %v", p.batches)
+	}
+	for i, want := range map[int][]string{0: {"r-1", "a", "c"}, 1: {"r-2", "b"}} {
+		m := p.batches[0][i]
+		if m.Encoding != "snappy" || string(m.Key) != want[0] {
+			t.Errorf("#%d encoding=%q key=%q", i, m.Encoding, m.Key)
+		}
+		value, err := snappyDecode(m.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(string(value), "\n")
+		if len(lines) != len(want)-1 {
+			t.Fatalf("#%d 解压后的行数错误: %q", i, value)
+		}
+		for j, line := range lines {
+			var v map[string]any
+			if err := json.Unmarshal([]byte(line), &v); err != nil || v["msg"] != want[j+1] {
+				t.Errorf("#%d 解压后每行应该是一条 JSON 记录: %v %s", i, err, line)
+			}
+		}
+	}
+}
+
+func TestKafkaHandler_Msgpack(t *testing.T) {
+	for _, codec := range []Codec{nil, Snappy} {
+		p := &fakeKafkaProducer{}
+		h, err := NewKafkaHandler(p, &KafkaOptions{Topic: "logs", Options: &Options{Format: FormatMsgpack}, Codec: codec})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 最后一个字节是 0x0a，不能被当作换行符去掉
+		logger := slog.New(h)
+		logger.Info("x", "n", 10)
+		logger.Info("y", "n", 10)
+		if err := h.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		var stream []byte
+		for _, m := range p.batches[0] {
+			value := m.Value
+			if codec != nil {
+				if value, err = snappyDecode(value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			stream = append(stream, value...)
+		}
+		d := NewMsgpackDecoder(bytes.NewReader(stream))
+		for _, want := range []string{"x", "y"} {
+			v, err := d.Decode()
+			if err != nil {
+				t.Fatalf("codec=%v: %v", codec, err)
+			}
+			if v["msg"] != want || fmt.Sprint(v["n"]) != "10" {
+				t.Errorf("codec=%v: 解码结果错误: %v", codec, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package slogplus
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// 在 slog 内置级别之外补充的级别，便于从 logrus、zap 迁移
+const (
+	// LevelFatal 记录后刷新所有输出并退出进程，参见 Fatal
+	LevelFatal = slog.LevelError + 4
+	// LevelPanic 记录后触发 panic，参见 Panic
+	LevelPanic = slog.LevelError + 8
+)
+
+// exit 用于测试中替换 os.Exit
+var exit = os.Exit
+
+// levelNames 是通过 RegisterLevel 注册的级别名称，写时复制
+var levelNames atomic.Pointer[map[slog.Level]string]
+
+func init() {
+	levelNames.Store(&map[slog.Level]string{
+		LevelFatal: "FATAL",
+		LevelPanic: "PANIC",
+	})
+}
+
+// RegisterLevel 注册一个具名级别并返回该级别，所有 Handler 都会以 name 输出它，
+// ParseLevel、包级别配置和过滤表达式也能识别该名称（不区分大小写）
+//
+//	var LevelNotice = slogplus.RegisterLevel("NOTICE", slog.LevelInfo+2)
+//	logger.Log(ctx, LevelNotice, "配置已更新") // NOTICE msg=配置已更新
+//
+// 通常在包初始化时调用，重复注册同一级别会覆盖原名称
+func RegisterLevel(name string, level slog.Level) slog.Level {
+	for {
+		old := levelNames.Load()
+		m := make(map[slog.Level]string, len(*old)+1)
+		for l, n := range *old {
+			m[l] = n
+		}
+		m[level] = name
+		if levelNames.CompareAndSwap(old, &m) {
+			return level
+		}
+	}
+}
+
+// ParseLevel 解析级别名称，除 slog 的 DEBUG、INFO、WARN、ERROR 及 INFO+2 等写法外，
+// 还接受 RegisterLevel 注册的名称，不区分大小写
+func ParseLevel(s string) (slog.Level, error) {
+	for l, n := range *levelNames.Load() {
+		if strings.EqualFold(n, s) {
+			return l, nil
+		}
+	}
+	var level slog.Level
+	err := level.UnmarshalText([]byte(s))
+	return level, err
+}
+
+// levelName 返回级别的输出名称，已注册的级别输出为注册的名称而不是 INFO+2、ERROR+4 等
+func levelName(level slog.Level) string {
+	if name, ok := (*levelNames.Load())[level]; ok {
+		return name
+	}
+	return level.String()
+}
+
+// Fatal 以 LevelFatal 记录日志，刷新所有已注册的输出后以状态码 1 退出进程
+// l 为 nil 时使用默认 Logger，args 的写法与 slog.Logger.Info 相同
+func Fatal(l *slog.Logger, msg string, args ...any) {
+	fatal(context.Background(), l, msg, args)
+}
+
+// FatalContext 与 Fatal 相同，使用 ctx 记录日志
+func FatalContext(ctx context.Context, l *slog.Logger, msg string, args ...any) {
+	fatal(ctx, l, msg, args)
+}
+
+func fatal(ctx context.Context, l *slog.Logger, msg string, args []any) {
+	flushAndExit(logAt(ctx, l, LevelFatal, msg, args, 4))
+}
+
+// flushAndExit 刷新 l 的 Handler 和所有已注册的输出后以状态码 1 退出进程
+func flushAndExit(l *slog.Logger) {
+	if f, ok := l.Handler().(Flusher); ok {
+		_ = f.Flush()
+	}
+	_ = FlushAll()
+	exit(1)
+}
+
+// Panic 以 LevelPanic 记录日志后以 msg 触发 panic，即使该级别未启用也会 panic
+// l 为 nil 时使用默认 Logger，args 的写法与 slog.Logger.Info 相同
+func Panic(l *slog.Logger, msg string, args ...any) {
+	logAt(context.Background(), l, LevelPanic, msg, args, 3)
+	panic(msg)
+}
+
+// PanicContext 与 Panic 相同，使用 ctx 记录日志
+func PanicContext(ctx context.Context, l *slog.Logger, msg string, args ...any) {
+	logAt(ctx, l, LevelPanic, msg, args, 3)
+	panic(msg)
+}
+
+// logAt 记录一条日志并返回实际使用的 Logger，源代码位置为导出函数的调用方
+// skip 跳过 Callers、logAt 以及 fatal 或 Panic 等导出函数
+func logAt(ctx context.Context, l *slog.Logger, level slog.Level, msg string, args []any, skip int) *slog.Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !l.Enabled(ctx, level) {
+		return l
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(skip, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(args...)
+	_ = l.Handler().Handle(ctx, r)
+	return l
+}
+
+// levelName 返回级别的输出名称，Options.LevelNames 优先
+func (h *Handler) levelName(level slog.Level) string {
+	if name, ok := h.levelNames[level]; ok {
+		return name
+	}
+	return levelName(level)
+}
+
+// levelReader 读取 Options.Level，使 Enabled 在常见情况下不经过接口调用
+// slog.Level 常量在 New 时取值，*slog.LevelVar 直接读取其中的原子值，
+// LevelVar.Set 之后立即生效；其它 Leveler 实现仍然每次调用 Level
+type levelReader struct {
+	fixed slog.Level
+	v     *slog.LevelVar
+	l     slog.Leveler
+}
+
+// newLevelReader 根据 Leveler 的具体类型创建 levelReader，nil 表示 Info
+func newLevelReader(l slog.Leveler) levelReader {
+	switch l := l.(type) {
+	case nil:
+		return levelReader{fixed: slog.LevelInfo}
+	case slog.Level:
+		return levelReader{fixed: l}
+	case *slog.LevelVar:
+		return levelReader{v: l}
+	default:
+		return levelReader{l: l}
+	}
+}
+
+// load 返回当前级别
+func (r *levelReader) load() slog.Level {
+	if r.v != nil {
+		return r.v.Level()
+	}
+	if r.l != nil {
+		return r.l.Level()
+	}
+	return r.fixed
+}
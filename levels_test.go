@@ -0,0 +1,118 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFatal(t *testing.T) {
+	var code int
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	var buf bytes.Buffer
+	h := &flushCountHandler{Handler: New(&buf, &Options{TimeFormat: "-", AddSource: true})}
+	Fatal(slog.New(h), "无法启动", "port", 8080)
+
+	got := buf.String()
+	if !strings.HasPrefix(got, "- FATAL source=") || !strings.Contains(got, "levels_test.go:") || !strings.HasSuffix(got, "msg=无法启动 port=8080\n") {
+		t.Errorf("输出错误: %q", got)
+	}
+	if code != 1 || h.flushes.Load() != 1 {
+		t.Errorf("应该刷新输出后以状态码 1 退出: code=%d flushes=%d", code, h.flushes.Load())
+	}
+}
+
+func TestPanic(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{Format: FormatJSON})
+
+	defer func() {
+		if r := recover(); r != "不可能的状态" {
+			t.Errorf("应该以消息触发 panic: %v", r)
+		}
+		if !strings.Contains(buf.String(), `"level":"PANIC"`) {
+			t.Errorf("输出错误: %q", buf.String())
+		}
+	}()
+	Panic(logger, "不可能的状态")
+}
+
+func TestLevelName(t *testing.T) {
+	for level, want := range map[slog.Level]string{
+		slog.LevelInfo:      "INFO",
+		slog.LevelError:     "ERROR",
+		LevelFatal:          "FATAL",
+		LevelPanic:          "PANIC",
+		slog.LevelError + 2: "ERROR+2",
+	} {
+		if got := levelName(level); got != want {
+			t.Errorf("levelName(%d) = %q, want %q", level, got, want)
+		}
+	}
+}
+
+func TestLevelNames(t *testing.T) {
+	levelNotice := RegisterLevel("NOTICE", slog.LevelInfo+2)
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{
+		TimeFormat: "-",
+		LevelNames: map[slog.Leveler]string{slog.LevelWarn: "WARNING", slog.LevelError + 1: "AUDIT"},
+	})
+	logger.Log(context.Background(), levelNotice, "配置已更新")
+	logger.With().WithGroup("g").Warn("磁盘将满")
+	logger.Log(context.Background(), slog.LevelError+1, "权限变更")
+
+	want := "- NOTICE msg=配置已更新\n- WARNING msg=磁盘将满\n- AUDIT msg=权限变更\n"
+	if got := buf.String(); got != want {
+		t.Errorf("级别名称错误:\n got %q\nwant %q", got, want)
+	}
+
+	for s, want := range map[string]slog.Level{"notice": levelNotice, "FATAL": LevelFatal, "warn+1": slog.LevelWarn + 1} {
+		if got, err := ParseLevel(s); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v", s, got, err)
+		}
+	}
+	if _, err := ParseLevel("VERBOSE"); err == nil {
+		t.Error("未知的级别名称应该返回错误")
+	}
+}
+
+// stepLeveler 每次调用 Level 返回下一个级别
+type stepLeveler struct{ n *int }
+
+func (l stepLeveler) Level() slog.Level {
+	*l.n++
+	return slog.Level(*l.n)
+}
+
+func TestLevelReader(t *testing.T) {
+	if r := newLevelReader(nil); r.load() != slog.LevelInfo {
+		t.Errorf("nil 应该为 Info: %v", r.load())
+	}
+	if r := newLevelReader(slog.LevelWarn); r.load() != slog.LevelWarn {
+		t.Errorf("常量级别错误: %v", r.load())
+	}
+
+	v := NewLevelVar(slog.LevelInfo)
+	logger := NewLogger(io.Discard, &Options{Level: v})
+	if logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Info 级别不应该输出 Debug")
+	}
+	v.Set(slog.LevelDebug)
+	if !logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("LevelVar.Set 之后应该立即生效")
+	}
+
+	n := 0
+	r := newLevelReader(stepLeveler{&n})
+	if r.load() != 1 || r.load() != 2 {
+		t.Error("其它 Leveler 应该每次调用 Level")
+	}
+}
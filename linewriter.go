@@ -0,0 +1,107 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// LineWriter 是按行写入日志的 io.Writer，每一行作为一条记录的消息
+// 用于接入子进程输出、第三方库的 log.Logger 等只会写字节流的来源；
+// 行尾的 \r 会被去掉，空行被忽略，超过 MaxLineSize 的行按长度拆分
+//
+//	w := &slogplus.LineWriter{Logger: logger.With("component", "legacy"), Level: slog.LevelWarn}
+//	defer w.Close()
+//	log.SetOutput(w)
+type LineWriter struct {
+	// Logger 写入的 Logger，为 nil 时使用 slog.Default()
+	Logger *slog.Logger
+
+	// Level 记录的级别
+	Level slog.Level
+
+	// MaxLineSize 单行的最大字节数，默认 64KB
+	MaxLineSize int
+
+	// PartialTimeout 不完整的行在没有新数据的情况下等待该时间后直接输出，
+	// 用于输出提示符、进度条等不以换行结尾的内容；0 表示只在 Flush 或 Close 时输出
+	PartialTimeout time.Duration
+
+	mu    sync.Mutex
+	buf   []byte
+	timer *time.Timer
+}
+
+// Write 实现 io.Writer，输出所有完整的行，剩余部分暂存
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	start := 0
+	for {
+		i := bytes.IndexByte(w.buf[start:], '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(w.buf[start : start+i])
+		start += i + 1
+	}
+	for limit := w.maxLineSize(); len(w.buf)-start >= limit; start += limit {
+		w.emit(w.buf[start : start+limit])
+	}
+	// 剩余部分移到开头，复用底层数组
+	w.buf = w.buf[:copy(w.buf, w.buf[start:])]
+
+	if w.PartialTimeout > 0 && len(w.buf) > 0 {
+		if w.timer == nil {
+			w.timer = time.AfterFunc(w.PartialTimeout, func() { w.Flush() })
+		} else {
+			w.timer.Reset(w.PartialTimeout)
+		}
+	}
+	return len(p), nil
+}
+
+// Flush 输出暂存的不完整的行
+func (w *LineWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.emit(w.buf)
+		w.buf = w.buf[:0]
+	}
+	return nil
+}
+
+// Close 输出暂存的不完整的行并停止计时器
+func (w *LineWriter) Close() error {
+	w.mu.Lock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mu.Unlock()
+	return w.Flush()
+}
+
+func (w *LineWriter) maxLineSize() int {
+	if w.MaxLineSize > 0 {
+		return w.MaxLineSize
+	}
+	return 64 << 10
+}
+
+// emit 输出一行，调用方需持有 mu
+func (w *LineWriter) emit(line []byte) {
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	if len(line) == 0 {
+		return
+	}
+	logger := w.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Log(context.Background(), w.Level, string(line))
+}
@@ -0,0 +1,41 @@
+package slogplus
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLineWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := &LineWriter{Logger: NewLogger(&buf, &Options{TimeFormat: "-"}), Level: slog.LevelWarn, MaxLineSize: 8}
+
+	w.Write([]byte("first\r\nsec"))
+	w.Write([]byte("ond\n\n0123456789"))
+	if got := buf.String(); got != "- WARN msg=first\n- WARN msg=second\n- WARN msg=01234567\n" {
+		t.Errorf("应该按行输出并拆分过长的行: %q", got)
+	}
+
+	buf.Reset()
+	w.Close()
+	if got := buf.String(); got != "- WARN msg=89\n" {
+		t.Errorf("Close 应该输出不完整的行: %q", got)
+	}
+}
+
+func TestLineWriter_PartialTimeout(t *testing.T) {
+	var buf syncBuffer
+	w := &LineWriter{Logger: NewLogger(&buf, nil), PartialTimeout: 10 * time.Millisecond}
+	defer w.Close()
+
+	w.Write([]byte("Password: "))
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "msg=Password: \n") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !strings.Contains(buf.String(), "msg=Password: \n") {
+		t.Errorf("超时后应该输出不完整的行: %q", buf.String())
+	}
+}
@@ -0,0 +1,24 @@
+package slogplus
+
+import (
+	"context"
+	"log/slog"
+)
+
+// loggerKey 是 Logger 在 context 中的键
+type loggerKey struct{}
+
+// NewContext 返回携带 logger 的 context，库代码可以通过 FromContext 取得请求级 Logger，无需全局状态
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext 返回 NewContext 存入的 Logger，没有时返回 slog.Default()
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok && l != nil {
+			return l
+		}
+	}
+	return slog.Default()
+}
@@ -0,0 +1,25 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	if FromContext(context.Background()) != slog.Default() || FromContext(nil) != slog.Default() {
+		t.Error("没有 Logger 时应该返回 slog.Default()")
+	}
+	if FromContext(NewContext(context.Background(), nil)) != slog.Default() {
+		t.Error("nil Logger 应该返回 slog.Default()")
+	}
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{TimeFormat: "-"}).With("request", 1)
+	ctx := NewContext(context.Background(), logger)
+	FromContext(ctx).Info("from library")
+	if got := buf.String(); got != "- INFO request=1 msg=from library\n" {
+		t.Errorf("应该返回存入的 Logger: %q", got)
+	}
+}
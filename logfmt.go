@@ -0,0 +1,134 @@
+package slogplus
+
+import (
+	"log/slog"
+	"unicode/utf8"
+)
+
+const hexDigits = "0123456789abcdef"
+
+// appendLogfmtAttr 按 logfmt 规范追加属性，分组属性展开为 group.key=value
+func (h *Handler) appendLogfmtAttr(buf []byte, groups []string, a slog.Attr) []byte {
+	a.Value = a.Value.Resolve()
+	if c, ok := h.composite(a.Value, 0); ok {
+		a.Value = c
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if a.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], a.Key)
+		}
+		for _, ga := range attrs {
+			if h.opts.ReplaceAttr != nil && ga.Value.Kind() != slog.KindGroup {
+				ga = h.opts.ReplaceAttr(groups, ga)
+				if ga.Equal(slog.Attr{}) {
+					continue
+				}
+			}
+			if h.omitNil(ga.Value) {
+				continue
+			}
+			buf = h.appendLogfmtAttr(buf, groups, ga)
+		}
+		return buf
+	}
+
+	buf = append(buf, ' ')
+	for _, g := range groups {
+		buf = appendLogfmtKey(buf, g)
+		buf = append(buf, '.')
+	}
+	buf = appendLogfmtKey(buf, a.Key)
+	buf = append(buf, '=')
+
+	switch a.Value.Kind() {
+	case slog.KindString, slog.KindAny, slog.KindLogValuer:
+		if isNilValue(a.Value) {
+			return append(buf, nilText...)
+		}
+		return appendLogfmtString(buf, a.Value.String())
+	default:
+		return h.appendValue(buf, a.Value)
+	}
+}
+
+// appendLogfmtKey 追加 logfmt 键，空格、'='、引号和控制字符替换为 '_'
+func appendLogfmtKey(buf []byte, key string) []byte {
+	if key == "" {
+		return append(buf, '_')
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c <= ' ' || c == '=' || c == '"' || c == 0x7f {
+			c = '_'
+		}
+		buf = append(buf, c)
+	}
+	return buf
+}
+
+// needsLogfmtQuote 判断值是否需要加引号
+func needsLogfmtQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for i := 0; i < len(s); {
+		if i = safeASCIIPrefix(s, i, &logfmtSafe); i == len(s) {
+			return false
+		}
+		if s[i] < utf8.RuneSelf {
+			return true
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			return true
+		}
+		i += size
+	}
+	return false
+}
+
+// appendLogfmtString 追加 logfmt 值，必要时加引号并转义
+func appendLogfmtString(buf []byte, s string) []byte {
+	if !needsLogfmtQuote(s) {
+		return append(buf, s...)
+	}
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		// 不需要转义的连续字节整段复制
+		if i = safeASCIIPrefix(s, i, &logfmtQuotedSafe); i == len(s) {
+			break
+		}
+		c := s[i]
+		if c < utf8.RuneSelf {
+			buf = append(buf, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				buf = append(buf, '\\', c)
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			default:
+				buf = append(buf, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, `\ufffd`...)
+			i++
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf = append(buf, s[start:]...)
+	return append(buf, '"')
+}
@@ -0,0 +1,59 @@
+package slogplus
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestAppendLogfmtString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"", `""`},
+		{"hello world", `"hello world"`},
+		{"a=b", `"a=b"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{"line1\nline2", `"line1\nline2"`},
+		{`C:\path`, `"C:\\path"`},
+		{"bell\x07", `"bell\u0007"`},
+		{"中文", "中文"},
+	}
+	for _, tt := range tests {
+		if got := string(appendLogfmtString(nil, tt.in)); got != tt.want {
+			t.Errorf("appendLogfmtString(%q) = %s, 期望 %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHandler_Logfmt(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{Logfmt: true}).With("svc", "user api")
+
+	logger.Info("hello world",
+		"query", "a=1 b=2",
+		"bad key", "x",
+		slog.Group("req", "path", "/users", "note", "multi\nline"),
+	)
+
+	output := buf.String()
+	for _, want := range []string{
+		`time="`,
+		` level=INFO `,
+		` svc="user api" `,
+		` msg="hello world" `,
+		` query="a=1 b=2"`,
+		` bad_key=x`,
+		` req.path=/users`,
+		` req.note="multi\nline"`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("输出应该包含 %s: %s", want, output)
+		}
+	}
+	if strings.Count(output, "\n") != 1 {
+		t.Errorf("每条记录只能占一行: %q", output)
+	}
+}
@@ -7,14 +7,14 @@ import (
 )
 
 // NewLogger 创建一个新的 Logger，使用自定义 Handler
-func NewLogger(out io.Writer, opts *Options) *slog.Logger {
-	return slog.New(New(out, opts))
+func NewLogger(out io.Writer, opts ...Option) *slog.Logger {
+	return slog.New(New(out, opts...))
 }
 
 // Setup 设置全局默认 Logger
 // 这是最常用的初始化方式
-func Setup(out io.Writer, opts *Options) {
-	slog.SetDefault(NewLogger(out, opts))
+func Setup(out io.Writer, opts ...Option) {
+	slog.SetDefault(NewLogger(out, opts...))
 }
 
 // SetupDefault 使用默认配置设置全局 Logger
@@ -38,10 +38,12 @@ func SetupProduction() {
 // - 输出到 stdout
 // - 日志级别为 Debug
 // - 启用源代码位置
+// - 终端输出时启用颜色
 func SetupDevelopment() {
 	Setup(os.Stdout, &Options{
 		Level:     slog.LevelDebug,
 		AddSource: true,
+		Color:     true,
 	})
 }
 
@@ -66,6 +68,7 @@ var DefaultPreset = Preset{
 		Level:      slog.LevelDebug,
 		AddSource:  true,
 		TimeFormat: "2006/01/02 15:04:05",
+		Color:      true,
 	},
 	Test: &Options{
 		Level:      slog.LevelDebug,
@@ -84,4 +87,3 @@ func NewLevelVar(level slog.Level) *LevelVar {
 	v.Set(level)
 	return v
 }
-
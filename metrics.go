@@ -0,0 +1,168 @@
+package slogplus
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// MetricRule 定义从日志记录派生指标的规则
+// 记录包含 Value 属性以及全部 Labels 属性时产生一次观测
+type MetricRule struct {
+	// Name 指标名
+	Name string
+
+	// Message 不为空时只匹配该消息的记录
+	Message string
+
+	// MinLevel 不为 nil 时只匹配该级别及以上的记录
+	MinLevel slog.Leveler
+
+	// Value 作为观测值的属性名，分组属性使用 group.key 形式
+	// 支持整数、浮点数、可解析为数字的字符串，time.Duration 转换为秒
+	// 为空时每条匹配的记录观测值为 1（计数）
+	Value string
+
+	// Labels 作为标签的属性名
+	Labels []string
+}
+
+// MetricObserver 接收派生出的指标观测，例如写入 Prometheus 直方图
+type MetricObserver func(name string, value float64, labels map[string]string)
+
+// DeriveMetrics 包装 h，按 rules 将匹配的记录转换为指标观测
+// 无需重复埋点即可从已有日志中得到延迟等指标
+func DeriveMetrics(h slog.Handler, observe MetricObserver, rules ...MetricRule) slog.Handler {
+	return &metricsHandler{next: h, observe: observe, rules: rules}
+}
+
+type metricsHandler struct {
+	next    slog.Handler
+	observe MetricObserver
+	rules   []MetricRule
+	prefix  string      // WithGroup 产生的键前缀
+	attrs   []slog.Attr // WithAttrs 添加的属性，键已包含前缀
+}
+
+func (h *metricsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *metricsHandler) Handle(ctx context.Context, r slog.Record) error {
+	var attrs map[string]slog.Value
+	for i := range h.rules {
+		rule := &h.rules[i]
+		if rule.Message != "" && rule.Message != r.Message {
+			continue
+		}
+		if rule.MinLevel != nil && r.Level < rule.MinLevel.Level() {
+			continue
+		}
+		if attrs == nil {
+			attrs = h.collect(r)
+		}
+		h.apply(rule, attrs)
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// collect 收集记录的全部属性，键为 group.key 形式
+func (h *metricsHandler) collect(r slog.Record) map[string]slog.Value {
+	m := make(map[string]slog.Value, len(h.attrs)+r.NumAttrs())
+	for _, a := range h.attrs {
+		flattenAttr(m, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		flattenAttr(m, h.prefix, a)
+		return true
+	})
+	return m
+}
+
+func (h *metricsHandler) apply(rule *MetricRule, attrs map[string]slog.Value) {
+	value := 1.0
+	if rule.Value != "" {
+		v, ok := attrs[rule.Value]
+		if !ok {
+			return
+		}
+		if value, ok = metricValue(v); !ok {
+			return
+		}
+	}
+
+	var labels map[string]string
+	if len(rule.Labels) > 0 {
+		labels = make(map[string]string, len(rule.Labels))
+		for _, l := range rule.Labels {
+			v, ok := attrs[l]
+			if !ok {
+				return
+			}
+			labels[l] = v.String()
+		}
+	}
+	h.observe(rule.Name, value, labels)
+}
+
+// metricValue 将属性值转换为观测值
+func metricValue(v slog.Value) (float64, bool) {
+	switch v.Kind() {
+	case slog.KindInt64:
+		return float64(v.Int64()), true
+	case slog.KindUint64:
+		return float64(v.Uint64()), true
+	case slog.KindFloat64:
+		return v.Float64(), true
+	case slog.KindDuration:
+		return v.Duration().Seconds(), true
+	case slog.KindBool:
+		if v.Bool() {
+			return 1, true
+		}
+		return 0, true
+	case slog.KindString:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v.String()), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// flattenAttr 将属性展开到 m 中，分组属性的键为 group.key
+func flattenAttr(m map[string]slog.Value, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		p := prefix
+		if a.Key != "" {
+			p += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			flattenAttr(m, p, ga)
+		}
+		return
+	}
+	m[prefix+a.Key] = v
+}
+
+func (h *metricsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	nh := *h
+	nh.next = h.next.WithAttrs(attrs)
+	nh.attrs = make([]slog.Attr, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(nh.attrs, h.attrs)
+	for _, a := range attrs {
+		nh.attrs = append(nh.attrs, slog.Attr{Key: h.prefix + a.Key, Value: a.Value})
+	}
+	return &nh
+}
+
+func (h *metricsHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	nh := *h
+	nh.next = h.next.WithGroup(name)
+	nh.prefix = h.prefix + name + "."
+	return &nh
+}
@@ -0,0 +1,56 @@
+package slogplus
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+type observation struct {
+	name   string
+	value  float64
+	labels map[string]string
+}
+
+func TestDeriveMetrics(t *testing.T) {
+	var got []observation
+	observe := func(name string, value float64, labels map[string]string) {
+		got = append(got, observation{name, value, labels})
+	}
+
+	h := DeriveMetrics(New(io.Discard, nil), observe,
+		MetricRule{Name: "http_request_seconds", Value: "duration", Labels: []string{"req.path"}},
+		MetricRule{Name: "errors_total", MinLevel: slog.LevelError},
+	)
+	logger := slog.New(h)
+
+	logger.With(slog.Group("req", "path", "/users")).Info("done", "duration", 250*time.Millisecond)
+	logger.Info("done", "duration", time.Second) // 缺少 path 标签
+	logger.Error("failed")
+
+	if len(got) != 2 {
+		t.Fatalf("应该产生 2 次观测: %+v", got)
+	}
+	if got[0].name != "http_request_seconds" || got[0].value != 0.25 || got[0].labels["req.path"] != "/users" {
+		t.Errorf("延迟观测不正确: %+v", got[0])
+	}
+	if got[1].name != "errors_total" || got[1].value != 1 {
+		t.Errorf("错误计数不正确: %+v", got[1])
+	}
+}
+
+func TestDeriveMetrics_WithGroup(t *testing.T) {
+	var got []observation
+	observe := func(name string, value float64, labels map[string]string) {
+		got = append(got, observation{name, value, labels})
+	}
+	h := DeriveMetrics(New(io.Discard, nil), observe,
+		MetricRule{Name: "db_rows", Value: "db.rows", Labels: []string{"db.table"}},
+	)
+	slog.New(h).WithGroup("db").With("table", "users").Info("query", "rows", "42")
+
+	if len(got) != 1 || got[0].value != 42 || got[0].labels["db.table"] != "users" {
+		t.Errorf("分组属性应该以 group.key 匹配: %+v", got)
+	}
+}
@@ -0,0 +1,104 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"log/slog"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// sensitiveHeaders 即使被加入允许列表也始终脱敏的请求头
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// HTTPOptions 定义 HTTP 中间件的配置
+type HTTPOptions struct {
+	// Logger 基础 Logger，默认使用 slog.Default()
+	Logger *slog.Logger
+
+	// Headers 请求头允许列表，例如 X-Tenant、X-Client-Version
+	// 列表中的请求头会作为属性添加到请求级 Logger 上，属性名为小写并将 '-' 替换为 '_'
+	// Authorization、Cookie 等敏感请求头始终输出为 [REDACTED]
+	Headers []string
+
+	// RemoteAddr 是否添加 remote_addr 属性
+	RemoteAddr bool
+
+	// AnonymizeIP 设置后对中间件添加的属性进行 IP 匿名化
+	// 例如 Keys 包含 remote_addr、x_forwarded_for
+	AnonymizeIP *IPAnonymizer
+}
+
+// HTTPMiddleware 返回一个 HTTP 中间件，为每个请求创建带请求属性的 Logger
+// 在处理函数中通过 RequestLogger(r) 或 FromContext(r.Context()) 获取
+func HTTPMiddleware(opts *HTTPOptions) func(http.Handler) http.Handler {
+	var o HTTPOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	type header struct {
+		name      string // 规范化的请求头名称
+		key       string // 属性名
+		sensitive bool
+	}
+	headers := make([]header, 0, len(o.Headers))
+	for _, name := range o.Headers {
+		canonical := textproto.CanonicalMIMEHeaderKey(name)
+		headers = append(headers, header{
+			name:      canonical,
+			key:       strings.ReplaceAll(strings.ToLower(canonical), "-", "_"),
+			sensitive: sensitiveHeaders[canonical],
+		})
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger := o.Logger
+			if logger == nil {
+				logger = slog.Default()
+			}
+
+			var attrs []any
+			if o.RemoteAddr {
+				attrs = append(attrs, o.attr("remote_addr", r.RemoteAddr))
+			}
+			for _, h := range headers {
+				v := r.Header.Get(h.name)
+				if v == "" {
+					continue
+				}
+				if h.sensitive {
+					v = RedactedValue
+				}
+				attrs = append(attrs, o.attr(h.key, v))
+			}
+			if len(attrs) > 0 {
+				logger = logger.With(attrs...)
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), logger)))
+		})
+	}
+}
+
+// attr 创建中间件属性，按配置进行 IP 匿名化
+func (o *HTTPOptions) attr(key, value string) slog.Attr {
+	a := slog.String(key, value)
+	if o.AnonymizeIP != nil {
+		a = o.AnonymizeIP.ReplaceAttr(nil, a)
+	}
+	return a
+}
+
+// RequestLogger 返回 HTTPMiddleware 为请求创建的 Logger，等同于 FromContext(r.Context())
+// 请求未经过中间件时返回 slog.Default()
+func RequestLogger(r *http.Request) *slog.Logger {
+	return FromContext(r.Context())
+}
@@ -0,0 +1,68 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPMiddleware_Headers(t *testing.T) {
+	var buf bytes.Buffer
+	mw := HTTPMiddleware(&HTTPOptions{
+		Logger:  NewLogger(&buf, nil),
+		Headers: []string{"x-tenant", "X-Client-Version", "Authorization", "X-Missing"},
+	})
+
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		RequestLogger(r).Info("handled")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Tenant", "acme")
+	req.Header.Set("X-Client-Version", "1.2.3")
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Other", "ignored")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	output := buf.String()
+	for _, want := range []string{"x_tenant=acme", "x_client_version=1.2.3", "authorization=[REDACTED]"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("输出应该包含 %s: %s", want, output)
+		}
+	}
+	for _, bad := range []string{"secret", "x_other", "x_missing"} {
+		if strings.Contains(output, bad) {
+			t.Errorf("输出不应该包含 %s: %s", bad, output)
+		}
+	}
+}
+
+func TestHTTPMiddleware_AnonymizeIP(t *testing.T) {
+	var buf bytes.Buffer
+	mw := HTTPMiddleware(&HTTPOptions{
+		Logger:      NewLogger(&buf, nil),
+		Headers:     []string{"X-Forwarded-For"},
+		RemoteAddr:  true,
+		AnonymizeIP: &IPAnonymizer{Keys: []string{"remote_addr", "x_forwarded_for"}},
+	})
+
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		RequestLogger(r).Info("handled")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.55:51234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.7")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	output := buf.String()
+	for _, want := range []string{"remote_addr=192.0.2.0:51234", "x_forwarded_for=198.51.100.0"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("输出应该包含 %s: %s", want, output)
+		}
+	}
+}
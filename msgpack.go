@@ -0,0 +1,198 @@
+package slogplus
+
+import (
+	"encoding/binary"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+)
+
+// appendMsgpack 按 MessagePack 格式追加一条记录
+// 每条记录是一个 map: time（时间戳扩展类型）、level、msg、source 以及属性，
+// 分组属性的键为 group.key，分组值输出为嵌套 map。记录之间没有分隔符
+func (h *Handler) appendMsgpack(buf []byte, r slog.Record) []byte {
+	start := len(buf)
+	buf = append(buf, 0xdf, 0, 0, 0, 0) // map32，数量稍后回填
+	n := 0
+
+	if h.opts.TimeFormat != "" && !r.Time.IsZero() {
+		buf = appendMsgpackString(buf, "time")
+		buf = appendMsgpackTime(buf, r.Time)
+		n++
+	}
+	buf = appendMsgpackString(buf, "level")
+	buf = appendMsgpackString(buf, h.levelName(r.Level))
+	n++
+
+	if h.opts.AddSource && r.PC != 0 {
+		f := h.frame(r.PC)
+		if f.File != "" {
+			buf = appendMsgpackString(buf, "source")
+			buf = appendMsgpackString(buf, f.File+":"+strconv.Itoa(f.Line))
+			n++
+		}
+	}
+
+	buf = appendMsgpackString(buf, "msg")
+	buf = appendMsgpackString(buf, r.Message)
+	n++
+
+	for _, ba := range h.attrs {
+		buf = h.appendMsgpackAttr(buf, ba.groups, ba.Attr, &n)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		buf = h.appendMsgpackAttr(buf, h.groups, a, &n)
+		return true
+	})
+
+	binary.BigEndian.PutUint32(buf[start+1:], uint32(n))
+	return buf
+}
+
+// appendMsgpackAttr 追加一个键值对并增加计数
+func (h *Handler) appendMsgpackAttr(buf []byte, groups []string, a slog.Attr, n *int) []byte {
+	if h.opts.ReplaceAttr != nil && a.Value.Kind() != slog.KindGroup {
+		a = h.opts.ReplaceAttr(groups, a)
+	}
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) || h.omitNil(a.Value) {
+		return buf
+	}
+	buf = appendMsgpackString(buf, string(appendJSONDottedKeyRaw(nil, groups, a.Key)))
+	buf = h.appendMsgpackValue(buf, a.Value)
+	*n++
+	return buf
+}
+
+// appendJSONDottedKeyRaw 以 group.key 形式追加键（不转义）
+func appendJSONDottedKeyRaw(buf []byte, groups []string, key string) []byte {
+	for _, g := range groups {
+		buf = append(buf, g...)
+		buf = append(buf, '.')
+	}
+	return append(buf, key...)
+}
+
+// appendMsgpackValue 追加一个值
+func (h *Handler) appendMsgpackValue(buf []byte, v slog.Value) []byte {
+	switch v.Kind() {
+	case slog.KindString:
+		return appendMsgpackString(buf, v.String())
+	case slog.KindInt64:
+		return appendMsgpackInt(buf, v.Int64())
+	case slog.KindUint64:
+		return appendMsgpackUint(buf, v.Uint64())
+	case slog.KindFloat64:
+		buf = append(buf, 0xcb)
+		return binary.BigEndian.AppendUint64(buf, math.Float64bits(v.Float64()))
+	case slog.KindBool:
+		if v.Bool() {
+			return append(buf, 0xc3)
+		}
+		return append(buf, 0xc2)
+	case slog.KindDuration:
+		return appendMsgpackInt(buf, int64(v.Duration()))
+	case slog.KindTime:
+		return appendMsgpackTime(buf, v.Time())
+	case slog.KindGroup:
+		start := len(buf)
+		buf = append(buf, 0xdf, 0, 0, 0, 0)
+		n := 0
+		for _, a := range v.Group() {
+			buf = h.appendMsgpackAttr(buf, nil, a, &n)
+		}
+		binary.BigEndian.PutUint32(buf[start+1:], uint32(n))
+		return buf
+	default:
+		if isNilValue(v) {
+			return append(buf, 0xc0)
+		}
+		switch x := v.Any().(type) {
+		case []byte:
+			return appendMsgpackBin(buf, x)
+		case error:
+			return appendMsgpackString(buf, x.Error())
+		}
+		return appendMsgpackString(buf, v.String())
+	}
+}
+
+// appendMsgpackString 追加 str 类型
+func appendMsgpackString(buf []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		buf = append(buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		buf = append(buf, 0xda)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(n))
+	default:
+		buf = append(buf, 0xdb)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(n))
+	}
+	return append(buf, s...)
+}
+
+// appendMsgpackBin 追加 bin 类型
+func appendMsgpackBin(buf []byte, b []byte) []byte {
+	switch n := len(b); {
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		buf = append(buf, 0xc5)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(n))
+	default:
+		buf = append(buf, 0xc6)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(n))
+	}
+	return append(buf, b...)
+}
+
+// appendMsgpackInt 使用最短编码追加有符号整数
+func appendMsgpackInt(buf []byte, i int64) []byte {
+	switch {
+	case i >= 0:
+		return appendMsgpackUint(buf, uint64(i))
+	case i >= -32:
+		return append(buf, byte(i))
+	case i >= math.MinInt8:
+		return append(buf, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		buf = append(buf, 0xd1)
+		return binary.BigEndian.AppendUint16(buf, uint16(i))
+	case i >= math.MinInt32:
+		buf = append(buf, 0xd2)
+		return binary.BigEndian.AppendUint32(buf, uint32(i))
+	default:
+		buf = append(buf, 0xd3)
+		return binary.BigEndian.AppendUint64(buf, uint64(i))
+	}
+}
+
+// appendMsgpackUint 使用最短编码追加无符号整数
+func appendMsgpackUint(buf []byte, u uint64) []byte {
+	switch {
+	case u < 128:
+		return append(buf, byte(u))
+	case u <= math.MaxUint8:
+		return append(buf, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		buf = append(buf, 0xcd)
+		return binary.BigEndian.AppendUint16(buf, uint16(u))
+	case u <= math.MaxUint32:
+		buf = append(buf, 0xce)
+		return binary.BigEndian.AppendUint32(buf, uint32(u))
+	default:
+		buf = append(buf, 0xcf)
+		return binary.BigEndian.AppendUint64(buf, u)
+	}
+}
+
+// appendMsgpackTime 追加时间戳扩展类型（timestamp 96）
+func appendMsgpackTime(buf []byte, t time.Time) []byte {
+	buf = append(buf, 0xc7, 12, 0xff)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(t.Nanosecond()))
+	return binary.BigEndian.AppendUint64(buf, uint64(t.Unix()))
+}
@@ -0,0 +1,220 @@
+package slogplus
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// MsgpackDecoder 解码 FormatMsgpack 输出的记录流，主要用于测试和调试
+type MsgpackDecoder struct {
+	r *bufio.Reader
+}
+
+// NewMsgpackDecoder 创建从 r 读取的解码器
+func NewMsgpackDecoder(r io.Reader) *MsgpackDecoder {
+	return &MsgpackDecoder{r: bufio.NewReader(r)}
+}
+
+// Decode 读取下一条记录，没有更多记录时返回 io.EOF
+// 字符串解码为 string，整数为 int64（超出范围的无符号整数为 uint64），浮点数为 float64，
+// 时间戳为 time.Time，二进制为 []byte，map 为 map[string]any
+func (d *MsgpackDecoder) Decode() (map[string]any, error) {
+	if _, err := d.r.Peek(1); err != nil {
+		return nil, err
+	}
+	v, err := d.value()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("slogplus: 记录应该是 map，实际为 %T", v)
+	}
+	return m, nil
+}
+
+func (d *MsgpackDecoder) value() (any, error) {
+	c, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return d.mapValue(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return d.arrayValue(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		return d.str(int(c & 0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.length(c - 0xc4)
+		if err != nil {
+			return nil, err
+		}
+		return d.bytes(n)
+	case 0xca:
+		b, err := d.bytes(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 0xcb:
+		b, err := d.bytes(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		b, err := d.bytes(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		u := beUint(b)
+		if u > math.MaxInt64 {
+			return u, nil
+		}
+		return int64(u), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		b, err := d.bytes(size)
+		if err != nil {
+			return nil, err
+		}
+		u := beUint(b)
+		shift := 64 - 8*size
+		return int64(u<<shift) >> shift, nil
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.length(c - 0xd9)
+		if err != nil {
+			return nil, err
+		}
+		return d.str(n)
+	case 0xdc, 0xdd:
+		n, err := d.length(c - 0xdc + 1)
+		if err != nil {
+			return nil, err
+		}
+		return d.arrayValue(n)
+	case 0xde, 0xdf:
+		n, err := d.length(c - 0xde + 1)
+		if err != nil {
+			return nil, err
+		}
+		return d.mapValue(n)
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.ext(1 << (c - 0xd4))
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.length(c - 0xc7)
+		if err != nil {
+			return nil, err
+		}
+		return d.ext(n)
+	}
+	return nil, fmt.Errorf("slogplus: 不支持的 MessagePack 类型 0x%02x", c)
+}
+
+// length 读取 1、2、4 字节的长度，sizeIdx 为 0、1、2
+func (d *MsgpackDecoder) length(sizeIdx byte) (int, error) {
+	b, err := d.bytes(1 << sizeIdx)
+	if err != nil {
+		return 0, err
+	}
+	return int(beUint(b)), nil
+}
+
+func (d *MsgpackDecoder) bytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	_, err := io.ReadFull(d.r, b)
+	return b, err
+}
+
+func (d *MsgpackDecoder) str(n int) (string, error) {
+	b, err := d.bytes(n)
+	return string(b), err
+}
+
+func (d *MsgpackDecoder) mapValue(n int) (map[string]any, error) {
+	m := make(map[string]any, n)
+	for i := 0; i < n; i++ {
+		k, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			key = fmt.Sprint(k)
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+func (d *MsgpackDecoder) arrayValue(n int) ([]any, error) {
+	a := make([]any, n)
+	for i := range a {
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		a[i] = v
+	}
+	return a, nil
+}
+
+// ext 解码扩展类型，时间戳（类型 -1）解码为 time.Time，其它返回原始字节
+func (d *MsgpackDecoder) ext(n int) (any, error) {
+	typ, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	b, err := d.bytes(n)
+	if err != nil {
+		return nil, err
+	}
+	if int8(typ) != -1 {
+		return b, nil
+	}
+	switch n {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(b)), 0), nil
+	case 8:
+		v := binary.BigEndian.Uint64(b)
+		return time.Unix(int64(v&0x3ffffffff), int64(v>>34)), nil
+	case 12:
+		return time.Unix(int64(binary.BigEndian.Uint64(b[4:])), int64(binary.BigEndian.Uint32(b))), nil
+	}
+	return nil, errors.New("slogplus: 无效的时间戳长度")
+}
+
+func beUint(b []byte) uint64 {
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u
+}
@@ -0,0 +1,80 @@
+package slogplus
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_Msgpack(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{Format: FormatMsgpack}).With("svc", "api").WithGroup("req")
+
+	now := time.Date(2025, 11, 14, 14, 3, 14, 123456789, time.UTC)
+	logger.Info("first",
+		"n", -1000,
+		"u", uint64(1<<40),
+		"f", 3.5,
+		"ok", true,
+		"d", time.Second,
+		"at", now,
+		"err", errors.New("boom"),
+		"long", strings.Repeat("x", 300),
+		slog.Group("user", "id", 7),
+	)
+	logger.Warn("second")
+
+	dec := NewMsgpackDecoder(&buf)
+	m, err := dec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"level":    "INFO",
+		"msg":      "first",
+		"svc":      "api",
+		"req.n":    int64(-1000),
+		"req.u":    int64(1 << 40),
+		"req.f":    3.5,
+		"req.ok":   true,
+		"req.d":    int64(time.Second),
+		"req.err":  "boom",
+		"req.long": strings.Repeat("x", 300),
+	}
+	for k, v := range want {
+		if m[k] != v {
+			t.Errorf("%s = %#v, 期望 %#v", k, m[k], v)
+		}
+	}
+	if at, _ := m["req.at"].(time.Time); !at.Equal(now) {
+		t.Errorf("时间戳不正确: %v", m["req.at"])
+	}
+	if _, ok := m["time"].(time.Time); !ok {
+		t.Errorf("应该包含记录时间: %#v", m["time"])
+	}
+	if user, _ := m["req.user"].(map[string]any); user["id"] != int64(7) {
+		t.Errorf("分组值应该解码为嵌套 map: %#v", m["req.user"])
+	}
+
+	m, err = dec.Decode()
+	if err != nil || m["msg"] != "second" {
+		t.Fatalf("应该能解码第二条记录: %v %v", m, err)
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		t.Errorf("没有更多记录时应该返回 io.EOF: %v", err)
+	}
+}
+
+func BenchmarkHandler_Msgpack(b *testing.B) {
+	logger := NewLogger(io.Discard, &Options{Format: FormatMsgpack})
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		logger.Info("test message", "key1", "value1", "key2", 42, "key3", true)
+	}
+}
@@ -0,0 +1,304 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NetOptions 定义 NetWriter 的配置
+type NetOptions struct {
+	// DialTimeout 建立连接的超时时间，默认 10 秒
+	DialTimeout time.Duration
+
+	// WriteTimeout 单次写入的超时时间，默认 5 秒
+	WriteTimeout time.Duration
+
+	// BacklogSize 断开期间在内存中暂存的最大字节数，超出时丢弃最旧的记录，默认 1MB
+	BacklogSize int
+
+	// Backoff 重连的退避策略，只使用其中的等待时间配置，默认使用 RetryPolicy 的默认值
+	Backoff *RetryPolicy
+
+	// Security TCP 连接的 TLS 和代理配置
+	Security *SinkSecurity
+
+	// OnTimeout 记录在截止时间（见 Options.WriteTimeout）之前没有写完时的处理方式，
+	// 默认 TimeoutQueue 暂存并重连后补发，TimeoutDrop 直接丢弃
+	OnTimeout TimeoutPolicy
+}
+
+// NetWriter 通过 TCP、UDP、unix socket 或命名管道将日志发送到采集端
+// 连接断开时记录暂存在内存中，后台按退避策略重连，连接恢复后按顺序补发，
+// Write 不会因为重连而阻塞。每次 Write 视为一条记录，UDP 和 unixgram 下每条记录是一个数据包
+type NetWriter struct {
+	open func() (writeConn, error)
+	opts NetOptions
+
+	mu           sync.Mutex
+	conn         writeConn
+	backlog      [][]byte
+	backlogBytes int
+	reconnecting bool // 后台正在重连或补发暂存的记录，期间新记录进入暂存区以保证顺序
+	closed       bool
+
+	dropped atomic.Int64
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// writeConn 是 NetWriter 写入的连接，net.Conn 和 *os.File 都满足
+type writeConn interface {
+	io.WriteCloser
+	SetWriteDeadline(t time.Time) error
+}
+
+// NewNetWriter 创建网络输出，network 可以是 "tcp"、"udp"、"unix"、"unixgram" 等，
+// 本机的 vector、fluent-bit 等采集端可以使用 unix socket 避免 TCP 回环的开销
+// 首次连接失败不会返回错误，而是在后台重连，期间的记录进入暂存区
+func NewNetWriter(network, addr string, opts *NetOptions) (*NetWriter, error) {
+	if !strings.HasPrefix(network, "tcp") && !strings.HasPrefix(network, "udp") && !strings.HasPrefix(network, "unix") {
+		return nil, fmt.Errorf("slogplus: 不支持的网络类型 %q", network)
+	}
+	w := newNetWriter(opts)
+	w.open = func() (writeConn, error) {
+		return w.dial(network, addr)
+	}
+	w.connect()
+	return w, nil
+}
+
+// newNetWriter 创建尚未连接的 NetWriter 并设置默认值
+func newNetWriter(opts *NetOptions) *NetWriter {
+	w := &NetWriter{stop: make(chan struct{})}
+	if opts != nil {
+		w.opts = *opts
+	}
+
+	// 设置默认值
+	if w.opts.DialTimeout <= 0 {
+		w.opts.DialTimeout = 10 * time.Second
+	}
+	if w.opts.WriteTimeout <= 0 {
+		w.opts.WriteTimeout = 5 * time.Second
+	}
+	if w.opts.BacklogSize <= 0 {
+		w.opts.BacklogSize = 1 << 20
+	}
+	if w.opts.Backoff == nil {
+		w.opts.Backoff = &RetryPolicy{}
+	}
+
+	return w
+}
+
+// connect 首次连接，失败时在后台重连
+func (w *NetWriter) connect() {
+	if conn, err := w.open(); err == nil {
+		w.conn = conn
+	} else {
+		w.mu.Lock()
+		w.startReconnect()
+		w.mu.Unlock()
+	}
+}
+
+// Write 发送一条记录，未连接或发送失败时暂存并在后台重连
+func (w *NetWriter) Write(p []byte) (int, error) {
+	return w.WriteDeadline(p, time.Time{})
+}
+
+// WriteDeadline 实现 DeadlineWriter，写入在 deadline 和 WriteTimeout 中较早的时间之前没有完成时，
+// 按 OnTimeout 暂存或丢弃记录；超时后连接中可能残留不完整的记录，因此会断开重连
+// deadline 在写入前就已经过去时（例如请求超时后记录的日志）连接并没有问题，只按 WriteTimeout 发送
+func (w *NetWriter) WriteDeadline(p []byte, deadline time.Time) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, net.ErrClosed
+	}
+	if !deadline.IsZero() && !time.Now().Before(deadline) {
+		deadline = time.Time{}
+	}
+
+	// 有暂存记录或正在补发时必须先补发，保证顺序
+	if w.conn != nil && !w.reconnecting && len(w.backlog) == 0 {
+		err := w.send(w.conn, p, deadline)
+		if err == nil {
+			return len(p), nil
+		}
+		w.conn.Close()
+		w.conn = nil
+		if ne, ok := err.(net.Error); ok && ne.Timeout() && !deadline.IsZero() && !time.Now().Before(deadline) {
+			return w.timeout(p)
+		}
+	}
+
+	w.enqueue(p)
+	w.startReconnect()
+	return len(p), nil
+}
+
+// timeout 按 OnTimeout 处理写入超时的记录，调用方需持有 mu 并已断开连接
+// 暂存的记录由后台重连后补发
+func (w *NetWriter) timeout(p []byte) (int, error) {
+	w.startReconnect()
+	if w.opts.OnTimeout == TimeoutDrop {
+		w.dropped.Add(1)
+		return 0, ErrWriteTimeout
+	}
+	w.enqueue(p)
+	return len(p), nil
+}
+
+// Connected 返回当前是否已连接
+func (w *NetWriter) Connected() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.conn != nil
+}
+
+// Dropped 返回因暂存区已满或超时而丢弃的记录数
+func (w *NetWriter) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Close 停止重连并关闭连接，仍在暂存区中的记录会被丢弃
+func (w *NetWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.stop)
+	w.mu.Unlock()
+
+	w.wg.Wait()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.dropped.Add(int64(len(w.backlog)))
+	w.backlog = nil
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+func (w *NetWriter) dial(network, addr string) (writeConn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.opts.DialTimeout)
+	defer cancel()
+	if strings.HasPrefix(network, "tcp") {
+		return w.opts.Security.DialContext(ctx, network, addr)
+	}
+	var d net.Dialer
+	return d.DialContext(ctx, network, addr)
+}
+
+// send 带超时地写入一条记录，deadline 早于 WriteTimeout 时使用 deadline
+func (w *NetWriter) send(conn writeConn, p []byte, deadline time.Time) error {
+	conn.SetWriteDeadline(earliest(deadline, time.Now().Add(w.opts.WriteTimeout)))
+	_, err := conn.Write(p)
+	return err
+}
+
+// enqueue 将记录加入暂存区，超出容量时丢弃最旧的记录，调用方需持有 mu
+func (w *NetWriter) enqueue(p []byte) {
+	if len(p) > w.opts.BacklogSize {
+		w.dropped.Add(1)
+		return
+	}
+	w.backlog = append(w.backlog, append([]byte(nil), p...))
+	w.backlogBytes += len(p)
+	w.trimBacklog()
+}
+
+// trimBacklog 丢弃最旧的记录直到暂存区不超过容量，调用方需持有 mu
+func (w *NetWriter) trimBacklog() {
+	for w.backlogBytes > w.opts.BacklogSize {
+		w.backlogBytes -= len(w.backlog[0])
+		w.backlog[0] = nil
+		w.backlog = w.backlog[1:]
+		w.dropped.Add(1)
+	}
+}
+
+// startReconnect 启动后台重连，调用方需持有 mu
+func (w *NetWriter) startReconnect() {
+	if w.reconnecting || w.closed {
+		return
+	}
+	w.reconnecting = true
+	w.wg.Add(1)
+	go w.reconnect()
+}
+
+// reconnect 按退避策略重连，成功后补发暂存的记录
+func (w *NetWriter) reconnect() {
+	defer w.wg.Done()
+
+	for attempt := 1; ; attempt++ {
+		if conn, err := w.open(); err == nil {
+			w.mu.Lock()
+			w.conn = conn
+			w.mu.Unlock()
+			if w.flushBacklog(conn) {
+				return
+			}
+		}
+
+		t := time.NewTimer(w.opts.Backoff.Backoff(attempt))
+		select {
+		case <-t.C:
+		case <-w.stop:
+			t.Stop()
+			w.mu.Lock()
+			w.reconnecting = false
+			w.mu.Unlock()
+			return
+		}
+	}
+}
+
+// flushBacklog 按顺序补发暂存的记录，全部发送完成后结束重连并返回 true
+// 每次在 mu 内取出当前的暂存区，在 mu 外发送，期间的新记录进入暂存区，下一轮继续发送，
+// 因此 Write 不会等待补发；发送失败时未发送的记录放回暂存区的最前面并断开连接
+func (w *NetWriter) flushBacklog(conn writeConn) bool {
+	for {
+		w.mu.Lock()
+		if len(w.backlog) == 0 || w.closed {
+			w.reconnecting = false
+			w.mu.Unlock()
+			return true
+		}
+		batch := w.backlog
+		w.backlog, w.backlogBytes = nil, 0
+		w.mu.Unlock()
+
+		for i, p := range batch {
+			if err := w.send(conn, p, time.Time{}); err != nil {
+				w.mu.Lock()
+				for _, p := range batch[i:] {
+					w.backlogBytes += len(p)
+				}
+				w.backlog = append(batch[i:], w.backlog...)
+				w.trimBacklog()
+				conn.Close()
+				w.conn = nil
+				w.mu.Unlock()
+				return false
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/IAmMrChen/slogplus/sinktest"
+)
+
+func TestNetWriter_Reconnect(t *testing.T) {
+	c := sinktest.NewTCPCollector(t)
+	c.Stop() // 模拟采集端尚未启动
+
+	w, err := NewNetWriter("tcp", c.Addr, &NetOptions{
+		Backoff: &RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if w.Connected() {
+		t.Fatal("采集端未启动时不应该连接成功")
+	}
+	w.Write([]byte("one\n"))
+	w.Write([]byte("two\n"))
+
+	c.Start()
+	// 重连后先补发断线期间暂存的两行
+	if lines := c.WaitForLines(2, 5*time.Second); !slices.Equal(lines, []string{"one", "two"}) {
+		t.Fatalf("重连后应该补发暂存的记录: %q", lines)
+	}
+	w.Write([]byte("three\n"))
+	if lines := c.WaitForLines(3, 5*time.Second); !slices.Equal(lines, []string{"one", "two", "three"}) {
+		t.Errorf("收到 %q, 期望 one two three", lines)
+	}
+}
+
+func TestNetWriter_BacklogLimit(t *testing.T) {
+	w, err := NewNetWriter("unix", "/nonexistent/slogplus.sock", &NetOptions{BacklogSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	w.Write([]byte("12345\n"))
+	w.Write([]byte("67890\n"))
+	if got := w.Dropped(); got != 1 {
+		t.Errorf("Dropped() = %d, 期望 1", got)
+	}
+	w.mu.Lock()
+	if len(w.backlog) != 1 || string(w.backlog[0]) != "67890\n" {
+		t.Errorf("应该保留最新的记录: %q", w.backlog)
+	}
+	w.mu.Unlock()
+}
+
+func TestNewNetWriter_InvalidNetwork(t *testing.T) {
+	if _, err := NewNetWriter("ip4:1", "127.0.0.1", nil); err == nil {
+		t.Error("不支持的网络类型应该返回错误")
+	}
+}
+
+func TestNetWriter_WriteDeadline(t *testing.T) {
+	c := sinktest.NewTCPCollector(t)
+
+	w, err := NewNetWriter("tcp", c.Addr, &NetOptions{OnTimeout: TimeoutDrop})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// 截止时间已经过去的记录照常发送，不应该断开正常的连接
+	for range 5 {
+		if _, err := w.WriteDeadline([]byte("late\n"), time.Now().Add(-time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.WriteDeadline([]byte("next\n"), time.Now().Add(time.Second))
+
+	lines := c.WaitForLines(6, 5*time.Second)
+	if len(lines) != 6 || lines[5] != "next" {
+		t.Errorf("超时前记录的日志应该按顺序发送: %q", lines)
+	}
+	if n := c.Accepted(); n != 1 {
+		t.Errorf("应该只建立一个连接, 实际 %d 个", n)
+	}
+	if w.Dropped() != 0 {
+		t.Errorf("不应该丢弃记录: %d", w.Dropped())
+	}
+}
+
+// slowConn 在 release 关闭之前阻塞所有写入
+type slowConn struct {
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (c *slowConn) Write(p []byte) (int, error) {
+	c.once.Do(func() { close(c.started) })
+	<-c.release
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.buf = append(c.buf, p...)
+	return len(p), nil
+}
+
+func (c *slowConn) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return string(c.buf)
+}
+
+func (c *slowConn) SetWriteDeadline(time.Time) error { return nil }
+func (c *slowConn) Close() error                     { return nil }
+
+func TestNetWriter_FlushDoesNotBlockWrite(t *testing.T) {
+	conn := &slowConn{started: make(chan struct{}), release: make(chan struct{})}
+	var up atomic.Bool
+	w := newNetWriter(&NetOptions{Backoff: &RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}})
+	w.open = func() (writeConn, error) {
+		if !up.Load() {
+			return nil, errors.New("down")
+		}
+		return conn, nil
+	}
+	w.connect()
+	defer w.Close()
+
+	w.Write([]byte("one\n"))
+	up.Store(true)
+	<-conn.started
+
+	// 补发期间的新记录进入暂存区，不等待补发完成
+	done := make(chan struct{})
+	go func() {
+		w.Write([]byte("two\n"))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("补发期间 Write 不应该阻塞")
+	}
+
+	close(conn.release)
+	deadline := time.Now().Add(5 * time.Second)
+	for conn.String() != "one\ntwo\n" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := conn.String(); got != "one\ntwo\n" {
+		t.Errorf("补发后应该按顺序发送: %q", got)
+	}
+}
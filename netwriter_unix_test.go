@@ -0,0 +1,82 @@
+//go:build unix && !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNetWriter_Unixgram(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+	ln, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skip(err)
+	}
+	defer ln.Close()
+
+	w, err := NewNetWriter("unixgram", path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.Write([]byte("first\n"))
+	w.Write([]byte("second\n"))
+
+	ln.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 64)
+	for _, want := range []string{"first\n", "second\n"} {
+		n, err := ln.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(buf[:n]) != want {
+			t.Errorf("每条记录应该是一个数据包: %q", buf[:n])
+		}
+	}
+}
+
+func TestPipeWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.fifo")
+	if err := syscall.Mkfifo(path, 0o600); err != nil {
+		t.Skip(err)
+	}
+
+	w, err := NewPipeWriter(path, &NetOptions{
+		Backoff: &RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if w.Connected() {
+		t.Fatal("没有读取端时不应该打开成功")
+	}
+	w.Write([]byte("one\n"))
+
+	// 以读写方式打开，避免没有写入端时读到 EOF
+	r, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	r.SetReadDeadline(time.Now().Add(5 * time.Second))
+	lines := bufio.NewScanner(r)
+
+	if !lines.Scan() || lines.Text() != "one" {
+		t.Fatalf("读取端启动后应该补发暂存的记录: %q %v", lines.Text(), lines.Err())
+	}
+	w.Write([]byte("two\n"))
+	if !lines.Scan() || lines.Text() != "two" {
+		t.Fatalf("应该直接写入管道: %q %v", lines.Text(), lines.Err())
+	}
+
+	if _, err := NewPipeWriter(filepath.Join(t.TempDir(), "missing"), nil); err != nil {
+		t.Error("管道不存在时应该在后台重试而不是返回错误")
+	}
+}
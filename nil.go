@@ -0,0 +1,31 @@
+package slogplus
+
+import (
+	"log/slog"
+	"reflect"
+)
+
+// nilText 是文本类格式中 nil 值的输出
+const nilText = "<nil>"
+
+// isNilValue 判断值是否为 nil
+// 包括 slog.Any(nil)、零值 slog.Value，以及值为 nil 的指针、map、切片、函数、通道
+func isNilValue(v slog.Value) bool {
+	if v.Kind() != slog.KindAny {
+		return false
+	}
+	x := v.Any()
+	if x == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(x); rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface, reflect.UnsafePointer:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// omitNil 判断是否应该因为 OmitNil 省略该值
+func (h *Handler) omitNil(v slog.Value) bool {
+	return h.opts.OmitNil && isNilValue(v)
+}
@@ -0,0 +1,71 @@
+package slogplus
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type nilTestError struct{}
+
+func (*nilTestError) Error() string { return "never called" }
+
+func TestIsNilValue(t *testing.T) {
+	var p *int
+	var m map[string]int
+	var e *nilTestError
+	var err error = e
+	tests := []struct {
+		v    slog.Value
+		want bool
+	}{
+		{slog.AnyValue(nil), true},
+		{slog.Value{}, true},
+		{slog.AnyValue(p), true},
+		{slog.AnyValue(m), true},
+		{slog.AnyValue([]string(nil)), true},
+		{slog.AnyValue(err), true},
+		{slog.AnyValue(new(int)), false},
+		{slog.AnyValue(struct{}{}), false},
+		{slog.StringValue(""), false},
+		{slog.IntValue(0), false},
+	}
+	for i, tt := range tests {
+		if got := isNilValue(tt.v); got != tt.want {
+			t.Errorf("#%d isNilValue(%v) = %v, 期望 %v", i, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestHandler_NilValues(t *testing.T) {
+	var typedErr *nilTestError
+	args := []any{"a", nil, slog.Any("b", slog.Value{}), "c", typedErr, "d", 1, slog.Group("g", "e", nil, "f", 2)}
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"text", Options{}, "INFO msg=m a=<nil> b=<nil> c=<nil> d=1 g={e=<nil> f=2}\n"},
+		{"text omit", Options{OmitNil: true}, "INFO msg=m d=1 g={f=2}\n"},
+		{"logfmt", Options{Logfmt: true}, "level=INFO msg=m a=<nil> b=<nil> c=<nil> d=1 g.e=<nil> g.f=2\n"},
+		{"logfmt omit", Options{Logfmt: true, OmitNil: true}, "level=INFO msg=m d=1 g.f=2\n"},
+		{"json", Options{Format: FormatJSON}, `"msg":"m","a":null,"b":null,"c":null,"d":1,"g":{"e":null,"f":2}}`},
+		{"json omit", Options{Format: FormatJSON, OmitNil: true}, `"msg":"m","d":1,"g":{"f":2}}`},
+		{"ecs", Options{Format: FormatECS}, `"c":null`},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		h := New(&buf, &tt.opts)
+		h.opts.TimeFormat = ""
+		slog.New(h).Info("m", args...)
+		if tt.opts.Format == FormatText {
+			if buf.String() != tt.want {
+				t.Errorf("%s: %q, 期望 %q", tt.name, buf.String(), tt.want)
+			}
+		} else if !strings.Contains(buf.String(), tt.want) {
+			t.Errorf("%s: %s 应该包含 %s", tt.name, buf.String(), tt.want)
+		}
+	}
+}
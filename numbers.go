@@ -0,0 +1,91 @@
+package slogplus
+
+import (
+	"strconv"
+	"time"
+)
+
+// NumberFormat 控制浮点数、时长和大整数的输出形式，用于需要数值字段格式一致的分析管道
+// 默认使用 Go 的最短表示，例如 0.1+0.2 输出为 0.30000000000000004，时长输出为 1.5s 或 1m30s
+//
+//	&slogplus.Options{Numbers: &slogplus.NumberFormat{
+//		FloatFormat: 'f', FloatPrecision: 3, // 1.500
+//		DurationUnit: time.Millisecond, DurationPrecision: 1, // 文本为 1500.0ms，JSON 为 1500.0
+//	}}
+type NumberFormat struct {
+	// FloatFormat 浮点数格式，同 strconv.FormatFloat 的 fmt 参数（'f'、'e'、'g' 等），默认为 'g'
+	FloatFormat byte
+
+	// FloatPrecision 浮点数精度，同 strconv.FormatFloat 的 prec 参数，仅在设置了 FloatFormat 时生效
+	// 'f' 和 'e' 为小数位数，'g' 为有效数字位数，-1 为能精确还原的最短表示
+	FloatPrecision int
+
+	// DurationUnit 时长的单位，设置后时长始终以该单位的数值输出，文本格式带有单位后缀（ns、us、ms、s、m、h）
+	// JSON 类格式为不带单位的数字；默认文本格式为 time.Duration.String()，JSON 类格式为纳秒数
+	DurationUnit time.Duration
+
+	// DurationPrecision 设置 DurationUnit 后时长保留的小数位数，-1 为最短表示
+	DurationPrecision int
+
+	// LargeIntAsString JSON 类格式中绝对值超过 2^53 的整数输出为字符串，
+	// 避免 JavaScript 等使用双精度浮点数解析 JSON 的下游丢失精度
+	LargeIntAsString bool
+}
+
+// maxSafeInt 是双精度浮点数可以精确表示的最大整数
+const maxSafeInt = 1<<53 - 1
+
+// appendFloat 按配置追加浮点数，n 为 nil 时使用最短表示
+func (n *NumberFormat) appendFloat(buf []byte, f float64) []byte {
+	if n == nil || n.FloatFormat == 0 {
+		return strconv.AppendFloat(buf, f, 'g', -1, 64)
+	}
+	return strconv.AppendFloat(buf, f, n.FloatFormat, n.FloatPrecision, 64)
+}
+
+// appendDuration 按配置追加时长，没有设置 DurationUnit 时返回 false
+func (n *NumberFormat) appendDuration(buf []byte, d time.Duration, suffix bool) ([]byte, bool) {
+	if n == nil || n.DurationUnit <= 0 {
+		return buf, false
+	}
+	v := float64(d) / float64(n.DurationUnit)
+	if n.DurationUnit == 1 || n.DurationPrecision == 0 && d%n.DurationUnit == 0 {
+		// 整数部分可以精确表示，避免大时长经过浮点数后丢失精度
+		buf = strconv.AppendInt(buf, int64(d/n.DurationUnit), 10)
+	} else {
+		buf = strconv.AppendFloat(buf, v, 'f', n.DurationPrecision, 64)
+	}
+	if suffix {
+		buf = append(buf, durationUnitSuffix(n.DurationUnit)...)
+	}
+	return buf, true
+}
+
+// durationUnitSuffix 返回时长单位的后缀，不是常用单位时返回空字符串
+func durationUnitSuffix(unit time.Duration) string {
+	switch unit {
+	case time.Nanosecond:
+		return "ns"
+	case time.Microsecond:
+		return "us"
+	case time.Millisecond:
+		return "ms"
+	case time.Second:
+		return "s"
+	case time.Minute:
+		return "m"
+	case time.Hour:
+		return "h"
+	}
+	return ""
+}
+
+// largeInt 判断 JSON 类格式中该整数是否需要输出为字符串
+func (n *NumberFormat) largeInt(i int64) bool {
+	return n != nil && n.LargeIntAsString && (i > maxSafeInt || i < -maxSafeInt)
+}
+
+// largeUint 判断 JSON 类格式中该无符号整数是否需要输出为字符串
+func (n *NumberFormat) largeUint(u uint64) bool {
+	return n != nil && n.LargeIntAsString && u > maxSafeInt
+}
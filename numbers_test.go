@@ -0,0 +1,57 @@
+package slogplus
+
+import (
+	"bytes"
+	"log/slog"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNumberFormat_Text(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, &Options{TimeFormat: "-", Numbers: &NumberFormat{
+		FloatFormat:       'f',
+		FloatPrecision:    2,
+		DurationUnit:      time.Millisecond,
+		DurationPrecision: 1,
+	}}))
+	logger.Info("done", "ratio", 0.1+0.2, "elapsed", 1500*time.Microsecond, "total", 2*time.Minute)
+
+	if got := buf.String(); got != "- INFO msg=done ratio=0.30 elapsed=1.5ms total=120000.0ms\n" {
+		t.Errorf("数值格式错误: %q", got)
+	}
+}
+
+func TestNumberFormat_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, &Options{TimeFormat: "-", Format: FormatJSON, Numbers: &NumberFormat{
+		FloatFormat:      'e',
+		FloatPrecision:   3,
+		DurationUnit:     time.Second,
+		LargeIntAsString: true,
+	}}))
+	logger.Info("done",
+		"f", 12345.678,
+		"nan", math.NaN(),
+		"d", 90*time.Second,
+		"small", int64(maxSafeInt),
+		"big", int64(maxSafeInt+1),
+		"neg", int64(-maxSafeInt-1),
+		"ubig", uint64(math.MaxUint64),
+	)
+
+	want := `"f":1.235e+04,"nan":"NaN","d":90,"small":9007199254740991,"big":"9007199254740992","neg":"-9007199254740992","ubig":"18446744073709551615"}`
+	if got := buf.String(); !bytes.Contains([]byte(got), []byte(want)) {
+		t.Errorf("数值格式错误: %s", got)
+	}
+}
+
+func TestNumberFormat_Default(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, &Options{TimeFormat: "-"}))
+	logger.Info("done", "ratio", 0.5, "elapsed", 1500*time.Millisecond)
+	if got := buf.String(); got != "- INFO msg=done ratio=0.5 elapsed=1.5s\n" {
+		t.Errorf("默认应该保持最短表示: %q", got)
+	}
+}
@@ -0,0 +1,112 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxObjectDepth 是 Object 展开嵌套结构体的最大层数，超过后按 fmt 的默认格式输出，避免指针环导致无限递归
+const maxObjectDepth = 8
+
+// Object 返回按字段输出结构体的 slog.LogValuer，结构体输出为分组而不是 %+v 的字符串
+//
+//	type User struct {
+//		ID       int64  `log:"id"`
+//		Name     string `log:"name"`
+//		Email    string `log:"email,omitempty"`
+//		Password string `log:"-"`
+//	}
+//	logger.Info("登录", "user", slogplus.Object(u)) // user={id=42 name=alice}
+//
+// 标签为 log:"name,omitempty"：name 为空时使用字段名，omitempty 省略零值字段，"-" 跳过该字段；
+// 未导出字段被忽略，嵌套的结构体和结构体指针递归展开，实现了 slog.LogValuer 的字段使用其自身的输出。
+// 每种类型的字段列表只在第一次使用时通过反射计算。v 不是结构体时按 slog.AnyValue 输出
+func Object(v any) slog.LogValuer {
+	return objectValue{v: v}
+}
+
+type objectValue struct {
+	v     any
+	depth int
+}
+
+// LogValue 实现 slog.LogValuer
+func (o objectValue) LogValue() slog.Value {
+	rv := reflect.ValueOf(o.v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct || o.depth >= maxObjectDepth {
+		return slog.AnyValue(o.v)
+	}
+
+	plan := objectPlanFor(rv.Type())
+	attrs := make([]slog.Attr, 0, len(plan))
+	for _, f := range plan {
+		fv := rv.Field(f.index)
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		attrs = append(attrs, slog.Attr{Key: f.name, Value: o.fieldValue(fv)})
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// fieldValue 返回字段的输出值，嵌套的结构体递归展开
+func (o objectValue) fieldValue(fv reflect.Value) slog.Value {
+	x := fv.Interface()
+	if _, ok := x.(slog.LogValuer); ok {
+		return slog.AnyValue(x)
+	}
+	t := fv.Type()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct && t != timeType && !(fv.Kind() == reflect.Pointer && fv.IsNil()) {
+		return slog.AnyValue(objectValue{v: x, depth: o.depth + 1})
+	}
+	return slog.AnyValue(x)
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// objectField 是结构体中需要输出的字段
+type objectField struct {
+	index     int
+	name      string
+	omitEmpty bool
+}
+
+// objectPlans 缓存每种结构体类型的字段列表
+var objectPlans sync.Map // reflect.Type -> []objectField
+
+// objectPlanFor 返回结构体类型的字段列表
+func objectPlanFor(t reflect.Type) []objectField {
+	if p, ok := objectPlans.Load(t); ok {
+		return p.([]objectField)
+	}
+
+	plan := make([]objectField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("log")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = sf.Name
+		}
+		plan = append(plan, objectField{index: i, name: name, omitEmpty: opts == "omitempty"})
+	}
+	p, _ := objectPlans.LoadOrStore(t, plan)
+	return p.([]objectField)
+}
@@ -0,0 +1,76 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type objectAddress struct {
+	City string `log:"city"`
+}
+
+type objectUser struct {
+	ID       int64          `log:"id"`
+	Name     string         `log:"name"`
+	Email    string         `log:"email,omitempty"`
+	Password string         `log:"-"`
+	Address  *objectAddress `log:"address,omitempty"`
+	Created  time.Time      `log:"created"`
+	Tags     []string
+	internal int
+}
+
+func TestObject(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{TimeFormat: "-"})
+
+	u := objectUser{ID: 42, Name: "alice", Password: "secret", Address: &objectAddress{City: "杭州"}, internal: 1}
+	logger.Info("登录", "user", Object(&u))
+	want := "- INFO msg=登录 user={id=42 name=alice address={city=杭州} created=0001-01-01T00:00:00Z Tags=<nil>}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("输出错误:\n got %q\nwant %q", got, want)
+	}
+
+	buf.Reset()
+	logger = NewLogger(&buf, &Options{Format: FormatJSON})
+	logger.Info("登录", "user", Object(objectUser{ID: 7, Email: "a@b.c"}))
+	var m struct {
+		User map[string]any `json:"user"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.User["id"] != float64(7) || m.User["email"] != "a@b.c" || m.User["Password"] != nil || m.User["address"] != nil {
+		t.Errorf("JSON 输出错误: %v", m.User)
+	}
+}
+
+func TestObject_NonStruct(t *testing.T) {
+	if v := Object(3).LogValue(); v.Kind() != slog.KindInt64 || v.Int64() != 3 {
+		t.Errorf("非结构体应该原样输出: %v", v)
+	}
+	if v := Object((*objectUser)(nil)).LogValue(); v.Any() != (*objectUser)(nil) {
+		t.Errorf("nil 指针应该原样输出: %v", v)
+	}
+}
+
+func TestObject_Encrypt(t *testing.T) {
+	c, _ := NewAESGCM(bytes.Repeat([]byte("k"), 32))
+	var buf bytes.Buffer
+	h, _ := NewEncryptHandler(New(&buf, &Options{TimeFormat: "-"}), &EncryptOptions{Encrypter: c, Keys: []string{"patient"}})
+	slog.New(h).Info("就诊", "patient", Object(objectAddress{City: "杭州"}))
+
+	ct, ok := strings.CutPrefix(strings.TrimSuffix(buf.String(), "\n"), "- INFO msg=就诊 patient=")
+	if !ok || strings.Contains(ct, "杭州") {
+		t.Fatalf("Object 值应该整体加密: %q", buf.String())
+	}
+	if pt, err := c.Decrypt(ct); err != nil || string(pt) != `{"city":"杭州"}` {
+		t.Errorf("解密错误: %q %v", pt, err)
+	}
+}
@@ -0,0 +1,73 @@
+package slogplus
+
+import (
+	"log/slog"
+	"reflect"
+)
+
+// Option 是 New 的配置项，*Options 以及 WithLevel 等函数的返回值都实现了 Option，
+// 多个配置项按顺序应用，新增配置时不影响已有的调用方
+//
+//	h := slogplus.New(os.Stdout,
+//		slogplus.WithLevel(slog.LevelDebug),
+//		slogplus.WithFormat(slogplus.FormatJSON),
+//		slogplus.WithSource(),
+//	)
+type Option interface {
+	apply(*Options)
+}
+
+// apply 实现 Option，将 o 中的非零字段合并到之前的配置，nil 不做任何修改
+// 与函数式配置项可以按任意顺序混用，后面的配置项覆盖前面设置的同一字段，之前设置的其它字段保持不变
+func (o *Options) apply(dst *Options) {
+	if o == nil {
+		return
+	}
+	src, d := reflect.ValueOf(o).Elem(), reflect.ValueOf(dst).Elem()
+	for i := 0; i < src.NumField(); i++ {
+		if f := src.Field(i); !f.IsZero() {
+			d.Field(i).Set(f)
+		}
+	}
+}
+
+// applyOptions 按顺序应用配置项，nil 被忽略
+func applyOptions(opts []Option) Options {
+	var o Options
+	for _, opt := range opts {
+		if opt != nil {
+			opt.apply(&o)
+		}
+	}
+	return o
+}
+
+// optionFunc 将函数适配为 Option
+type optionFunc func(*Options)
+
+func (f optionFunc) apply(o *Options) { f(o) }
+
+// WithLevel 设置最低日志级别，见 Options.Level
+func WithLevel(level slog.Leveler) Option {
+	return optionFunc(func(o *Options) { o.Level = level })
+}
+
+// WithFormat 设置输出格式，见 Options.Format
+func WithFormat(f Format) Option {
+	return optionFunc(func(o *Options) { o.Format = f })
+}
+
+// WithTimeFormat 设置时间格式，见 Options.TimeFormat
+func WithTimeFormat(layout string) Option {
+	return optionFunc(func(o *Options) { o.TimeFormat = layout })
+}
+
+// WithSource 输出源代码位置，见 Options.AddSource
+func WithSource() Option {
+	return optionFunc(func(o *Options) { o.AddSource = true })
+}
+
+// WithReplaceAttr 设置属性替换函数，见 Options.ReplaceAttr
+func WithReplaceAttr(fn func(groups []string, a slog.Attr) slog.Attr) Option {
+	return optionFunc(func(o *Options) { o.ReplaceAttr = fn })
+}
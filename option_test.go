@@ -0,0 +1,54 @@
+package slogplus
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNew_Options(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf,
+		WithLevel(slog.LevelDebug),
+		WithFormat(FormatJSON),
+		WithTimeFormat("15:04"),
+		WithSource(),
+		WithReplaceAttr(func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == "password" {
+				a.Value = slog.StringValue("***")
+			}
+			return a
+		}),
+	)
+	logger.Debug("test", "password", "hunter2")
+
+	out := buf.String()
+	for _, want := range []string{`"level":"DEBUG"`, `"source":`, `"password":"***"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("输出应该包含 %s: %s", want, out)
+		}
+	}
+}
+
+func TestNew_OptionsOrder(t *testing.T) {
+	base := &Options{Level: slog.LevelWarn, Format: FormatJSON}
+	h := New(nil, base, WithLevel(slog.LevelDebug))
+	if h.opts.Level != slog.LevelDebug || h.opts.Format != FormatJSON {
+		t.Errorf("函数式配置项应该覆盖 *Options 中的字段: %+v", h.opts)
+	}
+	if base.Level != slog.LevelWarn {
+		t.Error("不应该修改传入的 *Options")
+	}
+
+	h = New(nil, WithLevel(slog.LevelDebug), WithSource(), WithTimeFormat("15:04"), base)
+	if h.opts.Level != slog.LevelWarn || !h.opts.AddSource || h.opts.TimeFormat != "15:04" || h.opts.Format != FormatJSON {
+		t.Errorf("*Options 应该只覆盖其中的非零字段: %+v", h.opts)
+	}
+
+	var nilOpts *Options
+	h = New(nil, nil, nilOpts)
+	if h.opts.Level != nil || h.opts.TimeFormat == "" {
+		t.Errorf("nil 配置应该使用默认值: %+v", h.opts)
+	}
+}
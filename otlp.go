@@ -0,0 +1,447 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OTLPOptions 定义 OTLP 日志导出的配置
+type OTLPOptions struct {
+	// Endpoint OTLP/HTTP 日志接口地址，默认为 http://localhost:4318/v1/logs
+	// 使用 JSON 编码（application/json），OTel Collector 默认支持
+	Endpoint string
+
+	// Headers 额外的请求头
+	Headers map[string]string
+
+	// Resource 资源属性，例如 service.name、service.version、deployment.environment
+	Resource []slog.Attr
+
+	// ScopeName instrumentation scope 名称，默认为 "github.com/IAmMrChen/slogplus"
+	ScopeName string
+
+	// Level 最低日志级别，默认为 Info
+	Level slog.Leveler
+
+	// BatchSize 每批最多的记录数，默认 512
+	BatchSize int
+
+	// QueueSize 等待发送的最大记录数，队列满时丢弃新记录，默认 2048
+	QueueSize int
+
+	// FlushInterval 发送间隔，默认 5 秒
+	FlushInterval time.Duration
+
+	// Timeout 单次请求的超时时间，默认 10 秒
+	Timeout time.Duration
+
+	// Retry 重试策略，为 nil 时使用默认策略
+	Retry *RetryPolicy
+
+	// Security TLS、认证和代理配置
+	Security *SinkSecurity
+
+	// Codec 请求体的压缩算法，例如 Gzip，默认不压缩
+	Codec Codec
+
+	// Trace 从 context 中提取追踪信息（十六进制的 trace id 和 span id）
+	Trace func(ctx context.Context) (traceID, spanID string)
+
+	// Baggage 设置后将 baggage 条目复制到日志属性中
+	Baggage *Baggage
+
+	// OnError 发送失败（重试耗尽）时的回调
+	OnError func(err error)
+
+	// Stats 设置后报告发送队列深度和发送延迟，名称为 "otlp"
+	Stats *PipelineStats
+}
+
+// OTLPHandler 将记录转换为 OpenTelemetry 日志并通过 OTLP/HTTP 批量导出
+type OTLPHandler struct {
+	e      *otlpExporter
+	prefix string // WithGroup 产生的键前缀
+	attrs  []byte // WithAttrs 预编码的属性，以逗号开头
+}
+
+// otlpExporter 是所有派生 Handler 共享的导出器
+type otlpExporter struct {
+	opts     OTLPOptions
+	client   *http.Client
+	resource []byte
+
+	queue   chan []byte
+	flushCh chan chan error
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+
+	dropped    atomic.Int64
+	lastErr    error
+	unregister func()
+}
+
+// ErrOTLPQueueFull 表示导出队列已满，记录被丢弃
+var ErrOTLPQueueFull = errors.New("slogplus: OTLP 导出队列已满")
+
+// NewOTLPHandler 创建 OTLP 日志导出 Handler，使用完毕后需要调用 Close
+func NewOTLPHandler(opts *OTLPOptions) (*OTLPHandler, error) {
+	e := &otlpExporter{}
+	if opts != nil {
+		e.opts = *opts
+	}
+	o := &e.opts
+	if o.Endpoint == "" {
+		o.Endpoint = "http://localhost:4318/v1/logs"
+	}
+	if o.ScopeName == "" {
+		o.ScopeName = "github.com/IAmMrChen/slogplus"
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 512
+	}
+	if o.QueueSize <= 0 {
+		o.QueueSize = 2048
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = 5 * time.Second
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 10 * time.Second
+	}
+	if o.Retry == nil {
+		o.Retry = &RetryPolicy{}
+	}
+
+	client, err := o.Security.HTTPClient(o.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	e.client = client
+
+	var res []byte
+	for _, a := range o.Resource {
+		res = appendOTLPAttr(res, "", a)
+	}
+	if len(res) > 0 {
+		res = res[1:]
+	}
+	e.resource = res
+
+	e.queue = make(chan []byte, o.QueueSize)
+	e.flushCh = make(chan chan error)
+	e.stop = make(chan struct{})
+	e.done = make(chan struct{})
+	e.unregister = RegisterFlusher(e)
+	go e.loop()
+
+	return &OTLPHandler{e: e}, nil
+}
+
+// Enabled 实现 slog.Handler
+func (h *OTLPHandler) Enabled(_ context.Context, level slog.Level) bool {
+	min := slog.LevelInfo
+	if h.e.opts.Level != nil {
+		min = h.e.opts.Level.Level()
+	}
+	return level >= min
+}
+
+// Handle 将记录编码为 OTLP LogRecord 并放入发送队列，不会阻塞
+func (h *OTLPHandler) Handle(ctx context.Context, r slog.Record) error {
+	r = withCtxAttrs(ctx, r)
+	r = h.e.opts.Baggage.withBaggage(ctx, r)
+	buf := make([]byte, 0, 256)
+	buf = append(buf, '{')
+	if !r.Time.IsZero() {
+		buf = append(buf, `"timeUnixNano":"`...)
+		buf = strconv.AppendInt(buf, r.Time.UnixNano(), 10)
+		buf = append(buf, `",`...)
+	}
+	buf = append(buf, `"observedTimeUnixNano":"`...)
+	buf = strconv.AppendInt(buf, time.Now().UnixNano(), 10)
+	buf = append(buf, `","severityNumber":`...)
+	buf = strconv.AppendInt(buf, int64(otlpSeverity(r.Level)), 10)
+	buf = append(buf, `,"severityText":`...)
+	buf = appendJSONString(buf, levelName(r.Level))
+	buf = append(buf, `,"body":{"stringValue":`...)
+	buf = appendJSONString(buf, r.Message)
+	buf = append(buf, '}')
+
+	if h.e.opts.Trace != nil && ctx != nil {
+		if traceID, spanID := h.e.opts.Trace(ctx); traceID != "" {
+			buf = append(buf, `,"traceId":`...)
+			buf = appendJSONString(buf, traceID)
+			if spanID != "" {
+				buf = append(buf, `,"spanId":`...)
+				buf = appendJSONString(buf, spanID)
+			}
+		}
+	}
+
+	var attrs []byte
+	attrs = append(attrs, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = appendOTLPAttr(attrs, h.prefix, a)
+		return true
+	})
+	if len(attrs) > 0 {
+		buf = append(buf, `,"attributes":[`...)
+		buf = append(buf, attrs[1:]...)
+		buf = append(buf, ']')
+	}
+	buf = append(buf, '}')
+
+	select {
+	case h.e.queue <- buf:
+		h.e.opts.Stats.QueueDepth("otlp", len(h.e.queue))
+		return nil
+	default:
+		h.e.dropped.Add(1)
+		return ErrOTLPQueueFull
+	}
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *OTLPHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	nh := *h
+	nh.attrs = append([]byte(nil), h.attrs...)
+	for _, a := range attrs {
+		nh.attrs = appendOTLPAttr(nh.attrs, h.prefix, a)
+	}
+	return &nh
+}
+
+// WithGroup 实现 slog.Handler，分组属性的键为 group.key
+func (h *OTLPHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	nh := *h
+	nh.prefix = h.prefix + name + "."
+	return &nh
+}
+
+// Flush 立即发送队列中的所有记录
+func (h *OTLPHandler) Flush() error {
+	return h.e.Flush()
+}
+
+// Dropped 返回因队列已满而丢弃的记录数
+func (h *OTLPHandler) Dropped() int64 {
+	return h.e.dropped.Load()
+}
+
+// Close 发送剩余记录并停止导出
+func (h *OTLPHandler) Close() error {
+	var err error
+	h.e.once.Do(func() {
+		h.e.unregister()
+		close(h.e.stop)
+		<-h.e.done
+		err = h.e.lastErr
+	})
+	return err
+}
+
+// Flush 实现 Flusher
+func (e *otlpExporter) Flush() error {
+	ch := make(chan error, 1)
+	select {
+	case e.flushCh <- ch:
+		return <-ch
+	case <-e.done:
+		return nil
+	}
+}
+
+func (e *otlpExporter) loop() {
+	defer close(e.done)
+	ticker := time.NewTicker(e.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, e.opts.BatchSize)
+	send := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		start := time.Now()
+		err := e.export(batch)
+		e.opts.Stats.Latency("otlp", time.Since(start))
+		batch = batch[:0]
+		if err != nil && e.opts.OnError != nil {
+			e.opts.OnError(err)
+		}
+		return err
+	}
+	drain := func() error {
+		var errs []error
+		for {
+			select {
+			case rec := <-e.queue:
+				batch = append(batch, rec)
+				if len(batch) >= e.opts.BatchSize {
+					errs = append(errs, send())
+				}
+			default:
+				errs = append(errs, send())
+				return errors.Join(errs...)
+			}
+		}
+	}
+
+	for {
+		select {
+		case rec := <-e.queue:
+			batch = append(batch, rec)
+			if len(batch) >= e.opts.BatchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case ch := <-e.flushCh:
+			ch <- drain()
+		case <-e.stop:
+			e.lastErr = drain()
+			return
+		}
+	}
+}
+
+// export 发送一批记录
+func (e *otlpExporter) export(batch [][]byte) error {
+	var body bytes.Buffer
+	body.WriteString(`{"resourceLogs":[{"resource":{"attributes":[`)
+	body.Write(e.resource)
+	body.WriteString(`]},"scopeLogs":[{"scope":{"name":`)
+	body.Write(appendJSONString(nil, e.opts.ScopeName))
+	body.WriteString(`},"logRecords":[`)
+	for i, rec := range batch {
+		if i > 0 {
+			body.WriteByte(',')
+		}
+		body.Write(rec)
+	}
+	body.WriteString(`]}]}]}`)
+	payload, encoding, err := compress(e.opts.Codec, body.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return e.opts.Retry.Do(context.Background(), func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.opts.Endpoint, bytes.NewReader(payload))
+		if err != nil {
+			return Permanent(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if encoding != "" {
+			req.Header.Set("Content-Encoding", encoding)
+		}
+		for k, v := range e.opts.Headers {
+			req.Header.Set(k, v)
+		}
+		e.opts.Security.Authorize(req)
+
+		resp, err := e.client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return &StatusError{Code: resp.StatusCode, Status: resp.Status}
+		}
+		return nil
+	})
+}
+
+// otlpSeverity 将 slog 级别映射为 OpenTelemetry SeverityNumber
+// DEBUG=5，INFO=9，WARN=13，ERROR=17，范围为 1~24
+func otlpSeverity(level slog.Level) int {
+	n := int(level) + 9
+	return max(1, min(24, n))
+}
+
+// appendOTLPAttr 追加 ,{"key":...,"value":{...}}，分组展开为 group.key
+func appendOTLPAttr(buf []byte, prefix string, a slog.Attr) []byte {
+	v := a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return buf
+	}
+	if v.Kind() == slog.KindGroup {
+		p := prefix
+		if a.Key != "" {
+			p += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			buf = appendOTLPAttr(buf, p, ga)
+		}
+		return buf
+	}
+	buf = append(buf, `,{"key":"`...)
+	buf = appendJSONEscaped(buf, prefix)
+	buf = appendJSONEscaped(buf, a.Key)
+	buf = append(buf, `","value":`...)
+	buf = appendOTLPValue(buf, v)
+	return append(buf, '}')
+}
+
+// appendOTLPValue 按 OTLP JSON 编码追加 AnyValue
+func appendOTLPValue(buf []byte, v slog.Value) []byte {
+	switch v.Kind() {
+	case slog.KindString:
+		buf = append(buf, `{"stringValue":`...)
+		buf = appendJSONString(buf, v.String())
+	case slog.KindInt64:
+		buf = append(buf, `{"intValue":"`...)
+		buf = strconv.AppendInt(buf, v.Int64(), 10)
+		buf = append(buf, '"')
+	case slog.KindUint64:
+		if u := v.Uint64(); u <= math.MaxInt64 {
+			buf = append(buf, `{"intValue":"`...)
+			buf = strconv.AppendUint(buf, u, 10)
+			buf = append(buf, '"')
+		} else {
+			buf = append(buf, `{"stringValue":"`...)
+			buf = strconv.AppendUint(buf, u, 10)
+			buf = append(buf, '"')
+		}
+	case slog.KindFloat64:
+		f := v.Float64()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			buf = append(buf, `{"stringValue":`...)
+			buf = appendJSONString(buf, strconv.FormatFloat(f, 'g', -1, 64))
+		} else {
+			buf = append(buf, `{"doubleValue":`...)
+			buf = strconv.AppendFloat(buf, f, 'g', -1, 64)
+		}
+	case slog.KindBool:
+		buf = append(buf, `{"boolValue":`...)
+		buf = strconv.AppendBool(buf, v.Bool())
+	case slog.KindTime:
+		buf = append(buf, `{"stringValue":"`...)
+		buf = v.Time().AppendFormat(buf, time.RFC3339Nano)
+		buf = append(buf, '"')
+	default:
+		if isNilValue(v) {
+			// 空的 AnyValue 表示 null
+			return append(buf, '{', '}')
+		}
+		buf = append(buf, `{"stringValue":`...)
+		buf = appendJSONString(buf, fmt.Sprint(v.Any()))
+	}
+	return append(buf, '}')
+}
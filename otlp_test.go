@@ -0,0 +1,125 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/IAmMrChen/slogplus/sinktest"
+)
+
+func TestOTLPHandler_Export(t *testing.T) {
+	c := sinktest.NewHTTPCollector(t)
+	c.FailNext(1, http.StatusServiceUnavailable)
+
+	h, err := NewOTLPHandler(&OTLPOptions{
+		Endpoint:      c.URL,
+		Resource:      []slog.Attr{slog.String("service.name", "api")},
+		FlushInterval: time.Hour,
+		Retry:         &RetryPolicy{InitialBackoff: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	logger := slog.New(h).With("tenant", "acme").WithGroup("req")
+	logger.Warn("slow", "ms", 120)
+	logger.Debug("ignored")
+
+	if err := h.Flush(); err != nil {
+		t.Fatalf("重试后应该发送成功: %v", err)
+	}
+
+	batches := c.Batches()
+	if len(batches) != 1 || c.Requests() != 2 {
+		t.Fatalf("应该重试后发送一批记录: %d", len(batches))
+	}
+	body := batches[0].Body
+
+	var req struct {
+		ResourceLogs []struct {
+			Resource struct {
+				Attributes []struct {
+					Key   string
+					Value map[string]any
+				}
+			}
+			ScopeLogs []struct {
+				LogRecords []struct {
+					SeverityNumber int
+					SeverityText   string
+					Body           map[string]any
+					Attributes     []struct {
+						Key   string
+						Value map[string]any
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.Fatalf("请求体应该是合法的 JSON: %v\n%s", err, body)
+	}
+	rl := req.ResourceLogs[0]
+	if rl.Resource.Attributes[0].Key != "service.name" || rl.Resource.Attributes[0].Value["stringValue"] != "api" {
+		t.Errorf("资源属性不正确: %+v", rl.Resource)
+	}
+	recs := rl.ScopeLogs[0].LogRecords
+	if len(recs) != 1 {
+		t.Fatalf("应该只导出启用级别的记录: %d", len(recs))
+	}
+	rec := recs[0]
+	if rec.SeverityNumber != 13 || rec.SeverityText != "WARN" || rec.Body["stringValue"] != "slow" {
+		t.Errorf("记录字段不正确: %+v", rec)
+	}
+	if len(rec.Attributes) != 2 || rec.Attributes[0].Key != "tenant" ||
+		rec.Attributes[1].Key != "req.ms" || rec.Attributes[1].Value["intValue"] != "120" {
+		t.Errorf("属性不正确: %+v", rec.Attributes)
+	}
+}
+
+func TestOTLPHandler_Stats(t *testing.T) {
+	c := sinktest.NewHTTPCollector(t)
+	stats := &PipelineStats{}
+	h, err := NewOTLPHandler(&OTLPOptions{Endpoint: c.URL, Stats: stats})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	slog.New(h).Info("a")
+	slog.New(h).Info("b")
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	snap := stats.Snapshot()
+	if snap.QueueHigh["otlp"] < 1 || snap.LatencyCount["otlp"] != 1 || snap.LatencyP99["otlp"] <= 0 {
+		t.Errorf("应该报告队列深度和发送延迟: %+v", snap)
+	}
+}
+
+func TestOTLPHandler_Codec(t *testing.T) {
+	c := sinktest.NewHTTPCollector(t)
+	h, err := NewOTLPHandler(&OTLPOptions{Endpoint: c.URL, Codec: Gzip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slog.New(h).Info("gzipped")
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b := c.Batches()[0]
+	if b.Header.Get("Content-Encoding") != "gzip" {
+		t.Errorf("应该设置 Content-Encoding: %v", b.Header)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(b.Body, &body); err != nil {
+		t.Errorf("解压后应该是 JSON: %v", err)
+	}
+}
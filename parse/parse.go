@@ -0,0 +1,377 @@
+// Package parse 解析 slogplus 输出的日志，还原为结构化记录
+//
+// 支持文本格式、控制台格式（自动去掉颜色和超链接控制码）、logfmt、
+// JSON（包括标准库 JSONHandler 以及 ECS、GCP 等常见字段名）和 MessagePack，
+// 可用于回放抓取的日志、离线分析或测试
+package parse
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IAmMrChen/slogplus"
+)
+
+// Record 是解析出的一条日志记录
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   []slog.Attr
+}
+
+// SlogRecord 转换为 slog.Record，用于交给 Handler 处理
+func (r Record) SlogRecord() slog.Record {
+	sr := slog.NewRecord(r.Time, r.Level, r.Message, 0)
+	sr.AddAttrs(r.Attrs...)
+	return sr
+}
+
+// Reader 逐条读取日志记录
+type Reader struct {
+	lines *bufio.Scanner
+	mp    *slogplus.MsgpackDecoder
+	line  int
+}
+
+// NewReader 创建按行读取的 Reader，每行自动识别为 JSON、logfmt 或文本格式
+func NewReader(r io.Reader) *Reader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	return &Reader{lines: s}
+}
+
+// NewMsgpackReader 创建读取 FormatMsgpack 输出的 Reader
+func NewMsgpackReader(r io.Reader) *Reader {
+	return &Reader{mp: slogplus.NewMsgpackDecoder(r)}
+}
+
+// Next 返回下一条记录，没有更多记录时返回 io.EOF，空行会被跳过
+func (r *Reader) Next() (Record, error) {
+	if r.mp != nil {
+		m, err := r.mp.Decode()
+		if err != nil {
+			return Record{}, err
+		}
+		return fromMap(m), nil
+	}
+
+	for r.lines.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.lines.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		rec, err := ParseLine(line)
+		if err != nil {
+			return Record{}, fmt.Errorf("parse: 第 %d 行: %w", r.line, err)
+		}
+		return rec, nil
+	}
+	if err := r.lines.Err(); err != nil {
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
+
+// ParseLine 解析一行日志，以 '{' 开头的按 JSON 解析，否则按文本或 logfmt 解析
+func ParseLine(line []byte) (Record, error) {
+	line = bytes.TrimSpace(line)
+	if len(line) > 0 && line[0] == '{' {
+		return parseJSON(line)
+	}
+	return parseText(string(stripControl(line)))
+}
+
+// 常见的时间、级别和消息字段名
+var (
+	timeKeys    = []string{"time", "@timestamp", "timestamp", "ts"}
+	levelKeys   = []string{"level", "log.level", "severity"}
+	messageKeys = []string{"msg", "message", "short_message"}
+)
+
+// timeLayouts 文本中时间的常见格式
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006/01/02 15:04:05",
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05",
+	time.Stamp,
+}
+
+// parseTime 按常见格式解析时间
+func parseTime(s string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseLevel 解析级别名称，例如 INFO、WARN+2、warning
+func parseLevel(s string) (slog.Level, bool) {
+	s = strings.TrimSpace(s)
+	switch strings.ToUpper(s) {
+	case "WARNING":
+		return slog.LevelWarn, true
+	case "CRITICAL", "FATAL", "ALERT", "EMERGENCY":
+		return slog.LevelError + 4, true
+	case "PANIC":
+		return slog.LevelError + 8, true
+	}
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(s)); err != nil {
+		return 0, false
+	}
+	return l, true
+}
+
+// fromMap 将 MessagePack 解码出的 map 转换为记录
+func fromMap(m map[string]any) Record {
+	var r Record
+	if t, ok := m["time"].(time.Time); ok {
+		r.Time = t
+	}
+	if s, ok := m["level"].(string); ok {
+		r.Level, _ = parseLevel(s)
+	}
+	r.Message, _ = m["msg"].(string)
+	for _, k := range sortedKeys(m) {
+		switch k {
+		case "time", "level", "msg":
+			continue
+		}
+		r.Attrs = append(r.Attrs, anyAttr(k, m[k]))
+	}
+	return r
+}
+
+// anyAttr 将解码出的值转换为属性，map 转换为分组
+func anyAttr(key string, v any) slog.Attr {
+	switch x := v.(type) {
+	case map[string]any:
+		attrs := make([]any, 0, len(x))
+		for _, k := range sortedKeys(x) {
+			attrs = append(attrs, anyAttr(k, x[k]))
+		}
+		return slog.Group(key, attrs...)
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return slog.Int64(key, i)
+		}
+		f, _ := x.Float64()
+		return slog.Float64(key, f)
+	}
+	return slog.Any(key, v)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// parseJSON 按顺序解析 JSON 对象，保持属性的原始顺序
+func parseJSON(line []byte) (Record, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return Record{}, errors.New("不是 JSON 对象")
+	}
+
+	var r Record
+	var haveTime, haveLevel, haveMsg bool
+	for dec.More() {
+		kt, err := dec.Token()
+		if err != nil {
+			return Record{}, err
+		}
+		key := kt.(string)
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return Record{}, err
+		}
+
+		s, isString := v.(string)
+		switch {
+		case !haveTime && isString && slices.Contains(timeKeys, key):
+			if t, ok := parseTime(s); ok {
+				r.Time, haveTime = t, true
+				continue
+			}
+		case !haveLevel && isString && slices.Contains(levelKeys, key):
+			if l, ok := parseLevel(s); ok {
+				r.Level, haveLevel = l, true
+				continue
+			}
+		case !haveMsg && isString && slices.Contains(messageKeys, key):
+			r.Message, haveMsg = s, true
+			continue
+		}
+		r.Attrs = append(r.Attrs, anyAttr(key, v))
+	}
+	return r, nil
+}
+
+// parseText 解析文本、控制台和 logfmt 格式
+// 第一个 key=value 之前的内容为时间和级别，未加引号的值延续到下一个 " key=" 为止，
+// 因此文本格式中包含空格的消息也能正确还原
+func parseText(line string) (Record, error) {
+	var r Record
+	start := nextKey(line, 0)
+	if start < 0 {
+		return r, errors.New("找不到 key=value")
+	}
+
+	// 文本格式的前缀: [时间] 级别
+	if fields := strings.Fields(line[:start]); len(fields) > 0 {
+		level, ok := parseLevel(fields[len(fields)-1])
+		if !ok {
+			return r, fmt.Errorf("无效的级别 %q", fields[len(fields)-1])
+		}
+		r.Level = level
+		if len(fields) > 1 {
+			r.Time, _ = parseTime(strings.Join(fields[:len(fields)-1], " "))
+		}
+	}
+
+	for i := start; i < len(line); {
+		eq := strings.IndexByte(line[i:], '=')
+		key := line[i : i+eq]
+		i += eq + 1
+
+		var value string
+		if i < len(line) && line[i] == '"' {
+			end := quotedEnd(line, i)
+			v, err := strconv.Unquote(line[i:end])
+			if err != nil {
+				return r, fmt.Errorf("%s 的值无效: %w", key, err)
+			}
+			value = v
+			i = end
+		} else {
+			end := nextKey(line, i)
+			if end < 0 {
+				end = len(line)
+			}
+			value = strings.TrimRight(line[i:end], " ")
+			i = end
+		}
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+
+		switch key {
+		case "time":
+			if t, ok := parseTime(value); ok {
+				r.Time = t
+				continue
+			}
+		case "level":
+			if l, ok := parseLevel(value); ok {
+				r.Level = l
+				continue
+			}
+		case "msg":
+			r.Message = value
+			continue
+		}
+		r.Attrs = append(r.Attrs, textAttr(key, value))
+	}
+	return r, nil
+}
+
+// nextKey 返回 from 之后下一个 key= 的位置，key 必须位于行首或空格之后
+func nextKey(line string, from int) int {
+	for i := from; i < len(line); i++ {
+		if i > 0 && line[i-1] != ' ' {
+			continue
+		}
+		j := i
+		for j < len(line) && isKeyChar(line[j]) {
+			j++
+		}
+		if j > i && j < len(line) && line[j] == '=' {
+			return i
+		}
+	}
+	return -1
+}
+
+func isKeyChar(c byte) bool {
+	return c > ' ' && c != '=' && c != '"' && c != 0x7f
+}
+
+// quotedEnd 返回从 i 开始的带引号字符串的结束位置（不含）
+func quotedEnd(line string, i int) int {
+	for j := i + 1; j < len(line); j++ {
+		switch line[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1
+		}
+	}
+	return len(line)
+}
+
+// textAttr 将文本值转换为属性，尽量还原整数、浮点数和布尔值
+func textAttr(key, value string) slog.Attr {
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return slog.Int64(key, i)
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil && strings.ContainsAny(value, ".eE") {
+		return slog.Float64(key, f)
+	}
+	if b, err := strconv.ParseBool(value); err == nil && (value == "true" || value == "false") {
+		return slog.Bool(key, b)
+	}
+	return slog.String(key, value)
+}
+
+// stripControl 去掉 ANSI 颜色和 OSC 8 超链接控制码
+func stripControl(line []byte) []byte {
+	if bytes.IndexByte(line, 0x1b) < 0 {
+		return line
+	}
+	out := make([]byte, 0, len(line))
+	for i := 0; i < len(line); i++ {
+		if line[i] != 0x1b || i+1 >= len(line) {
+			out = append(out, line[i])
+			continue
+		}
+		switch line[i+1] {
+		case '[': // CSI，以字母结束
+			j := i + 2
+			for j < len(line) && (line[j] < '@' || line[j] > '~') {
+				j++
+			}
+			i = j
+		case ']': // OSC，以 ESC \ 或 BEL 结束
+			j := i + 2
+			for j < len(line) && line[j] != 0x07 && !(line[j] == 0x1b && j+1 < len(line) && line[j+1] == '\\') {
+				j++
+			}
+			if j < len(line) && line[j] == 0x1b {
+				j++
+			}
+			i = j
+		default:
+			out = append(out, line[i])
+		}
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package parse
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/IAmMrChen/slogplus"
+)
+
+func TestReader_Formats(t *testing.T) {
+	tests := []struct {
+		name string
+		opts slogplus.Options
+	}{
+		{"text", slogplus.Options{}},
+		{"logfmt", slogplus.Options{Logfmt: true}},
+		{"json", slogplus.Options{Format: slogplus.FormatJSON}},
+		{"msgpack", slogplus.Options{Format: slogplus.FormatMsgpack}},
+		{"stdlib json", slogplus.Options{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slogplus.NewLogger(&buf, &tt.opts)
+			if tt.name == "stdlib json" {
+				logger = slog.New(slog.NewJSONHandler(&buf, nil))
+			}
+			logger.Warn("user logged in", "user_id", 42, "ok", true, "path", "/a b", "ratio", 0.5)
+
+			r := NewReader(&buf)
+			if tt.opts.Format == slogplus.FormatMsgpack {
+				r = NewMsgpackReader(&buf)
+			}
+			rec, err := r.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rec.Level != slog.LevelWarn || rec.Message != "user logged in" {
+				t.Errorf("级别或消息不正确: %v %q", rec.Level, rec.Message)
+			}
+			if rec.Time.IsZero() || time.Since(rec.Time) > time.Minute {
+				t.Errorf("时间不正确: %v", rec.Time)
+			}
+			want := []slog.Attr{
+				slog.Int64("user_id", 42),
+				slog.Bool("ok", true),
+				slog.String("path", "/a b"),
+				slog.Float64("ratio", 0.5),
+			}
+			got := map[string]slog.Value{}
+			for _, a := range rec.Attrs {
+				got[a.Key] = a.Value
+			}
+			for _, a := range want {
+				if v, ok := got[a.Key]; !ok || !v.Equal(a.Value) {
+					t.Errorf("%s = %v, 期望 %v", a.Key, v, a.Value)
+				}
+			}
+			if _, err := r.Next(); err != io.EOF {
+				t.Errorf("没有更多记录时应该返回 io.EOF: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseLine_Console(t *testing.T) {
+	line := "\x1b[2m2025/11/14 14:03:14\x1b[0m \x1b[33mWARN \x1b[0m source=\x1b]8;;file:///a.go\x1b\\a.go:1\x1b]8;;\x1b\\ msg=disk almost full used=91"
+	rec, err := ParseLine([]byte(line))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Level != slog.LevelWarn || rec.Message != "disk almost full" {
+		t.Errorf("级别或消息不正确: %v %q", rec.Level, rec.Message)
+	}
+	if rec.Time.Format("2006/01/02 15:04:05") != "2025/11/14 14:03:14" {
+		t.Errorf("时间不正确: %v", rec.Time)
+	}
+	if len(rec.Attrs) != 2 || rec.Attrs[0].String() != "source=a.go:1" || rec.Attrs[1].String() != "used=91" {
+		t.Errorf("属性不正确: %v", rec.Attrs)
+	}
+}
+
+func TestParseLine_Errors(t *testing.T) {
+	for _, line := range []string{"no pairs here", "LOUD msg=x", `{"msg":`} {
+		if _, err := ParseLine([]byte(line)); err == nil {
+			t.Errorf("%q 应该解析失败", line)
+		}
+	}
+}
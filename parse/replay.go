@@ -0,0 +1,87 @@
+package parse
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// ReplayOptions 定义回放的配置
+type ReplayOptions struct {
+	// Speed 回放速度倍数，1 表示按原始时间间隔回放，10 表示加速 10 倍，
+	// 0 表示不等待、尽快回放
+	Speed float64
+
+	// Now 是否将记录时间改写为回放时的当前时间，便于测试按时间窗口计算的仪表盘和告警规则
+	Now bool
+
+	// Filter 设置后只回放返回 true 的记录
+	Filter func(r Record) bool
+}
+
+// Replay 读取 src 中的记录并交给 h 处理，返回回放的记录数
+// 已启用的级别才会交给 h，ctx 取消时停止回放并返回 ctx.Err()
+func Replay(ctx context.Context, src *Reader, h slog.Handler, opts *ReplayOptions) (int, error) {
+	var o ReplayOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	var first time.Time // 第一条记录的原始时间
+	var started time.Time
+	n := 0
+	for {
+		rec, err := src.Next()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		if o.Filter != nil && !o.Filter(rec) {
+			continue
+		}
+
+		if o.Speed > 0 && !rec.Time.IsZero() {
+			if first.IsZero() {
+				first, started = rec.Time, time.Now()
+			} else {
+				due := started.Add(time.Duration(float64(rec.Time.Sub(first)) / o.Speed))
+				if err := sleepUntil(ctx, due); err != nil {
+					return n, err
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+
+		if o.Now {
+			rec.Time = time.Now()
+		}
+		if !h.Enabled(ctx, rec.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, rec.SlogRecord()); err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+// sleepUntil 等待到 t，ctx 取消时提前返回
+func sleepUntil(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
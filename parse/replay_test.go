@@ -0,0 +1,55 @@
+package parse
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IAmMrChen/slogplus"
+)
+
+func TestReplay(t *testing.T) {
+	src := strings.Join([]string{
+		"2025/11/14 14:00:00 INFO msg=start",
+		"2025/11/14 14:00:00 DEBUG msg=filtered by level",
+		"2025/11/14 14:00:01 WARN msg=slow took=1.5",
+		"2025/11/14 14:00:02 ERROR msg=failed",
+	}, "\n")
+
+	var out bytes.Buffer
+	h := slogplus.New(&out, &slogplus.Options{Format: slogplus.FormatJSON})
+
+	begin := time.Now()
+	n, err := Replay(context.Background(), NewReader(strings.NewReader(src)), h, &ReplayOptions{Speed: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("应该回放 3 条记录，实际 %d", n)
+	}
+	if d := time.Since(begin); d < 15*time.Millisecond {
+		t.Errorf("加速 100 倍时 2 秒的记录应该耗时约 20ms，实际 %v", d)
+	}
+	if !strings.Contains(out.String(), `"time":"2025-11-14T14:00:01`) || !strings.Contains(out.String(), `"took":1.5`) {
+		t.Errorf("应该保留原始时间和属性: %s", out.String())
+	}
+}
+
+func TestReplay_NowAndCancel(t *testing.T) {
+	src := "2020/01/01 00:00:00 INFO msg=a\n2020/01/01 01:00:00 INFO msg=b\n"
+
+	var out bytes.Buffer
+	h := slogplus.New(&out, &slogplus.Options{Format: slogplus.FormatJSON})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	n, err := Replay(ctx, NewReader(strings.NewReader(src)), h, &ReplayOptions{Speed: 1, Now: true})
+	if err != context.DeadlineExceeded || n != 1 {
+		t.Errorf("ctx 超时应该停止回放: n=%d err=%v", n, err)
+	}
+	if strings.Contains(out.String(), "2020-01-01") {
+		t.Errorf("Now 应该改写记录时间: %s", out.String())
+	}
+}
@@ -0,0 +1,115 @@
+package slogplus
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PIIKind 表示一类个人信息
+type PIIKind uint8
+
+const (
+	// PIIEmail 电子邮件地址，保留首字符和域名，例如 a****@example.com
+	PIIEmail PIIKind = 1 << iota
+	// PIICard 通过 Luhn 校验的 13~19 位银行卡号（连续数字或 4-4-4-4、4-6-5 分组），保留末尾数字，例如 ****1234
+	PIICard
+	// PIIPhone 10~15 位电话号码，保留末尾数字，例如 ****5678
+	// 只匹配以 + 开头、区号带括号或分隔符的号码以及 11 位手机号，时间戳、订单号等连续数字不会被掩码
+	PIIPhone
+
+	// PIIAll 全部内置检测
+	PIIAll = PIIEmail | PIICard | PIIPhone
+)
+
+// PII 定义个人信息的自动检测和部分掩码，设置 Options.PII 后对消息、字符串属性值以及 error 和 fmt.Stringer 值的文本生效
+// 与 Masks 不同，匹配的内容保留少量字符，排查问题时仍然可以区分不同的用户
+type PII struct {
+	// Kinds 启用的检测，默认为 PIIAll
+	Kinds PIIKind
+
+	// Keep 银行卡号和电话号码保留的末尾数字个数，默认为 4，小于 0 时全部掩码
+	Keep int
+}
+
+var (
+	piiEmailRe = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	piiCardRe  = regexp.MustCompile(`\b\d{4}[ \-]?\d{4,6}[ \-]?\d{4,5}(?:[ \-]?\d{1,4})?\b`)
+	piiPhoneRe = regexp.MustCompile(`\+\d{1,3}[ \-]?(?:\(?\d{1,4}\)?[ .\-]?)?\d{3,4}[ .\-]?\d{4}\b|(?:\(\d{2,4}\)[ .\-]?|\b\d{2,4}[ .\-])\d{3,4}[ .\-]?\d{4}\b|\b1[3-9]\d{9}\b`)
+)
+
+// scrub 掩码 s 中的个人信息，银行卡号先于电话号码检测
+func (p *PII) scrub(s string) string {
+	kinds := p.Kinds
+	if kinds == 0 {
+		kinds = PIIAll
+	}
+	if kinds&PIIEmail != 0 && strings.IndexByte(s, '@') >= 0 {
+		s = piiEmailRe.ReplaceAllStringFunc(s, func(m string) string {
+			at := strings.LastIndexByte(m, '@')
+			return m[:1] + "****" + m[at:]
+		})
+	}
+	if kinds&(PIICard|PIIPhone) == 0 || !strings.ContainsAny(s, "0123456789") {
+		return s
+	}
+	if kinds&PIICard != 0 {
+		s = piiCardRe.ReplaceAllStringFunc(s, func(m string) string {
+			digits := onlyDigits(m)
+			if len(digits) < 13 || !luhnValid(digits) {
+				return m
+			}
+			return p.maskDigits(digits)
+		})
+	}
+	if kinds&PIIPhone != 0 {
+		s = piiPhoneRe.ReplaceAllStringFunc(s, func(m string) string {
+			digits := onlyDigits(m)
+			if len(digits) < 10 || len(digits) > 15 {
+				return m
+			}
+			return p.maskDigits(digits)
+		})
+	}
+	return s
+}
+
+// maskDigits 只保留末尾 Keep 个数字
+func (p *PII) maskDigits(digits string) string {
+	keep := p.Keep
+	if keep == 0 {
+		keep = 4
+	}
+	if keep < 0 || keep >= len(digits) {
+		return "****"
+	}
+	return "****" + digits[len(digits)-keep:]
+}
+
+// onlyDigits 去掉分隔符，只保留数字
+func onlyDigits(s string) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			b = append(b, s[i])
+		}
+	}
+	return string(b)
+}
+
+// luhnValid 判断数字串是否通过 Luhn 校验
+func luhnValid(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,68 @@
+package slogplus
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestPII(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{TimeFormat: "-", PII: &PII{}})
+	logger.Info("用户 alice@example.com 下单",
+		"card", "4111 1111 1111 1111",
+		"order", "1234567890123", // 未通过 Luhn 校验，也不是电话号码格式
+		"phone", "+86 138-1234-5678",
+		"date", "2024-01-02",
+	)
+
+	want := "- INFO msg=用户 a****@example.com 下单 card=****1111 order=1234567890123 phone=****5678 date=2024-01-02\n"
+	if got := buf.String(); got != want {
+		t.Errorf("输出错误:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestPII_ErrorValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{TimeFormat: "-", PII: &PII{}})
+	logger.Error("发送失败", "err", errors.New("smtp: 550 bob@example.com rejected"))
+	if got, want := buf.String(), "- ERROR msg=发送失败 err=smtp: 550 b****@example.com rejected\n"; got != want {
+		t.Errorf("error 值中的个人信息也应该掩码:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestPII_Phone(t *testing.T) {
+	p := &PII{Kinds: PIIPhone}
+	for in, want := range map[string]string{
+		"tel +1 (555) 123-4567":    "tel ****4567",
+		"+8613812345678":           "****5678",
+		"(010) 8888-1234":          "****1234",
+		"call 555-123-4567 now":    "call ****4567 now",
+		"手机 13812345678":           "手机 ****5678",
+		"ts 1700000000000":         "ts 1700000000000",
+		"ts=1700000000":            "ts=1700000000",
+		"order 202401021234567":    "order 202401021234567",
+		"trace 4bf92f3577b34da6a3": "trace 4bf92f3577b34da6a3",
+		"id 98765432101234":        "id 98765432101234",
+	} {
+		if got := p.scrub(in); got != want {
+			t.Errorf("scrub(%q) = %q, 期望 %q", in, got, want)
+		}
+	}
+}
+
+func TestPII_Kinds(t *testing.T) {
+	p := &PII{Kinds: PIICard, Keep: -1}
+	for in, want := range map[string]string{
+		"卡号 5500-0000-0000-0004": "卡号 ****",
+		"a@b.co 13812345678":     "a@b.co 13812345678",
+		"4111111111111112":       "4111111111111112",
+	} {
+		if got := p.scrub(in); got != want {
+			t.Errorf("scrub(%q) = %q, 期望 %q", in, got, want)
+		}
+	}
+	if !luhnValid("79927398713") || luhnValid("79927398710") {
+		t.Error("Luhn 校验错误")
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+// NewPipeWriter 创建写入命名管道的输出：Unix 下为 mkfifo 创建的 FIFO，
+// Windows 下为 \\.\pipe\<name> 形式的命名管道
+// 与 NetWriter 相同，采集端未启动或退出时记录进入暂存区，后台按退避策略重新打开管道后按顺序补发
+//
+//	w, _ := slogplus.NewPipeWriter("/var/run/vector/app.fifo", nil)
+//	slogplus.Setup(w, &slogplus.Options{Format: slogplus.FormatJSON})
+func NewPipeWriter(path string, opts *NetOptions) (*NetWriter, error) {
+	w := newNetWriter(opts)
+	w.open = func() (writeConn, error) {
+		return openPipe(path)
+	}
+	w.connect()
+	return w, nil
+}
@@ -0,0 +1,9 @@
+//go:build !unix && !windows && !slogplus_slim && !tinygo
+
+package slogplus
+
+import "errors"
+
+func openPipe(string) (writeConn, error) {
+	return nil, errors.New("slogplus: 当前平台不支持命名管道")
+}
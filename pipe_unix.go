@@ -0,0 +1,22 @@
+//go:build unix && !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// openPipe 以非阻塞方式打开 FIFO，没有读取端时立即返回错误而不是阻塞，
+// 非阻塞打开的文件由运行时轮询，支持写入超时
+func openPipe(path string) (writeConn, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if fi.Mode()&os.ModeNamedPipe == 0 {
+		return nil, fmt.Errorf("slogplus: %s 不是命名管道", path)
+	}
+	return os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+}
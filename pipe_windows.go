@@ -0,0 +1,10 @@
+//go:build windows && !slogplus_slim && !tinygo
+
+package slogplus
+
+import "os"
+
+// openPipe 以客户端身份连接命名管道，服务端未创建管道或没有空闲实例时返回错误
+func openPipe(path string) (writeConn, error) {
+	return os.OpenFile(path, os.O_WRONLY, 0)
+}
@@ -0,0 +1,189 @@
+package slogplus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// PackageLevels 按调用方所在的包设置日志级别，可以只为一个子系统开启 DEBUG 而不影响全局，
+// 可以在运行时通过 Set 修改，安全地并发使用；零值表示所有包都为 Info
+//
+//	levels, _ := slogplus.ParsePackageLevels("github.com/me/app/db=debug,default=info")
+//	slog.SetDefault(slog.New(slogplus.NewPackageLevelHandler(base, levels)))
+type PackageLevels struct {
+	v atomic.Pointer[packageLevelConfig]
+}
+
+// packageLevelConfig 是一份不可变的配置，cache 随配置一起替换
+type packageLevelConfig struct {
+	def      slog.Level
+	prefixes []string // 按长度降序，最长的匹配优先
+	levels   map[string]slog.Level
+	min      slog.Level
+	cache    sync.Map // map[uintptr]slog.Level
+}
+
+// NewPackageLevels 创建按包设置的日志级别，levels 的键为包路径前缀，
+// 匹配该包及其所有子包，最长的前缀优先，没有匹配的包使用 def
+func NewPackageLevels(def slog.Level, levels map[string]slog.Level) *PackageLevels {
+	pl := new(PackageLevels)
+	pl.store(def, levels)
+	return pl
+}
+
+// ParsePackageLevels 解析按包设置的日志级别，格式为逗号分隔的 "包路径前缀=级别"，
+// "default=级别" 或单独的级别设置默认级别，例如 "github.com/me/app/db=debug,net/http=warn,default=info"
+func ParsePackageLevels(spec string) (*PackageLevels, error) {
+	pl := new(PackageLevels)
+	if err := pl.Set(spec); err != nil {
+		return nil, err
+	}
+	return pl, nil
+}
+
+// Set 按 ParsePackageLevels 的格式替换当前配置，解析失败时保持原配置
+func (pl *PackageLevels) Set(spec string) error {
+	def := slog.LevelInfo
+	levels := make(map[string]slog.Level)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		prefix, name, found := strings.Cut(part, "=")
+		if !found {
+			prefix, name = "default", part
+		}
+		prefix, name = strings.TrimSpace(prefix), strings.TrimSpace(name)
+		level, err := ParseLevel(name)
+		if err != nil {
+			return fmt.Errorf("slogplus: 无效的包级别配置 %q: %w", part, err)
+		}
+		if prefix == "default" {
+			def = level
+		} else {
+			levels[strings.TrimSuffix(prefix, "/")] = level
+		}
+	}
+	pl.store(def, levels)
+	return nil
+}
+
+func (pl *PackageLevels) store(def slog.Level, levels map[string]slog.Level) {
+	c := &packageLevelConfig{def: def, levels: make(map[string]slog.Level, len(levels)), min: def}
+	for prefix, level := range levels {
+		c.levels[prefix] = level
+		c.prefixes = append(c.prefixes, prefix)
+		c.min = min(c.min, level)
+	}
+	slices.SortFunc(c.prefixes, func(a, b string) int { return len(b) - len(a) })
+	pl.v.Store(c)
+}
+
+func (pl *PackageLevels) config() *packageLevelConfig {
+	if c := pl.v.Load(); c != nil {
+		return c
+	}
+	pl.v.CompareAndSwap(nil, &packageLevelConfig{})
+	return pl.v.Load()
+}
+
+// Level 返回调用位置 pc 所在包的日志级别，pc 为 0 时返回默认级别
+func (pl *PackageLevels) Level(pc uintptr) slog.Level {
+	c := pl.config()
+	if pc == 0 || len(c.prefixes) == 0 {
+		return c.def
+	}
+	if level, ok := c.cache.Load(pc); ok {
+		return level.(slog.Level)
+	}
+	level := c.lookup(funcPackage(pc))
+	c.cache.Store(pc, level)
+	return level
+}
+
+// lookup 返回包的日志级别
+func (c *packageLevelConfig) lookup(pkg string) slog.Level {
+	for _, prefix := range c.prefixes {
+		if pkg == prefix || strings.HasPrefix(pkg, prefix) && pkg[len(prefix)] == '/' {
+			return c.levels[prefix]
+		}
+	}
+	return c.def
+}
+
+// funcPackage 返回 pc 所在函数的包路径
+func funcPackage(pc uintptr) string {
+	return packagePath(callerFrame(pc).Function)
+}
+
+// packagePath 返回函数的包路径
+// 函数名形如 github.com/me/app/db.(*Conn).Query，包路径为最后一个 '/' 之后第一个 '.' 之前的部分
+func packagePath(name string) string {
+	slash := strings.LastIndexByte(name, '/')
+	if i := strings.IndexByte(name[slash+1:], '.'); i >= 0 {
+		return name[:slash+1+i]
+	}
+	return name
+}
+
+// String 实现 fmt.Stringer，返回 ParsePackageLevels 格式的配置
+func (pl *PackageLevels) String() string {
+	c := pl.config()
+	prefixes := slices.Clone(c.prefixes)
+	slices.Sort(prefixes)
+	parts := make([]string, 0, len(prefixes)+1)
+	for _, prefix := range prefixes {
+		parts = append(parts, prefix+"="+levelName(c.levels[prefix]))
+	}
+	return strings.Join(append(parts, "default="+levelName(c.def)), ",")
+}
+
+// MarshalText 实现 encoding.TextMarshaler
+func (pl *PackageLevels) MarshalText() ([]byte, error) {
+	return []byte(pl.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler
+func (pl *PackageLevels) UnmarshalText(data []byte) error {
+	return pl.Set(string(data))
+}
+
+// NewPackageLevelHandler 返回按调用方所在的包过滤记录的 Handler，
+// 级别由 levels 决定，next 自身的 Level 不再生效；没有调用位置（PC 为 0）的记录使用默认级别
+func NewPackageLevelHandler(next slog.Handler, levels *PackageLevels) slog.Handler {
+	return &packageLevelHandler{next: next, levels: levels}
+}
+
+type packageLevelHandler struct {
+	next   slog.Handler
+	levels *PackageLevels
+}
+
+// Enabled 只按所有包中最低的级别判断，具体的包在 Handle 中过滤
+func (h *packageLevelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.levels.config().min
+}
+
+func (h *packageLevelHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.levels.Level(r.PC) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *packageLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &packageLevelHandler{next: h.next.WithAttrs(attrs), levels: h.levels}
+}
+
+func (h *packageLevelHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &packageLevelHandler{next: h.next.WithGroup(name), levels: h.levels}
+}
@@ -0,0 +1,88 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestPackageLevels(t *testing.T) {
+	pl, err := ParsePackageLevels("github.com/IAmMrChen/slogplus=debug, github.com/IAmMrChen/slogplus/parse=error, default=warn")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(NewPackageLevelHandler(New(&buf, &Options{TimeFormat: "-"}), pl))
+	logger.Debug("debug")
+	if got := buf.String(); got != "- DEBUG msg=debug\n" {
+		t.Errorf("本包应该使用 DEBUG: %q", got)
+	}
+
+	buf.Reset()
+	if err := pl.Set("github.com/IAmMrChen/slog=debug,warn"); err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("info")
+	logger.Warn("warn")
+	if got := buf.String(); got != "- WARN msg=warn\n" {
+		t.Errorf("前缀应该按包路径边界匹配: %q", got)
+	}
+
+	buf.Reset()
+	r := slog.NewRecord(time.Time{}, slog.LevelInfo, "no pc", 0)
+	NewPackageLevelHandler(New(&buf, nil), pl).Handle(context.Background(), r)
+	if buf.Len() != 0 {
+		t.Errorf("没有调用位置的记录应该使用默认级别: %q", buf.String())
+	}
+}
+
+func TestPackageLevels_Lookup(t *testing.T) {
+	pl := NewPackageLevels(slog.LevelInfo, map[string]slog.Level{
+		"github.com/me/app":    slog.LevelWarn,
+		"github.com/me/app/db": slog.LevelDebug,
+	})
+	c := pl.config()
+	for pkg, want := range map[string]slog.Level{
+		"github.com/me/app":         slog.LevelWarn,
+		"github.com/me/app/db":      slog.LevelDebug,
+		"github.com/me/app/db/pool": slog.LevelDebug,
+		"github.com/me/app/dbx":     slog.LevelWarn,
+		"github.com/me/application": slog.LevelInfo,
+		"net/http":                  slog.LevelInfo,
+	} {
+		if got := c.lookup(pkg); got != want {
+			t.Errorf("%s: %v, 期望 %v", pkg, got, want)
+		}
+	}
+	if got := pl.String(); got != "github.com/me/app=WARN,github.com/me/app/db=DEBUG,default=INFO" {
+		t.Errorf("String = %q", got)
+	}
+	if c.min != slog.LevelDebug {
+		t.Errorf("最低级别错误: %v", c.min)
+	}
+
+	var zero PackageLevels
+	if zero.Level(1) != slog.LevelInfo {
+		t.Error("零值应该为 Info")
+	}
+	if _, err := ParsePackageLevels("github.com/me/app=verbose"); err == nil {
+		t.Error("无效的级别应该返回错误")
+	}
+}
+
+func TestFuncPackage(t *testing.T) {
+	pc, _, _, _ := runtime.Caller(0)
+	if got := funcPackage(pc); got != "github.com/IAmMrChen/slogplus" {
+		t.Errorf("funcPackage = %q", got)
+	}
+	func() {
+		pc, _, _, _ := runtime.Caller(0)
+		if got := funcPackage(pc); got != "github.com/IAmMrChen/slogplus" {
+			t.Errorf("闭包的包路径错误: %q", got)
+		}
+	}()
+}
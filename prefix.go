@@ -0,0 +1,56 @@
+package slogplus
+
+import "log/slog"
+
+// attrPrefix 是 WithAttrs 添加的属性按某种输出格式预先编码的结果
+type attrPrefix struct {
+	format Format
+	b      []byte
+}
+
+// appendBound 追加 WithAttrs 添加的属性按格式 f 编码后的字节
+// 编码结果缓存在 Handler 上，请求级的子 Logger 输出大量日志时不必每次重新编码相同的属性；
+// 预设属性中的 LogValuer 已经在 WithAttrs 时展开，FormatVar 切换格式后按新格式重新编码相同的值。
+// 只有按属性逐个编码、与记录无关的格式使用缓存
+func (h *Handler) appendBound(buf []byte, f Format) []byte {
+	if len(h.attrs) == 0 {
+		return buf
+	}
+	if p := h.prefix.Load(); p != nil && p.format == f {
+		return append(buf, p.b...)
+	}
+	var b []byte
+	for _, ba := range h.attrs {
+		switch f {
+		case FormatJSON:
+			b = h.appendJSONAttr(b, ba.groups, ba.Attr, appendJSONDottedKey, false)
+		case FormatGELF:
+			b = h.appendJSONAttr(b, ba.groups, ba.Attr, appendGELFKey, true)
+		case FormatECS:
+			b = h.appendECSAttr(b, ba.groups, ba.Attr)
+		case FormatSyslog:
+			b = h.appendSyslogParam(b, ba.groups, ba.Attr)
+		case FormatJournal:
+			b = h.appendJournalAttr(b, ba.groups, ba.Attr)
+		default:
+			b = h.appendAttr(b, ba.groups, ba.Attr, f == FormatConsole)
+		}
+	}
+	h.prefix.Store(&attrPrefix{format: f, b: b})
+	return append(buf, b...)
+}
+
+// resolveAttr 展开属性值中的 LogValuer，分组递归处理
+// WithAttrs 添加的属性只展开一次，之后无论输出多少次、切换为哪种格式，值都保持一致
+func resolveAttr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		out := make([]slog.Attr, len(attrs))
+		for i, ga := range attrs {
+			out[i] = resolveAttr(ga)
+		}
+		a.Value = slog.GroupValue(out...)
+	}
+	return a
+}
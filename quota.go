@@ -0,0 +1,161 @@
+package slogplus
+
+import (
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Quota 按属性值（租户、组件等）统计每个时间窗口内的记录数和字节数，超出配额的记录被丢弃
+// 用于多个生产者共享中心化日志管道时保证公平，同一个 Quota 可以在多个 Handler 之间共享
+//
+//	quota := &slogplus.Quota{Key: "tenant", Window: time.Minute, MaxBytes: 10 << 20, Summarize: true}
+//	slogplus.Setup(os.Stdout, &slogplus.Options{Quota: quota})
+type Quota struct {
+	// Key 统计依据的属性名，例如 "tenant"、"component"，分组属性使用 "group.key"
+	// 没有该属性的记录统一计入空字符串
+	Key string
+
+	// Window 统计窗口，默认 1 分钟
+	Window time.Duration
+
+	// MaxRecords 每个窗口内每个属性值的最大记录数，0 表示不限制
+	MaxRecords int64
+
+	// MaxBytes 每个窗口内每个属性值的最大字节数（按编码后的大小计算），0 表示不限制
+	MaxBytes int64
+
+	// Limits 针对特定属性值的配额，覆盖 MaxRecords 和 MaxBytes
+	Limits map[string]QuotaLimit
+
+	// Summarize 是否在窗口结束后为超限的属性值输出一条汇总记录，说明丢弃了多少记录
+	Summarize bool
+
+	mu     sync.Mutex
+	start  time.Time
+	counts map[string]*QuotaUsage
+	now    func() time.Time
+}
+
+// QuotaLimit 是单个属性值的配额
+type QuotaLimit struct {
+	MaxRecords int64
+	MaxBytes   int64
+}
+
+// QuotaUsage 是单个属性值在当前窗口内的用量
+type QuotaUsage struct {
+	Value          string `json:"value"`
+	Records        int64  `json:"records"`
+	Bytes          int64  `json:"bytes"`
+	DroppedRecords int64  `json:"dropped_records"`
+	DroppedBytes   int64  `json:"dropped_bytes"`
+}
+
+// QuotaSummaryMessage 是配额汇总记录的消息
+const QuotaSummaryMessage = "日志配额超限"
+
+// Usage 返回当前窗口内各属性值的用量，按属性值排序
+// Usage 只读取用量而不切换窗口，上一窗口的超限汇总仍由下一条记录输出，窗口已结束时返回空
+func (q *Quota) Usage() []QuotaUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.expired(q.clock()) {
+		return []QuotaUsage{}
+	}
+
+	usage := make([]QuotaUsage, 0, len(q.counts))
+	for _, u := range q.counts {
+		usage = append(usage, *u)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Value < usage[j].Value })
+	return usage
+}
+
+// value 返回记录的统计属性值
+func (q *Quota) value(r *slog.Record, groups []string, attrs []boundAttr) string {
+	e := filterEnv{r: r, groups: groups, attrs: attrs}
+	if v, ok := e.lookup(q.Key); ok {
+		return v.String()
+	}
+	return ""
+}
+
+// allow 记录一条大小为 n 字节的记录，超出配额时返回 false
+// 进入新窗口时返回上一窗口中超限的用量，用于输出汇总记录
+func (q *Quota) allow(value string, n int) (ok bool, exceeded []QuotaUsage) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	exceeded = q.rotate(q.clock())
+
+	u := q.counts[value]
+	if u == nil {
+		u = &QuotaUsage{Value: value}
+		q.counts[value] = u
+	}
+	maxRecords, maxBytes := q.MaxRecords, q.MaxBytes
+	if l, found := q.Limits[value]; found {
+		maxRecords, maxBytes = l.MaxRecords, l.MaxBytes
+	}
+	if maxRecords > 0 && u.Records+1 > maxRecords || maxBytes > 0 && u.Bytes+int64(n) > maxBytes {
+		u.DroppedRecords++
+		u.DroppedBytes += int64(n)
+		return false, exceeded
+	}
+	u.Records++
+	u.Bytes += int64(n)
+	return true, exceeded
+}
+
+// rotate 在窗口结束时清空计数，调用方需持有 mu
+func (q *Quota) rotate(now time.Time) (exceeded []QuotaUsage) {
+	if !q.expired(now) {
+		return nil
+	}
+
+	if q.Summarize {
+		for _, u := range q.counts {
+			if u.DroppedRecords > 0 {
+				exceeded = append(exceeded, *u)
+			}
+		}
+		sort.Slice(exceeded, func(i, j int) bool { return exceeded[i].Value < exceeded[j].Value })
+	}
+	q.start = now.Truncate(q.window())
+	q.counts = make(map[string]*QuotaUsage)
+	return exceeded
+}
+
+// expired 判断当前窗口是否已经结束，调用方需持有 mu
+func (q *Quota) expired(now time.Time) bool {
+	return q.counts == nil || now.Sub(q.start) >= q.window()
+}
+
+// window 返回统计窗口，默认 1 分钟
+func (q *Quota) window() time.Duration {
+	if q.Window <= 0 {
+		return time.Minute
+	}
+	return q.Window
+}
+
+func (q *Quota) clock() time.Time {
+	if q.now != nil {
+		return q.now()
+	}
+	return time.Now()
+}
+
+// summaryRecord 生成配额汇总记录
+func (q *Quota) summaryRecord(u QuotaUsage) slog.Record {
+	r := slog.NewRecord(q.clock(), slog.LevelWarn, QuotaSummaryMessage, 0)
+	r.AddAttrs(
+		slog.String("quota.key", q.Key),
+		slog.String("quota.value", u.Value),
+		slog.Int64("dropped_records", u.DroppedRecords),
+		slog.Int64("dropped_bytes", u.DroppedBytes),
+	)
+	return r
+}
@@ -0,0 +1,69 @@
+package slogplus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuota(t *testing.T) {
+	now := time.Date(2025, 11, 14, 14, 0, 0, 0, time.UTC)
+	q := &Quota{
+		Key:        "tenant",
+		MaxRecords: 2,
+		Limits:     map[string]QuotaLimit{"vip": {}},
+		Summarize:  true,
+		now:        func() time.Time { return now },
+	}
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{Quota: q})
+	for i := 0; i < 4; i++ {
+		logger.Info("noisy", "tenant", "acme")
+		logger.Info("vip", "tenant", "vip")
+	}
+	logger.Info("no tenant")
+
+	if got := strings.Count(buf.String(), "msg=noisy"); got != 2 {
+		t.Errorf("超出配额的记录应该被丢弃，输出了 %d 条", got)
+	}
+	if got := strings.Count(buf.String(), "msg=vip"); got != 4 {
+		t.Errorf("Limits 中不限制的属性值应该全部输出，输出了 %d 条", got)
+	}
+
+	usage := q.Usage()
+	if len(usage) != 3 || usage[1] != (QuotaUsage{Value: "acme", Records: 2, Bytes: usage[1].Bytes, DroppedRecords: 2, DroppedBytes: usage[1].DroppedBytes}) {
+		t.Errorf("用量不正确: %+v", usage)
+	}
+
+	// 进入新窗口后输出汇总记录并重新计数，期间读取用量不影响汇总
+	buf.Reset()
+	now = now.Add(time.Minute)
+	if usage := q.Usage(); len(usage) != 0 {
+		t.Errorf("窗口结束后用量应该为空: %+v", usage)
+	}
+	logger.Info("noisy", "tenant", "acme")
+	out := buf.String()
+	if !strings.Contains(out, "WARN msg="+QuotaSummaryMessage+" quota.key=tenant quota.value=acme dropped_records=2") {
+		t.Errorf("应该输出汇总记录: %s", out)
+	}
+	if !strings.Contains(out, "msg=noisy") {
+		t.Errorf("新窗口应该重新计数: %s", out)
+	}
+}
+
+func TestQuota_MaxBytes(t *testing.T) {
+	q := &Quota{Key: "component", MaxBytes: 100}
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{Quota: q}).With("component", "db")
+	logger.Info(strings.Repeat("x", 50))
+	logger.Info(strings.Repeat("y", 50))
+
+	if strings.Contains(buf.String(), "yyy") {
+		t.Errorf("超出字节配额的记录应该被丢弃: %s", buf.String())
+	}
+	if u := q.Usage(); len(u) != 1 || u[0].Value != "db" || u[0].DroppedRecords != 1 {
+		t.Errorf("应该按预设属性统计: %+v", u)
+	}
+}
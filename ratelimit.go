@@ -0,0 +1,207 @@
+package slogplus
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// RateLimit 是一个级别的令牌桶配置
+type RateLimit struct {
+	// Rate 每秒补充的令牌数，即长期平均每秒允许的记录数，小于等于 0 表示不限制
+	Rate float64
+
+	// Burst 令牌桶容量，即允许的突发记录数，默认为 Rate 向上取整
+	Burst int
+}
+
+// RateLimitOptions 定义限流 Handler 的配置
+type RateLimitOptions struct {
+	// Limits 各级别的限流配置，按级别精确匹配，没有配置的级别不限制，例如
+	// {slog.LevelDebug: {Rate: 100}, slog.LevelInfo: {Rate: 1000, Burst: 5000}} 不限制 WARN 和 ERROR
+	Limits map[slog.Level]RateLimit
+
+	// SummaryInterval 汇总记录的最小间隔，默认 1 分钟
+	// 有记录被限流时，间隔结束后的下一条记录（或 Flush）之前会输出一条汇总记录，说明各级别丢弃了多少记录
+	SummaryInterval time.Duration
+
+	// SummaryLevel 汇总记录的级别，默认为 Warn
+	SummaryLevel slog.Leveler
+}
+
+// RateLimitSummaryMessage 是限流汇总记录的消息
+const RateLimitSummaryMessage = "日志被限流"
+
+// NewRateLimiter 返回按级别使用令牌桶限流的 Handler，超出速率的记录被丢弃，
+// 并定期输出一条汇总记录，让运维人员知道发生了限流:
+//
+//	h := slogplus.NewRateLimiter(base, &slogplus.RateLimitOptions{
+//		Limits: map[slog.Level]slogplus.RateLimit{
+//			slog.LevelDebug: {Rate: 100},
+//			slog.LevelInfo:  {Rate: 1000, Burst: 5000},
+//		},
+//	})
+//
+// 同一个 Handler 派生出的所有 Handler 共享令牌桶
+func NewRateLimiter(next slog.Handler, opts *RateLimitOptions) slog.Handler {
+	l := &rateLimiter{next: next, buckets: make(map[slog.Level]*tokenBucket)}
+	if opts != nil {
+		l.opts = *opts
+	}
+	if l.opts.SummaryInterval <= 0 {
+		l.opts.SummaryInterval = time.Minute
+	}
+	for level, limit := range l.opts.Limits {
+		if limit.Rate <= 0 {
+			continue
+		}
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = int(math.Ceil(limit.Rate))
+		}
+		l.buckets[level] = &tokenBucket{rate: limit.Rate, burst: float64(burst), tokens: float64(burst)}
+	}
+	return &rateLimitHandler{next: next, l: l}
+}
+
+// tokenBucket 是一个级别的令牌桶和被丢弃的记录数
+type tokenBucket struct {
+	rate, burst float64
+	tokens      float64
+	last        time.Time
+	dropped     int64
+}
+
+// rateLimiter 是所有派生 Handler 共享的限流状态
+type rateLimiter struct {
+	opts    RateLimitOptions
+	next    slog.Handler // 未经 With 派生的 Handler，用于输出汇总记录
+	mu      sync.Mutex
+	buckets map[slog.Level]*tokenBucket
+	summary time.Time // 上次输出汇总记录的时间
+	now     func() time.Time
+}
+
+func (l *rateLimiter) clock() time.Time {
+	if l.now != nil {
+		return l.now()
+	}
+	return time.Now()
+}
+
+// allow 判断该级别的记录是否可以输出，到达汇总间隔且有记录被丢弃时返回汇总记录
+func (l *rateLimiter) allow(level slog.Level) (ok bool, summary *slog.Record) {
+	if len(l.buckets) == 0 {
+		return true, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock()
+	if l.summary.IsZero() {
+		l.summary = now
+	}
+
+	ok = true
+	if b := l.buckets[level]; b != nil {
+		if !b.last.IsZero() {
+			b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		}
+		b.last = now
+		if ok = b.tokens >= 1; ok {
+			b.tokens--
+		} else {
+			b.dropped++
+		}
+	}
+	if now.Sub(l.summary) >= l.opts.SummaryInterval {
+		summary = l.summarize(now)
+	}
+	return ok, summary
+}
+
+// summarize 生成汇总记录并清空丢弃计数，没有记录被丢弃时返回 nil，调用方需持有 mu
+func (l *rateLimiter) summarize(now time.Time) *slog.Record {
+	levels := make([]slog.Level, 0, len(l.buckets))
+	for level, b := range l.buckets {
+		if b.dropped > 0 {
+			levels = append(levels, level)
+		}
+	}
+	interval := now.Sub(l.summary)
+	l.summary = now
+	if len(levels) == 0 {
+		return nil
+	}
+	slices.Sort(levels)
+
+	level := slog.LevelWarn
+	if l.opts.SummaryLevel != nil {
+		level = l.opts.SummaryLevel.Level()
+	}
+	r := slog.NewRecord(now, level, RateLimitSummaryMessage, 0)
+	r.AddAttrs(slog.Duration("interval", interval))
+	attrs := make([]any, len(levels))
+	var total int64
+	for i, level := range levels {
+		b := l.buckets[level]
+		attrs[i] = slog.Int64(levelName(level), b.dropped)
+		total += b.dropped
+		b.dropped = 0
+	}
+	r.AddAttrs(slog.Int64("suppressed", total), slog.Group("suppressed_by_level", attrs...))
+	return &r
+}
+
+// emit 输出汇总记录
+func (l *rateLimiter) emit(ctx context.Context, r *slog.Record) error {
+	if r == nil || !l.next.Enabled(ctx, r.Level) {
+		return nil
+	}
+	return l.next.Handle(ctx, *r)
+}
+
+type rateLimitHandler struct {
+	next slog.Handler
+	l    *rateLimiter
+}
+
+func (h *rateLimitHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *rateLimitHandler) Handle(ctx context.Context, r slog.Record) error {
+	ok, summary := h.l.allow(r.Level)
+	err := h.l.emit(ctx, summary)
+	if !ok {
+		return err
+	}
+	return errors.Join(err, h.next.Handle(ctx, r))
+}
+
+func (h *rateLimitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &rateLimitHandler{next: h.next.WithAttrs(attrs), l: h.l}
+}
+
+func (h *rateLimitHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &rateLimitHandler{next: h.next.WithGroup(name), l: h.l}
+}
+
+// Flush 立即输出尚未汇总的限流记录，并刷新被包装的 Handler
+func (h *rateLimitHandler) Flush() error {
+	h.l.mu.Lock()
+	summary := h.l.summarize(h.l.clock())
+	h.l.mu.Unlock()
+	err := h.l.emit(context.Background(), summary)
+	if f, ok := h.l.next.(Flusher); ok {
+		err = errors.Join(err, f.Flush())
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package slogplus
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewRateLimiter(New(&buf, &Options{TimeFormat: "-", Level: slog.LevelDebug}), &RateLimitOptions{
+		Limits: map[slog.Level]RateLimit{
+			slog.LevelDebug: {Rate: 2},
+			slog.LevelInfo:  {Rate: 1, Burst: 3},
+		},
+		SummaryInterval: 10 * time.Second,
+	}).(*rateLimitHandler)
+	now := time.Date(2025, 11, 14, 14, 0, 0, 0, time.UTC)
+	h.l.now = func() time.Time { return now }
+	logger := slog.New(h).With("app", "demo")
+
+	for i := 0; i < 5; i++ {
+		logger.Debug("d")
+		logger.Info("i")
+		logger.Error("e")
+	}
+	out := buf.String()
+	if n := strings.Count(out, "msg=d"); n != 2 {
+		t.Errorf("DEBUG 应该保留 2 条: %d", n)
+	}
+	if n := strings.Count(out, "msg=i"); n != 3 {
+		t.Errorf("INFO 应该保留 Burst 条: %d", n)
+	}
+	if n := strings.Count(out, "msg=e"); n != 5 {
+		t.Errorf("没有配置的级别不应该限流: %d", n)
+	}
+
+	// 令牌按速率补充
+	now = now.Add(time.Second)
+	buf.Reset()
+	logger.Info("i")
+	logger.Info("i")
+	if n := strings.Count(buf.String(), "msg=i"); n != 1 {
+		t.Errorf("1 秒后应该补充 1 个令牌: %d", n)
+	}
+
+	now = now.Add(10 * time.Second)
+	buf.Reset()
+	logger.Error("e")
+	want := "- WARN msg=日志被限流 interval=11s suppressed=6 suppressed_by_level={DEBUG=3 INFO=3}\n- ERROR app=demo msg=e\n"
+	if got := buf.String(); got != want {
+		t.Errorf("汇总记录错误:\n%s", got)
+	}
+
+	buf.Reset()
+	logger.Debug("d")
+	logger.Debug("d")
+	logger.Debug("d")
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "suppressed=1 ") {
+		t.Errorf("Flush 应该输出汇总记录: %s", buf.String())
+	}
+	buf.Reset()
+	h.Flush()
+	if buf.Len() != 0 {
+		t.Errorf("没有限流时不应该输出汇总记录: %s", buf.String())
+	}
+}
+
+func TestRateLimiter_JSONSummary(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewRateLimiter(New(&buf, &Options{Format: FormatJSON}), &RateLimitOptions{
+		Limits:       map[slog.Level]RateLimit{slog.LevelInfo: {Rate: 1}},
+		SummaryLevel: slog.LevelError,
+	}).(*rateLimitHandler)
+	logger := slog.New(h)
+	logger.Info("a")
+	logger.Info("b")
+	buf.Reset()
+	h.Flush()
+
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatal(err, buf.String())
+	}
+	if rec["level"] != "ERROR" || rec["msg"] != RateLimitSummaryMessage || rec["suppressed"] != float64(1) {
+		t.Errorf("汇总记录错误: %s", buf.String())
+	}
+}
@@ -0,0 +1,250 @@
+package slogplus
+
+import (
+	"fmt"
+	"log/slog"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Redactor 是可插拔的脱敏逻辑，通过 Options.Redactor 设置
+// Redact 对每个非分组属性调用一次，groups 为属性所在的分组，返回空 Attr 表示丢弃该属性
+// 与 ReplaceAttr 不同，Redactor 在所有输出格式中对分组内的属性和 LogValuer 展开后的属性同样生效
+type Redactor interface {
+	Redact(groups []string, a slog.Attr) slog.Attr
+}
+
+// RedactorFunc 将函数适配为 Redactor
+type RedactorFunc func(groups []string, a slog.Attr) slog.Attr
+
+// Redact 实现 Redactor
+func (f RedactorFunc) Redact(groups []string, a slog.Attr) slog.Attr { return f(groups, a) }
+
+// Redactors 将多个 Redactor 组合为一个，按顺序执行，属性被丢弃后不再调用后面的 Redactor；nil 被忽略
+//
+//	Redactor: slogplus.Redactors(&slogplus.IPAnonymizer{}, corpScrubber)
+func Redactors(rs ...Redactor) Redactor {
+	return RedactorFunc(func(groups []string, a slog.Attr) slog.Attr {
+		for _, r := range rs {
+			if r == nil {
+				continue
+			}
+			if a = r.Redact(groups, a); a.Equal(slog.Attr{}) {
+				break
+			}
+		}
+		return a
+	})
+}
+
+// attrRewriter 汇总 Options 中的脱敏配置，在编码前改写记录和 WithAttrs 添加的属性，
+// 分组、LogValuer 展开后的分组以及 map 递归处理，所有输出格式的效果一致
+type attrRewriter struct {
+	allow   keyPatterns // AllowKeys，为空时不限制
+	redact  keyPatterns
+	masks   []*regexp.Regexp
+	pii     *PII
+	hmac    *HMAC
+	hashed  keyPatterns // HMAC.Keys
+	custom  Redactor
+	encrypt *encryptor // NewEncryptHandler 设置，匹配的属性整体加密
+	maxLen  int        // MaxValueLen
+	reveal  bool       // RevealSecrets
+}
+
+// newAttrRewriter 根据配置创建 attrRewriter，没有任何改写配置时返回 nil
+func newAttrRewriter(opts *Options) *attrRewriter {
+	if len(opts.AllowKeys) == 0 && len(opts.RedactKeys) == 0 && len(opts.Masks) == 0 && opts.PII == nil && opts.HMAC == nil && opts.Redactor == nil && opts.MaxValueLen <= 0 && !opts.RevealSecrets {
+		return nil
+	}
+	rw := &attrRewriter{
+		allow:  newKeyPatterns(opts.AllowKeys),
+		redact: newKeyPatterns(opts.RedactKeys),
+		masks:  opts.Masks,
+		pii:    opts.PII,
+		custom: opts.Redactor,
+		maxLen: opts.MaxValueLen,
+		reveal: opts.RevealSecrets,
+	}
+	if opts.HMAC != nil && len(opts.HMAC.Keys) > 0 {
+		rw.hmac = opts.HMAC
+		rw.hashed = newKeyPatterns(opts.HMAC.Keys)
+	}
+	return rw
+}
+
+// keyPatterns 是不区分大小写的属性名规则，支持 * 和 ? 通配符
+// 不含 '.' 的规则匹配属性名，含 '.' 的规则匹配 group.key 形式的完整路径
+type keyPatterns []string
+
+func newKeyPatterns(keys []string) keyPatterns {
+	var ps keyPatterns
+	for _, k := range keys {
+		ps = append(ps, strings.ToLower(k))
+	}
+	return ps
+}
+
+// match 判断 groups 中的属性 key 是否匹配任一规则
+func (ps keyPatterns) match(groups []string, key string) bool {
+	if len(ps) == 0 || key == "" {
+		return false
+	}
+	key = strings.ToLower(key)
+	full := ""
+	for _, p := range ps {
+		name := key
+		if strings.Contains(p, ".") {
+			if full == "" {
+				full = strings.ToLower(strings.Join(append(groups[:len(groups):len(groups)], key), "."))
+			}
+			name = full
+		}
+		if p == name {
+			return true
+		}
+		if ok, err := path.Match(p, name); ok && err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// attr 改写一个属性，groups 为属性所在的分组，返回 false 表示属性不在 AllowKeys 中应该丢弃
+func (rw *attrRewriter) attr(groups []string, a slog.Attr) (slog.Attr, bool) {
+	return rw.rewrite(groups, a, len(rw.allow) == 0)
+}
+
+// rewrite 改写一个属性，allowed 表示所在的分组已经整体在 AllowKeys 中
+func (rw *attrRewriter) rewrite(groups []string, a slog.Attr, allowed bool) (slog.Attr, bool) {
+	allowed = allowed || rw.allow.match(groups, a.Key)
+	if rw.redact.match(groups, a.Key) {
+		return slog.String(a.Key, RedactedValue), allowed
+	}
+
+	if rw.reveal && a.Value.Kind() == slog.KindLogValuer {
+		if s, ok := a.Value.Any().(revealer); ok {
+			a.Value = slog.AnyValue(s.reveal())
+		}
+	}
+	a.Value = a.Value.Resolve()
+	// map 在编码时才展开为分组，这里先转换，使其中的键同样受脱敏规则约束
+	if g, ok := mapGroup(a.Value, 0); ok {
+		a.Value = g
+	}
+	if rw.encrypt != nil && rw.encrypt.keys.match(groups, a.Key) {
+		return rw.encrypt.seal(a), allowed
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		if !allowed {
+			return a, false
+		}
+		if rw.hashed.match(groups, a.Key) {
+			a = slog.String(a.Key, rw.hmac.sum(a.Value.String()))
+		} else if a.Value.Kind() == slog.KindString {
+			a.Value = slog.StringValue(rw.text(a.Value.String()))
+		} else if a.Value.Kind() == slog.KindAny && (len(rw.masks) > 0 || rw.pii != nil) {
+			a.Value = rw.anyText(a.Value)
+		}
+		if rw.maxLen > 0 {
+			a.Value = truncateValue(a.Value, rw.maxLen)
+		}
+		if rw.custom != nil {
+			a = rw.custom.Redact(groups, a)
+			return a, !a.Equal(slog.Attr{})
+		}
+		return a, true
+	}
+
+	if a.Key != "" {
+		groups = append(groups[:len(groups):len(groups)], a.Key)
+	}
+	attrs := a.Value.Group()
+	out := make([]slog.Attr, 0, len(attrs))
+	for _, ga := range attrs {
+		if ga, ok := rw.rewrite(groups, ga, allowed); ok {
+			out = append(out, ga)
+		}
+	}
+	// 不在 AllowKeys 中的分组只保留其中允许的属性，全部被丢弃时分组也被丢弃
+	if len(out) == 0 && !allowed {
+		return a, false
+	}
+	a.Value = slog.GroupValue(out...)
+	return a, true
+}
+
+// text 对消息或字符串值应用正则掩码和个人信息检测
+func (rw *attrRewriter) text(s string) string {
+	if len(rw.masks) > 0 {
+		s = maskString(rw.masks, s)
+	}
+	if rw.pii != nil {
+		s = rw.pii.scrub(s)
+	}
+	return s
+}
+
+// anyText 对 error 和 fmt.Stringer 的文本应用正则掩码和个人信息检测，
+// 文本被改写时值替换为改写后的字符串，否则保持原样
+func (rw *attrRewriter) anyText(v slog.Value) slog.Value {
+	if isNilValue(v) {
+		return v
+	}
+	var s string
+	switch x := v.Any().(type) {
+	case error:
+		s = x.Error()
+	case fmt.Stringer:
+		s = x.String()
+	default:
+		return v
+	}
+	if t := rw.text(s); t != s {
+		return slog.StringValue(t)
+	}
+	return v
+}
+
+// truncateValue 截断超过 n 字节的字符串和 []byte，截断处对齐到 UTF-8 字符边界并追加 …(+N bytes)
+func truncateValue(v slog.Value, n int) slog.Value {
+	var s string
+	total := 0
+	switch v.Kind() {
+	case slog.KindString:
+		s = v.String()
+		total = len(s)
+	case slog.KindAny:
+		b, ok := v.Any().([]byte)
+		if !ok {
+			return v
+		}
+		s = string(b[:min(len(b), n+utf8.UTFMax)])
+		total = len(b)
+	default:
+		return v
+	}
+	if total <= n {
+		return v
+	}
+	cut := n
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return slog.StringValue(s[:cut] + "…(+" + strconv.Itoa(total-cut) + " bytes)")
+}
+
+// record 改写记录的消息和所有属性
+func (rw *attrRewriter) record(groups []string, r slog.Record) slog.Record {
+	nr := slog.NewRecord(r.Time, r.Level, rw.text(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		if a, ok := rw.attr(groups, a); ok {
+			nr.AddAttrs(a)
+		}
+		return true
+	})
+	return nr
+}
@@ -0,0 +1,115 @@
+package slogplus
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedactKeys(t *testing.T) {
+	opts := &Options{TimeFormat: "-", RedactKeys: []string{"password", "*TOKEN*", "user.email"}}
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, opts).With("api_token", "t1").WithGroup("req")
+	logger.Info("登录",
+		"Password", "p@ss",
+		slog.Group("user", "name", "alice", "email", "a@b.c"),
+		"email", "kept@b.c",
+		slog.Group("auth", "refresh_token", "r1"),
+	)
+
+	want := "- INFO api_token=[REDACTED] msg=登录 req.Password=[REDACTED] req.user={name=alice email=a@b.c} req.email=kept@b.c req.auth={refresh_token=[REDACTED]}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("输出错误:\n got %q\nwant %q", got, want)
+	}
+
+	buf.Reset()
+	NewLogger(&buf, opts).Info("登录", slog.Group("user", "name", "alice", "email", "a@b.c"), slog.Attr{Key: "password", Value: Err(errors.New("x")).Value})
+	want = "- INFO msg=登录 user={name=alice email=[REDACTED]} password=[REDACTED]\n"
+	if got := buf.String(); got != want {
+		t.Errorf("完整路径规则错误:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestRedactKeys_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{Format: FormatJSON, TimeFormat: "-", RedactKeys: []string{"secret"}})
+	logger.Info("x", slog.Group("cfg", "secret", "s", "host", "db"))
+	if got := buf.String(); !strings.Contains(got, `"cfg":{"secret":"[REDACTED]","host":"db"}`) {
+		t.Errorf("JSON 输出错误: %s", got)
+	}
+}
+
+func TestRedactKeys_Map(t *testing.T) {
+	user := map[string]any{"n": 1, "password": "hunter2", "meta": map[string]string{"password": "p2"}}
+
+	var buf bytes.Buffer
+	NewLogger(&buf, &Options{TimeFormat: "-", RedactKeys: []string{"password"}}).Info("x", "user", user)
+	if got, want := buf.String(), "- INFO msg=x user={meta={password=[REDACTED]} n=1 password=[REDACTED]}\n"; got != want {
+		t.Errorf("文本输出错误:\n got %q\nwant %q", got, want)
+	}
+
+	buf.Reset()
+	NewLogger(&buf, &Options{Format: FormatJSON, TimeFormat: "-", RedactKeys: []string{"password"}}).Info("x", "user", user)
+	if got := buf.String(); strings.Contains(got, "hunter2") || !strings.Contains(got, `"user":{"meta":{"password":"[REDACTED]"},"n":1,"password":"[REDACTED]"}`) {
+		t.Errorf("JSON 输出错误: %s", got)
+	}
+}
+
+func TestAllowKeys(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{TimeFormat: "-", AllowKeys: []string{"request_id", "http", "user.id"}})
+	logger.With("request_id", "r1", "host", "web-1").Info("请求",
+		slog.Group("http", "method", "GET", "status", 200),
+		slog.Group("user", "id", 42, "email", "a@b.c"),
+		slog.Group("debug", "sql", "select 1"),
+		"token", "t",
+	)
+
+	want := "- INFO request_id=r1 msg=请求 http={method=GET status=200} user={id=42}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("输出错误:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestRedactor(t *testing.T) {
+	var seen []string
+	dropDebug := RedactorFunc(func(groups []string, a slog.Attr) slog.Attr {
+		seen = append(seen, strings.Join(append(groups, a.Key), "."))
+		if strings.HasPrefix(a.Key, "debug_") {
+			return slog.Attr{}
+		}
+		return a
+	})
+	upper := RedactorFunc(func(_ []string, a slog.Attr) slog.Attr {
+		if a.Key == "name" {
+			return slog.String(a.Key, strings.ToUpper(a.Value.String()))
+		}
+		return a
+	})
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{TimeFormat: "-", Redactor: Redactors(dropDebug, nil, upper, &IPAnonymizer{})})
+	logger.WithGroup("req").Info("x", "ip", "10.1.2.3", "debug_sql", "select 1", slog.Group("user", "name", "alice"))
+
+	want := "- INFO msg=x req.ip=10.1.2.0 req.user={name=ALICE}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("输出错误:\n got %q\nwant %q", got, want)
+	}
+	if strings.Join(seen, ",") != "req.ip,req.debug_sql,req.user.name" {
+		t.Errorf("Redactor 收到的分组错误: %v", seen)
+	}
+}
+
+func TestMaxValueLen(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{TimeFormat: "-", MaxValueLen: 8})
+	logger.Info("x", "body", "0123456789abcdef", "zh", "你好世界", "raw", []byte("0123456789"), "short", "ok", "n", 1234567890123)
+
+	want := "- INFO msg=x body=01234567…(+8 bytes) zh=你好…(+6 bytes) raw=01234567…(+2 bytes) short=ok n=1234567890123\n"
+	if got := buf.String(); got != want {
+		t.Errorf("输出错误:\n got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,53 @@
+package slogplus
+
+import (
+	"errors"
+	"sync"
+)
+
+// reloaders 是全局注册的重新加载函数，按注册顺序执行
+var reloaders struct {
+	mu   sync.Mutex
+	list []*reloaderEntry
+}
+
+type reloaderEntry struct {
+	fn func() error
+}
+
+// RegisterReloader 注册一个在 ReloadAll（以及 HandleSignals 收到 SIGHUP 时）执行的函数，
+// 例如重新读取配置中的级别；FileWriter 会自动注册重新打开文件，返回的函数用于取消注册
+func RegisterReloader(fn func() error) (unregister func()) {
+	e := &reloaderEntry{fn: fn}
+	reloaders.mu.Lock()
+	reloaders.list = append(reloaders.list, e)
+	reloaders.mu.Unlock()
+
+	return func() {
+		reloaders.mu.Lock()
+		defer reloaders.mu.Unlock()
+		for i, x := range reloaders.list {
+			if x == e {
+				reloaders.list = append(reloaders.list[:i], reloaders.list[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// ReloadAll 重新打开所有 FileWriter 并执行已注册的重新加载函数，返回合并后的错误
+// logrotate 移走日志文件后调用，之后的记录写入原路径的新文件
+func ReloadAll() error {
+	reloaders.mu.Lock()
+	list := make([]*reloaderEntry, len(reloaders.list))
+	copy(list, reloaders.list)
+	reloaders.mu.Unlock()
+
+	var errs []error
+	for _, e := range list {
+		if err := e.fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,57 @@
+package slogplus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// RequestIDKey 是请求 ID 的属性名
+const RequestIDKey = "request_id"
+
+// requestIDKey 是请求 ID 在 context 中的键
+type requestIDKey struct{}
+
+// WithRequestID 返回带有请求 ID 的 context，并通过 AppendCtx 附加 request_id 属性，
+// 之后使用该 context 记录的日志都会带上请求 ID；id 为空时原样返回 ctx
+func WithRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return AppendCtx(ctx, RequestIDKey, id)
+}
+
+// RequestID 返回 context 中的请求 ID，没有时返回空字符串
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID 生成 32 个十六进制字符的随机请求 ID
+func NewRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// validRequestID 判断外部传入的请求 ID 是否可以直接使用：
+// 不超过 128 个字符，只包含字母、数字和 -_.:，防止日志注入和超长字段
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import "net/http"
+
+// RequestIDHeader 是默认的请求 ID 请求头
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDOptions 定义请求 ID 中间件的配置
+type RequestIDOptions struct {
+	// Header 读取和返回请求 ID 的请求头，默认为 X-Request-ID
+	Header string
+
+	// Generate 生成新请求 ID 的函数，默认为 NewRequestID
+	Generate func() string
+
+	// IgnoreIncoming 是否忽略请求中已有的请求 ID，总是生成新的
+	// 面向公网的入口可以开启，避免客户端伪造请求 ID；
+	// 默认沿用上游传入的请求 ID（超过 128 个字符或包含字母、数字、-_.: 以外字符的会被替换）
+	IgnoreIncoming bool
+}
+
+// RequestIDMiddleware 返回为每个请求确定请求 ID 的 HTTP 中间件：
+// 沿用请求头中的请求 ID 或生成新的，写入响应头并通过 WithRequestID 存入 context，
+// 处理函数中使用 r.Context() 记录的日志都会带上 request_id，也可以通过 RequestID 取得
+//
+//	mux := http.NewServeMux()
+//	http.ListenAndServe(":8080", slogplus.RequestIDMiddleware(nil)(mux))
+func RequestIDMiddleware(opts *RequestIDOptions) func(http.Handler) http.Handler {
+	var o RequestIDOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Header == "" {
+		o.Header = RequestIDHeader
+	}
+	if o.Generate == nil {
+		o.Generate = NewRequestID
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(o.Header)
+			if o.IgnoreIncoming || !validRequestID(id) {
+				id = o.Generate()
+			}
+			w.Header().Set(o.Header, id)
+			next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+		})
+	}
+}
+
+// RequestIDTransport 返回在出站请求中传递请求 ID 的 http.RoundTripper，
+// 请求的 context 中有请求 ID 且请求头中没有时添加 X-Request-ID，base 为 nil 时使用 http.DefaultTransport
+//
+//	client := &http.Client{Transport: slogplus.RequestIDTransport(nil)}
+//	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+func RequestIDTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return requestIDTransport{base: base}
+}
+
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+func (t requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if id := RequestID(req.Context()); id != "" && req.Header.Get(RequestIDHeader) == "" {
+		// RoundTripper 不能修改原请求
+		req = req.Clone(req.Context())
+		req.Header.Set(RequestIDHeader, id)
+	}
+	return t.base.RoundTrip(req)
+}
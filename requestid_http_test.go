@@ -0,0 +1,71 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, &Options{TimeFormat: "-"}))
+
+	var upstream string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstream = r.Header.Get(RequestIDHeader)
+	}))
+	defer backend.Close()
+	client := &http.Client{Transport: RequestIDTransport(nil)}
+
+	h := RequestIDMiddleware(&RequestIDOptions{Generate: func() string { return "generated" }})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger.InfoContext(r.Context(), "handled")
+			req, _ := http.NewRequestWithContext(r.Context(), http.MethodGet, backend.URL, nil)
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+		}))
+
+	for _, tt := range []struct {
+		incoming, want string
+	}{
+		{"", "generated"},
+		{"from-gateway-1", "from-gateway-1"},
+		{"bad id\n", "generated"},
+	} {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.incoming != "" {
+			req.Header.Set(RequestIDHeader, tt.incoming)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get(RequestIDHeader); got != tt.want {
+			t.Errorf("响应头错误: %q, 期望 %q", got, tt.want)
+		}
+		if got := buf.String(); got != "- INFO msg=handled request_id="+tt.want+"\n" {
+			t.Errorf("日志应该带有请求 ID: %q", got)
+		}
+		if upstream != tt.want {
+			t.Errorf("出站请求应该传递请求 ID: %q", upstream)
+		}
+	}
+}
+
+func TestRequestIDMiddleware_IgnoreIncoming(t *testing.T) {
+	h := RequestIDMiddleware(&RequestIDOptions{Header: "X-Trace", IgnoreIncoming: true})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Trace", "client-supplied")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get("X-Trace"); got == "client-supplied" || len(got) != 32 {
+		t.Errorf("应该忽略请求中的请求 ID: %q", got)
+	}
+}
@@ -0,0 +1,36 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWithRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, &Options{TimeFormat: "-"}))
+
+	ctx := WithRequestID(context.Background(), "abc-123")
+	logger.InfoContext(ctx, "handled")
+	if got := buf.String(); got != "- INFO msg=handled request_id=abc-123\n" {
+		t.Errorf("应该输出请求 ID: %q", got)
+	}
+	if RequestID(ctx) != "abc-123" || RequestID(context.Background()) != "" {
+		t.Error("RequestID 错误")
+	}
+	if WithRequestID(ctx, "") != ctx {
+		t.Error("空请求 ID 应该原样返回")
+	}
+
+	id := NewRequestID()
+	if len(id) != 32 || id == NewRequestID() || !validRequestID(id) {
+		t.Errorf("生成的请求 ID 错误: %q", id)
+	}
+	for _, bad := range []string{"", "a b", "x\ny", strings.Repeat("a", 129), "<script>"} {
+		if validRequestID(bad) {
+			t.Errorf("%q 不应该通过校验", bad)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package slogplus
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RetryPolicy 是所有远程输出共用的重试策略
+// 配置一次后可在多个输出之间共享，零值即可使用默认配置
+type RetryPolicy struct {
+	// MaxAttempts 最大尝试次数（包含首次），默认 3，设为 1 表示不重试
+	MaxAttempts int
+
+	// InitialBackoff 首次重试前的等待时间，默认 100ms
+	InitialBackoff time.Duration
+
+	// MaxBackoff 单次等待时间的上限，默认 10s
+	MaxBackoff time.Duration
+
+	// Multiplier 每次重试等待时间的增长倍数，默认 2
+	Multiplier float64
+
+	// Jitter 等待时间的随机抖动比例（0~1），默认 0.2
+	Jitter float64
+
+	// BudgetRatio 重试预算，每次成功补充的重试令牌数
+	// 例如 0.1 表示长期来看重试次数不超过成功次数的 10%，避免故障时重试风暴
+	// 为 0 时不限制
+	BudgetRatio float64
+
+	// Retryable 判断错误是否可以重试，默认使用 DefaultRetryable
+	Retryable func(err error) bool
+
+	attempts atomic.Int64
+	retries  atomic.Int64
+	giveUps  atomic.Int64
+
+	mu     sync.Mutex
+	tokens float64
+	inited bool
+}
+
+// retryBudgetMax 重试预算令牌桶的容量
+const retryBudgetMax = 10
+
+// RetryStats 是重试策略的统计信息
+type RetryStats struct {
+	Attempts int64 // 总尝试次数
+	Retries  int64 // 重试次数
+	GiveUps  int64 // 重试耗尽或预算不足后放弃的次数
+}
+
+// StatusError 表示远程服务返回了失败的状态码
+type StatusError struct {
+	Code   int
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	if e.Status != "" {
+		return "slogplus: 远程服务返回 " + e.Status
+	}
+	return "slogplus: 远程服务返回状态码 " + strconv.Itoa(e.Code)
+}
+
+// permanentError 标记不可重试的错误
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 将错误标记为不可重试
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// DefaultRetryable 默认的错误分类
+// 408、425、429 和 5xx（501 除外）状态码以及网络错误可以重试，
+// 其它状态码、context 取消和 Permanent 标记的错误不重试
+func DefaultRetryable(err error) bool {
+	var pe *permanentError
+	if errors.As(err, &pe) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		switch {
+		case se.Code == 408, se.Code == 425, se.Code == 429:
+			return true
+		case se.Code == 501:
+			return false
+		case se.Code >= 500:
+			return true
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Do 执行 fn，失败时按策略重试
+// p 为 nil 时只执行一次
+func (p *RetryPolicy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if p == nil {
+		return fn(ctx)
+	}
+
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = DefaultRetryable
+	}
+
+	for attempt := 1; ; attempt++ {
+		p.attempts.Add(1)
+		err := fn(ctx)
+		if err == nil {
+			p.refund()
+			return nil
+		}
+		if !retryable(err) {
+			return err
+		}
+		if attempt >= maxAttempts || !p.take() {
+			p.giveUps.Add(1)
+			return err
+		}
+
+		timer := time.NewTimer(p.Backoff(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			p.giveUps.Add(1)
+			return err
+		}
+		p.retries.Add(1)
+	}
+}
+
+// Backoff 返回第 attempt 次失败后的等待时间
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = 100 * time.Millisecond
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 10 * time.Second
+	}
+	mult := p.Multiplier
+	if mult < 1 {
+		mult = 2
+	}
+	jitter := p.Jitter
+	if jitter <= 0 || jitter > 1 {
+		jitter = 0.2
+	}
+
+	d := float64(initial) * math.Pow(mult, float64(attempt-1))
+	if d > float64(maxBackoff) {
+		d = float64(maxBackoff)
+	}
+	d += d * jitter * (rand.Float64()*2 - 1)
+	return time.Duration(d)
+}
+
+// Stats 返回重试统计信息
+func (p *RetryPolicy) Stats() RetryStats {
+	return RetryStats{
+		Attempts: p.attempts.Load(),
+		Retries:  p.retries.Load(),
+		GiveUps:  p.giveUps.Load(),
+	}
+}
+
+// take 从重试预算中取出一个令牌
+func (p *RetryPolicy) take() bool {
+	if p.BudgetRatio <= 0 {
+		return true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.inited {
+		p.tokens = retryBudgetMax
+		p.inited = true
+	}
+	if p.tokens < 1 {
+		return false
+	}
+	p.tokens--
+	return true
+}
+
+// refund 成功后补充重试预算
+func (p *RetryPolicy) refund() {
+	if p.BudgetRatio <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.inited {
+		p.tokens = retryBudgetMax
+		p.inited = true
+	}
+	p.tokens = min(p.tokens+p.BudgetRatio, retryBudgetMax)
+}
@@ -0,0 +1,81 @@
+package slogplus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Do(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	calls := 0
+	err := p.Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return &StatusError{Code: 503}
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("应该在第三次成功: calls=%d err=%v", calls, err)
+	}
+
+	calls = 0
+	err = p.Do(context.Background(), func(context.Context) error {
+		calls++
+		return &StatusError{Code: 400}
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("4xx 不应该重试: calls=%d", calls)
+	}
+
+	calls = 0
+	p.Do(context.Background(), func(context.Context) error {
+		calls++
+		return errors.New("connection refused")
+	})
+	if calls != 3 {
+		t.Errorf("网络错误应该重试到上限: calls=%d", calls)
+	}
+
+	stats := p.Stats()
+	if stats.Retries != 4 || stats.GiveUps != 1 {
+		t.Errorf("统计信息不正确: %+v", stats)
+	}
+}
+
+func TestRetryPolicy_Permanent(t *testing.T) {
+	p := &RetryPolicy{InitialBackoff: time.Millisecond}
+	calls := 0
+	p.Do(context.Background(), func(context.Context) error {
+		calls++
+		return Permanent(errors.New("bad payload"))
+	})
+	if calls != 1 {
+		t.Errorf("Permanent 错误不应该重试: calls=%d", calls)
+	}
+}
+
+func TestRetryPolicy_Budget(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 100, InitialBackoff: time.Microsecond, BudgetRatio: 0.1}
+	calls := 0
+	p.Do(context.Background(), func(context.Context) error {
+		calls++
+		return errors.New("down")
+	})
+	if calls != retryBudgetMax+1 {
+		t.Errorf("重试次数应该受预算限制: calls=%d", calls)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Jitter: 0.1}
+	if d := p.Backoff(1); d < 90*time.Millisecond || d > 110*time.Millisecond {
+		t.Errorf("首次等待时间不正确: %v", d)
+	}
+	if d := p.Backoff(10); d > 1100*time.Millisecond {
+		t.Errorf("等待时间应该受 MaxBackoff 限制: %v", d)
+	}
+}
@@ -0,0 +1,97 @@
+package slogplus
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"slices"
+)
+
+// DefaultRoute 是 NewLevelRouter 中的默认路由，低于其它所有路由级别的记录都交给它
+const DefaultRoute slog.Level = math.MinInt
+
+// NewLevelRouter 返回按级别将记录交给不同 Handler 的 Handler
+// routes 的键为最低级别，每条记录交给不高于其级别的最高路由，例如下面的配置中
+// DEBUG、INFO 输出到 stdout，WARN、ERROR 以及更高的自定义级别输出到 stderr；
+// 低于所有路由的记录交给 DefaultRoute，没有设置 DefaultRoute 时被丢弃
+//
+//	slog.New(slogplus.NewLevelRouter(map[slog.Level]slog.Handler{
+//		slog.LevelDebug: slogplus.New(os.Stdout, &slogplus.Options{Level: slog.LevelDebug}),
+//		slog.LevelWarn:  slogplus.New(os.Stderr, nil),
+//	}))
+func NewLevelRouter(routes map[slog.Level]slog.Handler) slog.Handler {
+	r := &levelRouter{}
+	for level := range routes {
+		if routes[level] != nil {
+			r.levels = append(r.levels, level)
+		}
+	}
+	slices.Sort(r.levels)
+	r.handlers = make([]slog.Handler, len(r.levels))
+	for i, level := range r.levels {
+		r.handlers[i] = routes[level]
+	}
+	return r
+}
+
+type levelRouter struct {
+	levels   []slog.Level // 升序
+	handlers []slog.Handler
+}
+
+// route 返回处理该级别的 Handler，没有时返回 nil
+func (r *levelRouter) route(level slog.Level) slog.Handler {
+	i, found := slices.BinarySearch(r.levels, level)
+	if !found {
+		i--
+	}
+	if i < 0 {
+		return nil
+	}
+	return r.handlers[i]
+}
+
+func (r *levelRouter) Enabled(ctx context.Context, level slog.Level) bool {
+	h := r.route(level)
+	return h != nil && h.Enabled(ctx, level)
+}
+
+func (r *levelRouter) Handle(ctx context.Context, rec slog.Record) error {
+	if h := r.route(rec.Level); h != nil {
+		return h.Handle(ctx, rec)
+	}
+	return nil
+}
+
+func (r *levelRouter) WithAttrs(attrs []slog.Attr) slog.Handler {
+	nr := &levelRouter{levels: r.levels, handlers: make([]slog.Handler, len(r.handlers))}
+	for i, h := range r.handlers {
+		nr.handlers[i] = h.WithAttrs(attrs)
+	}
+	return nr
+}
+
+func (r *levelRouter) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return r
+	}
+	nr := &levelRouter{levels: r.levels, handlers: make([]slog.Handler, len(r.handlers))}
+	for i, h := range r.handlers {
+		nr.handlers[i] = h.WithGroup(name)
+	}
+	return nr
+}
+
+// Flush 刷新所有实现了 Flusher 的 Handler
+func (r *levelRouter) Flush() error {
+	var errs []error
+	for _, h := range r.handlers {
+		if f, ok := h.(Flusher); ok {
+			if err := f.Flush(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,61 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLevelRouter(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	h := NewLevelRouter(map[slog.Level]slog.Handler{
+		slog.LevelDebug: New(&stdout, &Options{Level: slog.LevelDebug}),
+		slog.LevelWarn:  New(&stderr, &Options{Level: slog.LevelDebug}),
+	})
+	logger := slog.New(h).With("app", "demo")
+
+	logger.Debug("d")
+	logger.Info("i")
+	logger.Warn("w")
+	logger.Error("e")
+	logger.Log(context.Background(), slog.LevelError+4, "fatal")
+
+	for _, want := range []string{"msg=d", "msg=i"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("stdout 缺少 %s: %s", want, stdout.String())
+		}
+	}
+	if strings.Contains(stdout.String(), "msg=w") {
+		t.Errorf("WARN 不应该输出到 stdout: %s", stdout.String())
+	}
+	for _, want := range []string{"app=demo msg=w", "msg=e", "msg=fatal"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("stderr 缺少 %s: %s", want, stderr.String())
+		}
+	}
+
+	if h.Enabled(context.Background(), slog.LevelDebug-4) {
+		t.Error("低于所有路由且没有默认路由时应该丢弃")
+	}
+}
+
+func TestLevelRouter_Default(t *testing.T) {
+	var def, info bytes.Buffer
+	h := NewLevelRouter(map[slog.Level]slog.Handler{
+		DefaultRoute:   New(&def, &Options{Level: DefaultRoute}),
+		slog.LevelInfo: New(&info, nil),
+		slog.LevelWarn: nil,
+	})
+	logger := slog.New(h)
+	logger.Debug("trace")
+	logger.Warn("warn")
+
+	if !strings.Contains(def.String(), "msg=trace") || strings.Contains(def.String(), "msg=warn") {
+		t.Errorf("默认路由错误: %s", def.String())
+	}
+	if !strings.Contains(info.String(), "msg=warn") {
+		t.Errorf("nil 路由应该被忽略: %s", info.String())
+	}
+}
@@ -0,0 +1,137 @@
+package slogplus
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// SamplerOptions 定义采样 Handler 的配置
+type SamplerOptions struct {
+	// Tick 计数周期，默认 1 秒
+	Tick time.Duration
+
+	// First 每个周期内相同级别和消息的记录保留前 First 条，默认 100
+	First int
+
+	// Thereafter 超过 First 条后每 Thereafter 条保留 1 条，并带上 sampled=true 属性，默认 100
+	// 小于 0 时超过 First 条后全部丢弃
+	Thereafter int
+
+	// Stats 设置后报告每条记录的采样结果
+	Stats *PipelineStats
+}
+
+// SampledKey 是按比例保留的记录上附加的属性名
+const SampledKey = "sampled"
+
+// samplerBuckets 计数器的数量，不同的级别和消息哈希到同一计数器时会合并计数
+const samplerBuckets = 4096
+
+// NewSampler 返回按级别和消息采样的 Handler，用于防止热点循环中的日志刷屏:
+// 每个 Tick 内相同级别和消息的记录保留前 First 条，之后每 Thereafter 条保留 1 条，
+// 保留下来的代表记录带有 sampled=true，便于在查询时区分
+//
+//	h := slogplus.NewSampler(base, &slogplus.SamplerOptions{First: 10, Thereafter: 100})
+func NewSampler(next slog.Handler, opts *SamplerOptions) slog.Handler {
+	s := &sampler{counters: new([samplerBuckets]samplerCounter)}
+	if opts != nil {
+		s.opts = *opts
+	}
+	if s.opts.Tick <= 0 {
+		s.opts.Tick = time.Second
+	}
+	if s.opts.First <= 0 {
+		s.opts.First = 100
+	}
+	if s.opts.Thereafter == 0 {
+		s.opts.Thereafter = 100
+	}
+	return &samplerHandler{next: next, s: s}
+}
+
+// sampler 是所有派生 Handler 共享的计数器
+type sampler struct {
+	opts     SamplerOptions
+	counters *[samplerBuckets]samplerCounter
+	now      func() time.Time
+}
+
+// samplerCounter 是一个计数周期内的计数，resetAt 为周期结束的时间（UnixNano）
+type samplerCounter struct {
+	resetAt atomic.Int64
+	n       atomic.Int64
+}
+
+// inc 计数加一并返回当前周期内的计数
+func (c *samplerCounter) inc(now int64, tick time.Duration) int64 {
+	resetAt := c.resetAt.Load()
+	if now < resetAt {
+		return c.n.Add(1)
+	}
+	// 进入新周期，只有一个 goroutine 能成功重置，其余的继续累加
+	if c.resetAt.CompareAndSwap(resetAt, now+int64(tick)) {
+		c.n.Store(1)
+		return 1
+	}
+	return c.n.Add(1)
+}
+
+// sample 判断记录是否保留，marked 表示记录是按比例保留的
+func (s *sampler) sample(r slog.Record) (keep, marked bool) {
+	h := fnv.New32a()
+	h.Write([]byte{byte(r.Level >> 24), byte(r.Level >> 16), byte(r.Level >> 8), byte(r.Level)})
+	h.Write([]byte(r.Message))
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	n := s.counters[h.Sum32()%samplerBuckets].inc(now().UnixNano(), s.opts.Tick)
+
+	first := int64(s.opts.First)
+	if n <= first {
+		return true, false
+	}
+	if s.opts.Thereafter < 0 {
+		return false, false
+	}
+	if (n-first)%int64(s.opts.Thereafter) == 0 {
+		return true, true
+	}
+	return false, false
+}
+
+type samplerHandler struct {
+	next slog.Handler
+	s    *sampler
+}
+
+func (h *samplerHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *samplerHandler) Handle(ctx context.Context, r slog.Record) error {
+	keep, marked := h.s.sample(r)
+	h.s.opts.Stats.Sampled(keep)
+	if !keep {
+		return nil
+	}
+	if marked {
+		r = r.Clone()
+		r.AddAttrs(slog.Bool(SampledKey, true))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *samplerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplerHandler{next: h.next.WithAttrs(attrs), s: h.s}
+}
+
+func (h *samplerHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &samplerHandler{next: h.next.WithGroup(name), s: h.s}
+}
@@ -0,0 +1,53 @@
+package slogplus
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSampler(t *testing.T) {
+	var buf bytes.Buffer
+	stats := &PipelineStats{}
+	h := NewSampler(New(&buf, &Options{TimeFormat: "-"}), &SamplerOptions{First: 2, Thereafter: 3, Stats: stats}).(*samplerHandler)
+	now := time.Date(2025, 11, 14, 14, 0, 0, 0, time.UTC)
+	h.s.now = func() time.Time { return now }
+	logger := slog.New(h).With("app", "demo")
+
+	for i := 0; i < 8; i++ {
+		logger.Info("hot", "i", i)
+	}
+	logger.Warn("hot")
+
+	want := "- INFO app=demo msg=hot i=0\n" +
+		"- INFO app=demo msg=hot i=1\n" +
+		"- INFO app=demo msg=hot i=4 sampled=true\n" +
+		"- INFO app=demo msg=hot i=7 sampled=true\n" +
+		"- WARN app=demo msg=hot\n"
+	if got := buf.String(); got != want {
+		t.Errorf("采样结果错误:\n%s", got)
+	}
+	if snap := stats.Snapshot(); snap.Seen != 9 || snap.Kept != 5 {
+		t.Errorf("采样统计错误: %+v", snap)
+	}
+
+	buf.Reset()
+	now = now.Add(time.Second)
+	logger.Info("hot", "i", 8)
+	if got := buf.String(); got != "- INFO app=demo msg=hot i=8\n" {
+		t.Errorf("新周期应该重新计数: %q", got)
+	}
+}
+
+func TestSampler_DropThereafter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewSampler(New(&buf, nil), &SamplerOptions{First: 1, Thereafter: -1}))
+	for i := 0; i < 100; i++ {
+		logger.Info("loop")
+	}
+	if n := strings.Count(buf.String(), "msg=loop"); n != 1 {
+		t.Errorf("超过 First 后应该全部丢弃: %d", n)
+	}
+}
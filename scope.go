@@ -0,0 +1,103 @@
+package slogplus
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Scope 是 Push 添加的一组临时属性，Pop 时移除
+type Scope struct {
+	logger *slog.Logger
+	saved  slog.Logger
+	popped bool
+}
+
+// Push 就地为 logger 添加属性，直到返回的 Scope 调用 Pop 为止，
+// 用于在多层嵌套的批处理代码中标记当前阶段，而不必为每个阶段创建并传递新的 Logger:
+//
+//	scope := slogplus.Push(logger, "phase", "validate")
+//	defer scope.Pop()
+//
+// Push 会修改 logger 本身，同一个 logger 不能在多个 goroutine 之间并发使用 Push，
+// 也不能在 Push 期间被其它 goroutine 使用；共享的 Logger 应该使用 With
+func Push(logger *slog.Logger, args ...any) *Scope {
+	s := &Scope{logger: logger, saved: *logger}
+	*logger = *logger.With(args...)
+	return s
+}
+
+// Pop 将 logger 恢复为 Push 之前的状态，Push 之后添加的内层 Scope 也一并移除，
+// 重复调用 Pop 没有效果
+func (s *Scope) Pop() {
+	if s.popped {
+		return
+	}
+	s.popped = true
+	*s.logger = s.saved
+}
+
+// ambient 是 PushScope 添加的全局属性
+var ambient struct {
+	mu     sync.Mutex
+	layers []*ambientLayer
+	attrs  atomic.Pointer[[]slog.Attr] // 所有层展开后的属性，读取时无需加锁
+}
+
+type ambientLayer struct {
+	attrs []slog.Attr
+}
+
+// PushScope 添加进程级的全局属性，之后所有 Logger 输出的记录都会带上，直到调用返回的 pop 函数，
+// 适用于命令行工具和批处理任务标记当前阶段:
+//
+//	pop := slogplus.PushScope("phase", "migrate")
+//	defer pop()
+//
+// 多次 PushScope 的属性按添加顺序叠加，pop 只移除自己添加的属性，可以不按添加的相反顺序调用；
+// 属性位于 context 属性和记录自身的属性之前。服务端程序中请求级的属性应该使用 AppendCtx
+func PushScope(args ...any) (pop func()) {
+	r := slog.NewRecord(time.Time{}, 0, "", 0)
+	r.Add(args...)
+	layer := &ambientLayer{attrs: make([]slog.Attr, 0, r.NumAttrs())}
+	r.Attrs(func(a slog.Attr) bool {
+		layer.attrs = append(layer.attrs, a)
+		return true
+	})
+
+	ambient.mu.Lock()
+	ambient.layers = append(ambient.layers, layer)
+	storeAmbientLocked()
+	ambient.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ambient.mu.Lock()
+			defer ambient.mu.Unlock()
+			if i := slices.Index(ambient.layers, layer); i >= 0 {
+				ambient.layers = slices.Delete(ambient.layers, i, i+1)
+			}
+			storeAmbientLocked()
+		})
+	}
+}
+
+// storeAmbientLocked 展开所有层并保存，调用方需持有 ambient.mu
+func storeAmbientLocked() {
+	var attrs []slog.Attr
+	for _, l := range ambient.layers {
+		attrs = append(attrs, l.attrs...)
+	}
+	ambient.attrs.Store(&attrs)
+}
+
+// ambientAttrs 返回当前的全局属性
+func ambientAttrs() []slog.Attr {
+	if p := ambient.attrs.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
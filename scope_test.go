@@ -0,0 +1,70 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestPush(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, &Options{TimeFormat: "-"})).With("job", 7)
+
+	func() {
+		scope := Push(logger, "phase", "validate")
+		defer scope.Pop()
+		logger.Info("start")
+
+		inner := Push(logger, "item", 1)
+		logger.Info("item")
+		inner.Pop()
+		inner.Pop()
+		logger.Info("done")
+	}()
+	logger.Info("finished")
+
+	want := "- INFO job=7 phase=validate msg=start\n" +
+		"- INFO job=7 phase=validate item=1 msg=item\n" +
+		"- INFO job=7 phase=validate msg=done\n" +
+		"- INFO job=7 msg=finished\n"
+	if got := buf.String(); got != want {
+		t.Errorf("临时属性错误:\n%s", got)
+	}
+}
+
+func TestPush_PopOuter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, &Options{TimeFormat: "-"}))
+
+	outer := Push(logger, "phase", "load")
+	Push(logger, "item", 1)
+	outer.Pop()
+	logger.Info("after")
+
+	if got := buf.String(); got != "- INFO msg=after\n" {
+		t.Errorf("移除外层 Scope 应该同时移除内层: %q", got)
+	}
+}
+
+func TestPushScope(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, &Options{TimeFormat: "-"})).With("app", "cli")
+	other := slog.New(New(&buf, &Options{TimeFormat: "-"}))
+
+	popPhase := PushScope("phase", "startup")
+	popJob := PushScope(slog.String("job", "migrate"))
+	logger.InfoContext(AppendCtx(context.Background(), "step", 1), "running", "n", 2)
+	popPhase()
+	popPhase()
+	other.Info("other logger")
+	popJob()
+	logger.Info("done")
+
+	want := "- INFO app=cli msg=running phase=startup job=migrate step=1 n=2\n" +
+		"- INFO msg=other logger job=migrate\n" +
+		"- INFO app=cli msg=done\n"
+	if got := buf.String(); got != want {
+		t.Errorf("全局属性错误:\n%s", got)
+	}
+}
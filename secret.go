@@ -0,0 +1,47 @@
+package slogplus
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// SecretValue 包装敏感值，写入日志、fmt 格式化和 JSON 序列化时都输出 [REDACTED]
+// 只能通过 Reveal 取回原始值，使密码、令牌等字段可以放心地在结构体中传递和记录
+type SecretValue[T any] struct {
+	v T
+}
+
+// Secret 包装敏感值，输出时总是显示为 [REDACTED]
+//
+//	logger.Info("连接数据库", "dsn", slogplus.Secret(dsn))
+//	// msg=连接数据库 dsn=[REDACTED]
+//
+// Options.RevealSecrets 为 true 时输出原始值，仅用于本地调试
+func Secret[T any](v T) SecretValue[T] {
+	return SecretValue[T]{v}
+}
+
+// Reveal 返回原始值
+func (s SecretValue[T]) Reveal() T { return s.v }
+
+// LogValue 实现 slog.LogValuer
+func (s SecretValue[T]) LogValue() slog.Value { return slog.StringValue(RedactedValue) }
+
+// String 实现 fmt.Stringer
+func (s SecretValue[T]) String() string { return RedactedValue }
+
+// Format 实现 fmt.Formatter，%v、%+v、%#v 等所有动词都不会输出原始值
+func (s SecretValue[T]) Format(f fmt.State, verb rune) { f.Write([]byte(RedactedValue)) }
+
+// MarshalJSON 实现 json.Marshaler
+func (s SecretValue[T]) MarshalJSON() ([]byte, error) { return []byte(`"` + RedactedValue + `"`), nil }
+
+// MarshalText 实现 encoding.TextMarshaler
+func (s SecretValue[T]) MarshalText() ([]byte, error) { return []byte(RedactedValue), nil }
+
+// revealer 由所有 SecretValue 实现，用于 RevealSecrets 模式下取回原始值
+type revealer interface {
+	reveal() any
+}
+
+func (s SecretValue[T]) reveal() any { return s.v }
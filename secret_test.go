@@ -0,0 +1,52 @@
+package slogplus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSecret(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{TimeFormat: "-"})
+	token := Secret("s3cr3t")
+	logger.Info("x", "token", token, slog.Group("db", "dsn", Secret("postgres://u:p@h/db")))
+
+	want := "- INFO msg=x token=[REDACTED] db={dsn=[REDACTED]}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("输出错误:\n got %q\nwant %q", got, want)
+	}
+
+	buf.Reset()
+	NewLogger(&buf, &Options{Format: FormatJSON}).Info("x", "token", token)
+	if strings.Contains(buf.String(), "s3cr3t") || !strings.Contains(buf.String(), `"token":"[REDACTED]"`) {
+		t.Errorf("JSON 输出不应该包含原始值: %s", buf.String())
+	}
+
+	for _, s := range []string{fmt.Sprint(token), fmt.Sprintf("%#v", token), fmt.Sprintf("%+v", struct{ T SecretValue[string] }{token})} {
+		if strings.Contains(s, "s3cr3t") {
+			t.Errorf("fmt 输出不应该包含原始值: %s", s)
+		}
+	}
+	b, _ := json.Marshal(map[string]any{"token": token})
+	if string(b) != `{"token":"[REDACTED]"}` {
+		t.Errorf("json.Marshal 输出错误: %s", b)
+	}
+	if token.Reveal() != "s3cr3t" {
+		t.Errorf("Reveal 应该返回原始值: %s", token.Reveal())
+	}
+}
+
+func TestSecret_Reveal(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{TimeFormat: "-", RevealSecrets: true, RedactKeys: []string{"password"}})
+	logger.With("api_key", Secret("k1")).Info("x", "port", Secret(5432), "password", Secret("p"), slog.Group("db", "dsn", Secret("d")))
+
+	want := "- INFO api_key=k1 msg=x port=5432 password=[REDACTED] db={dsn=d}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("输出错误:\n got %q\nwant %q", got, want)
+	}
+}
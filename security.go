@@ -0,0 +1,213 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// SinkSecurity 是所有 HTTP/TCP 输出共用的安全配置
+// 包括 TLS（含双向认证）、Bearer/Basic 认证以及代理
+type SinkSecurity struct {
+	// TLS 是否启用 TLS，设置了 CAFile/CertFile 时自动启用
+	TLS bool
+
+	// CAFile 用于校验服务端证书的 CA 文件（PEM），为空则使用系统根证书
+	CAFile string
+
+	// CertFile、KeyFile 客户端证书和私钥（PEM），同时设置时启用双向认证
+	CertFile string
+	KeyFile  string
+
+	// ServerName 覆盖 TLS 校验时使用的服务端名称
+	ServerName string
+
+	// InsecureSkipVerify 跳过服务端证书校验，仅用于测试
+	InsecureSkipVerify bool
+
+	// BearerToken 设置后添加 "Authorization: Bearer <token>" 请求头
+	BearerToken string
+
+	// Username、Password 设置后使用 Basic 认证
+	// 同时设置 BearerToken 时优先使用 BearerToken
+	Username string
+	Password string
+
+	// Proxy 代理地址，例如 "http://proxy:3128"
+	// 为空时 HTTP 输出使用环境变量中的代理设置，TCP 输出直连
+	Proxy string
+}
+
+// tlsEnabled 判断是否需要 TLS
+func (s *SinkSecurity) tlsEnabled() bool {
+	return s != nil && (s.TLS || s.CAFile != "" || s.CertFile != "" || s.InsecureSkipVerify)
+}
+
+// TLSConfig 根据配置构建 *tls.Config，未启用 TLS 时返回 nil
+func (s *SinkSecurity) TLSConfig() (*tls.Config, error) {
+	if !s.tlsEnabled() {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		ServerName:         s.ServerName,
+		InsecureSkipVerify: s.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if s.CAFile != "" {
+		pem, err := os.ReadFile(s.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("slogplus: 读取 CA 文件失败: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("slogplus: CA 文件 %s 中没有有效证书", s.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if s.CertFile != "" || s.KeyFile != "" {
+		if s.CertFile == "" || s.KeyFile == "" {
+			return nil, errors.New("slogplus: CertFile 和 KeyFile 必须同时设置")
+		}
+		cert, err := tls.LoadX509KeyPair(s.CertFile, s.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("slogplus: 加载客户端证书失败: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// HTTPClient 构建应用了 TLS 和代理配置的 HTTP 客户端
+// 认证信息需要通过 Authorize 添加到每个请求上
+func (s *SinkSecurity) HTTPClient(timeout time.Duration) (*http.Client, error) {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	if s != nil {
+		tlsCfg, err := s.TLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		if tlsCfg != nil {
+			tr.TLSClientConfig = tlsCfg
+		}
+		if s.Proxy != "" {
+			u, err := url.Parse(s.Proxy)
+			if err != nil {
+				return nil, fmt.Errorf("slogplus: 代理地址无效: %w", err)
+			}
+			tr.Proxy = http.ProxyURL(u)
+		}
+	}
+	return &http.Client{Transport: tr, Timeout: timeout}, nil
+}
+
+// Authorize 为请求添加认证头
+func (s *SinkSecurity) Authorize(req *http.Request) {
+	if s == nil {
+		return
+	}
+	switch {
+	case s.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+s.BearerToken)
+	case s.Username != "" || s.Password != "":
+		req.SetBasicAuth(s.Username, s.Password)
+	}
+}
+
+// DialContext 建立 TCP 连接，按配置经过 HTTP CONNECT 代理并进行 TLS 握手
+func (s *SinkSecurity) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	var d net.Dialer
+
+	var conn net.Conn
+	var err error
+	if s != nil && s.Proxy != "" {
+		conn, err = s.dialProxy(ctx, &d, network, addr)
+	} else {
+		conn, err = d.DialContext(ctx, network, addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tlsCfg, err := s.TLSConfig()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if tlsCfg == nil {
+		return conn, nil
+	}
+	if tlsCfg.ServerName == "" {
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			tlsCfg.ServerName = host
+		}
+	}
+	tc := tls.Client(conn, tlsCfg)
+	if err := tc.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tc, nil
+}
+
+// dialProxy 通过 HTTP CONNECT 代理建立隧道
+func (s *SinkSecurity) dialProxy(ctx context.Context, d *net.Dialer, network, addr string) (net.Conn, error) {
+	u, err := url.Parse(s.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("slogplus: 代理地址无效: %w", err)
+	}
+	if u.Scheme != "http" {
+		return nil, fmt.Errorf("slogplus: 不支持的代理协议 %q", u.Scheme)
+	}
+
+	conn, err := d.DialContext(ctx, network, u.Host)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if u.User != nil {
+		pass, _ := u.User.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(u.User.Username() + ":" + pass))
+		req.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("slogplus: 代理 CONNECT 失败: %s", resp.Status)
+	}
+	return conn, nil
+}
@@ -0,0 +1,54 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSinkSecurity_HTTPClient(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	sec := &SinkSecurity{CAFile: caFile, BearerToken: "abc"}
+	client, err := sec.HTTPClient(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, nil)
+	sec.Authorize(req)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("使用 CA 文件应该能够建立 TLS 连接: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotAuth != "Bearer abc" {
+		t.Errorf("应该携带 Bearer 认证头: %q", gotAuth)
+	}
+}
+
+func TestSinkSecurity_Invalid(t *testing.T) {
+	if _, err := (&SinkSecurity{CertFile: "cert.pem"}).TLSConfig(); err == nil {
+		t.Errorf("只设置 CertFile 应该返回错误")
+	}
+	if cfg, err := (*SinkSecurity)(nil).TLSConfig(); cfg != nil || err != nil {
+		t.Errorf("未配置时不应该启用 TLS")
+	}
+}
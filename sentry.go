@@ -0,0 +1,629 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SentryOptions 定义 Sentry 错误上报的配置
+type SentryOptions struct {
+	// DSN Sentry 项目的 DSN，例如 https://<key>@o0.ingest.sentry.io/<project>，必须设置
+	DSN string
+
+	// Level 上报的最低级别，默认为 Error
+	Level slog.Leveler
+
+	// Environment、Release、ServerName 事件的环境、版本和主机名
+	Environment string
+	Release     string
+	ServerName  string
+
+	// TagKeys 作为 tag 上报的属性名（分组属性使用 "group.key"），可在 Sentry 中搜索和聚合
+	// 其余属性作为 extra 上报
+	TagKeys []string
+
+	// Redact 上报前的脱敏配置，使用其中的 AllowKeys、RedactKeys、Masks、PII、HMAC、Redactor 和 MaxValueLen，
+	// 对消息、属性和错误信息生效，RevealSecrets 不生效；通常与被包装的 Handler 使用同一个 Options，
+	// 为 nil 时事件内容原样发送到 Sentry
+	Redact *Options
+
+	// QueueSize 等待发送的最大事件数，队列满时丢弃新事件，默认 100
+	QueueSize int
+
+	// Timeout 单次请求的超时时间，默认 10 秒
+	Timeout time.Duration
+
+	// Retry 重试策略，为 nil 时使用默认策略
+	Retry *RetryPolicy
+
+	// Security TLS 和代理配置
+	Security *SinkSecurity
+
+	// Codec 请求体的压缩算法，例如 Gzip，默认不压缩
+	Codec Codec
+
+	// OnError 发送失败（重试耗尽）时的回调
+	OnError func(err error)
+
+	// Stats 设置后报告发送队列深度和发送延迟，名称为 "sentry"
+	Stats *PipelineStats
+}
+
+// SentryHandler 将所有记录交给被包装的 Handler，同时把 Error 及以上的记录作为事件上报到 Sentry
+// 事件包括消息、属性（tag 和 extra）、记录中的错误以及调用栈，
+// 错误实现了 Callers() []uintptr 时使用错误自带的调用栈，否则使用记录的调用位置
+type SentryHandler struct {
+	next   slog.Handler
+	c      *sentryClient
+	groups []string    // WithGroup 添加的分组，用于匹配脱敏规则
+	prefix string      // WithGroup 产生的键前缀
+	attrs  []slog.Attr // WithAttrs 预设的属性，键已加上前缀
+}
+
+// sentryClient 是所有派生 Handler 共享的发送队列
+type sentryClient struct {
+	opts     SentryOptions
+	client   *http.Client
+	endpoint string
+	auth     string
+	tags     map[string]bool
+	rw       *attrRewriter // Redact 的改写规则，没有配置时为 nil
+
+	queue   chan *sentryEvent
+	flushCh chan chan error
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+
+	dropped    atomic.Int64
+	lastErr    error
+	unregister func()
+}
+
+// ErrSentryQueueFull 表示 Sentry 发送队列已满，事件被丢弃
+var ErrSentryQueueFull = errors.New("slogplus: Sentry 发送队列已满")
+
+// NewSentryHandler 创建 Sentry 上报 Handler，next 为 nil 时只上报不输出，使用完毕后需要调用 Close
+func NewSentryHandler(next slog.Handler, opts *SentryOptions) (*SentryHandler, error) {
+	c := &sentryClient{}
+	if opts != nil {
+		c.opts = *opts
+	}
+	o := &c.opts
+	endpoint, key, err := parseSentryDSN(o.DSN)
+	if err != nil {
+		return nil, err
+	}
+	c.endpoint = endpoint
+	c.auth = "Sentry sentry_version=7, sentry_client=slogplus/1.0, sentry_key=" + key
+	if o.QueueSize <= 0 {
+		o.QueueSize = 100
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 10 * time.Second
+	}
+	if o.Retry == nil {
+		o.Retry = &RetryPolicy{}
+	}
+	c.tags = make(map[string]bool, len(o.TagKeys))
+	for _, k := range o.TagKeys {
+		c.tags[k] = true
+	}
+
+	if o.Redact != nil {
+		if c.rw = newAttrRewriter(o.Redact); c.rw != nil {
+			// 上报到第三方的内容不还原 Secret
+			c.rw.reveal = false
+		}
+	}
+
+	client, err := o.Security.HTTPClient(o.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	c.client = client
+
+	c.queue = make(chan *sentryEvent, o.QueueSize)
+	c.flushCh = make(chan chan error)
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	c.unregister = RegisterFlusher(c)
+	go c.loop()
+
+	return &SentryHandler{next: next, c: c}, nil
+}
+
+// parseSentryDSN 解析 DSN，返回 envelope 接口地址和公钥
+// DSN 格式为 {scheme}://{key}@{host}{/path}/{project}
+func parseSentryDSN(dsn string) (endpoint, key string, err error) {
+	if dsn == "" {
+		return "", "", errors.New("slogplus: 必须设置 Sentry DSN")
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", fmt.Errorf("slogplus: 无效的 Sentry DSN: %w", err)
+	}
+	key = u.User.Username()
+	i := strings.LastIndexByte(u.Path, '/')
+	if key == "" || u.Host == "" || i < 0 || u.Path[i+1:] == "" {
+		return "", "", errors.New("slogplus: 无效的 Sentry DSN，缺少公钥或项目 ID")
+	}
+	endpoint = u.Scheme + "://" + u.Host + u.Path[:i] + "/api/" + u.Path[i+1:] + "/envelope/"
+	return endpoint, key, nil
+}
+
+// Enabled 实现 slog.Handler，被包装的 Handler 或 Sentry 任一需要时返回 true
+func (h *SentryHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.c.level() || h.next != nil && h.next.Enabled(ctx, level)
+}
+
+// Handle 将记录交给被包装的 Handler，达到上报级别时放入发送队列，不会阻塞
+func (h *SentryHandler) Handle(ctx context.Context, r slog.Record) error {
+	var err error
+	if h.next != nil && h.next.Enabled(ctx, r.Level) {
+		err = h.next.Handle(ctx, r)
+	}
+	if r.Level < h.c.level() {
+		return err
+	}
+
+	select {
+	case h.c.queue <- h.event(withCtxAttrs(ctx, r)):
+		h.c.opts.Stats.QueueDepth("sentry", len(h.c.queue))
+	default:
+		h.c.dropped.Add(1)
+		err = errors.Join(err, ErrSentryQueueFull)
+	}
+	return err
+}
+
+// level 返回上报的最低级别
+func (c *sentryClient) level() slog.Level {
+	if c.opts.Level != nil {
+		return c.opts.Level.Level()
+	}
+	return slog.LevelError
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *SentryHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	nh := *h
+	if h.next != nil {
+		nh.next = h.next.WithAttrs(attrs)
+	}
+	nh.attrs = slices.Clip(h.attrs)
+	for _, a := range attrs {
+		a, ok := h.c.redact(h.groups, a)
+		if !ok {
+			continue
+		}
+		a.Key = h.prefix + a.Key
+		nh.attrs = append(nh.attrs, a)
+	}
+	return &nh
+}
+
+// WithGroup 实现 slog.Handler，分组属性的键为 group.key
+func (h *SentryHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	nh := *h
+	if h.next != nil {
+		nh.next = h.next.WithGroup(name)
+	}
+	nh.groups = append(slices.Clip(h.groups), name)
+	nh.prefix = h.prefix + name + "."
+	return &nh
+}
+
+// Flush 立即发送队列中的所有事件
+func (h *SentryHandler) Flush() error {
+	return h.c.Flush()
+}
+
+// Dropped 返回因队列已满而丢弃的事件数
+func (h *SentryHandler) Dropped() int64 {
+	return h.c.dropped.Load()
+}
+
+// Close 发送剩余事件并停止上报，不会关闭被包装的 Handler
+func (h *SentryHandler) Close() error {
+	var err error
+	h.c.once.Do(func() {
+		h.c.unregister()
+		close(h.c.stop)
+		<-h.c.done
+		err = h.c.lastErr
+	})
+	return err
+}
+
+// sentryEvent 是 Sentry 事件中用到的字段
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Logger      string            `json:"logger"`
+	Platform    string            `json:"platform"`
+	Message     *sentryMessage    `json:"message,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]any    `json:"extra,omitempty"`
+	Fingerprint []string          `json:"fingerprint,omitempty"`
+	Exception   *sentryValues     `json:"exception,omitempty"`
+	Threads     *sentryValues     `json:"threads,omitempty"`
+}
+
+type sentryMessage struct {
+	Formatted string `json:"formatted"`
+}
+
+type sentryValues struct {
+	Values []sentryValue `json:"values"`
+}
+
+// sentryValue 是一个异常或线程
+type sentryValue struct {
+	Type       string            `json:"type,omitempty"`
+	Value      string            `json:"value,omitempty"`
+	Current    bool              `json:"current,omitempty"`
+	Stacktrace *sentryStacktrace `json:"stacktrace,omitempty"`
+}
+
+type sentryStacktrace struct {
+	Frames []sentryFrame `json:"frames"`
+}
+
+type sentryFrame struct {
+	Function string `json:"function,omitempty"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	AbsPath  string `json:"abs_path,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+	InApp    bool   `json:"in_app"`
+}
+
+// event 将记录转换为 Sentry 事件，第一个 error 类型的属性作为异常上报
+func (h *SentryHandler) event(r slog.Record) *sentryEvent {
+	o := &h.c.opts
+	e := &sentryEvent{
+		EventID:     newSentryEventID(),
+		Timestamp:   r.Time.UTC().Format(time.RFC3339Nano),
+		Level:       sentryLevel(r.Level),
+		Logger:      "slogplus",
+		Platform:    "go",
+		Environment: o.Environment,
+		Release:     o.Release,
+		ServerName:  o.ServerName,
+		Tags:        make(map[string]string),
+		Extra:       make(map[string]any),
+	}
+	if r.Time.IsZero() {
+		e.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	if r.Message != "" {
+		e.Message = &sentryMessage{Formatted: h.c.text(r.Message)}
+	}
+
+	var err error
+	add := func(a slog.Attr) {
+		h.c.addAttr(e, &err, "", a)
+	}
+	for _, a := range h.attrs {
+		add(a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		if a, ok := h.c.redact(h.groups, a); ok {
+			a.Key = h.prefix + a.Key
+			add(a)
+		}
+		return true
+	})
+
+	pcs := []uintptr{r.PC}
+	var c interface{ Callers() []uintptr }
+	if errors.As(err, &c) {
+		pcs = c.Callers()
+	}
+	stack := sentryStack(pcs)
+
+	if err != nil {
+		e.Exception = &sentryValues{Values: []sentryValue{{
+			Type:       fmt.Sprintf("%T", rootError(err)),
+			Value:      h.c.text(err.Error()),
+			Stacktrace: stack,
+		}}}
+		e.Fingerprint = []string{ErrorFingerprint(err, r.PC)}
+	} else if stack != nil {
+		e.Threads = &sentryValues{Values: []sentryValue{{Current: true, Stacktrace: stack}}}
+	}
+	return e
+}
+
+// redact 按 Redact 配置改写属性，返回 false 表示丢弃
+// 错误值保留原样用于生成异常信息，只对键应用 AllowKeys 和 RedactKeys，错误信息在生成事件时脱敏
+func (c *sentryClient) redact(groups []string, a slog.Attr) (slog.Attr, bool) {
+	rw := c.rw
+	if rw == nil {
+		return a, true
+	}
+	if !isErrorAttr(a) {
+		return rw.attr(groups, a)
+	}
+	if rw.redact.match(groups, a.Key) {
+		return slog.String(a.Key, RedactedValue), true
+	}
+	return a, len(rw.allow) == 0 || rw.allow.match(groups, a.Key)
+}
+
+// text 对消息和错误信息应用 Redact 中的正则掩码和个人信息检测
+func (c *sentryClient) text(s string) string {
+	if c.rw == nil {
+		return s
+	}
+	return c.rw.text(s)
+}
+
+// isErrorAttr 判断属性是否会被 addAttr 作为异常上报
+func isErrorAttr(a slog.Attr) bool {
+	switch a.Value.Kind() {
+	case slog.KindLogValuer:
+		_, ok := a.Value.Any().(errorValue)
+		return ok
+	case slog.KindAny:
+		x, ok := a.Value.Any().(error)
+		return ok && x != nil
+	}
+	return false
+}
+
+// addAttr 将属性加入事件，分组展开为 group.key
+func (c *sentryClient) addAttr(e *sentryEvent, err *error, prefix string, a slog.Attr) {
+	if x, ok := a.Value.Any().(errorValue); ok && a.Value.Kind() == slog.KindLogValuer && *err == nil {
+		*err = x.error
+		return
+	}
+	v := a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	key := prefix + a.Key
+	if v.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix = key + "."
+		}
+		for _, ga := range v.Group() {
+			c.addAttr(e, err, prefix, ga)
+		}
+		return
+	}
+	if x, ok := v.Any().(error); ok && v.Kind() == slog.KindAny && *err == nil && x != nil {
+		*err = x
+		return
+	}
+	if c.tags[key] {
+		e.Tags[key] = v.String()
+		return
+	}
+	e.Extra[key] = sentryExtra(v)
+}
+
+// sentryExtra 将属性值转换为可 JSON 编码的值
+func sentryExtra(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindInt64:
+		return v.Int64()
+	case slog.KindUint64:
+		return v.Uint64()
+	case slog.KindFloat64:
+		f := v.Float64()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return strconv.FormatFloat(f, 'g', -1, 64)
+		}
+		return f
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindDuration:
+		return v.Duration().String()
+	default:
+		if isNilValue(v) {
+			return nil
+		}
+		return fmt.Sprint(v.Any())
+	}
+}
+
+// sentryLevel 将 slog 级别映射为 Sentry 级别
+func sentryLevel(l slog.Level) string {
+	switch {
+	case l >= slog.LevelError+4:
+		return "fatal"
+	case l >= slog.LevelError:
+		return "error"
+	case l >= slog.LevelWarn:
+		return "warning"
+	case l >= slog.LevelInfo:
+		return "info"
+	default:
+		return "debug"
+	}
+}
+
+// sentryStack 将调用栈转换为 Sentry 栈帧，Sentry 要求最外层的调用在前
+func sentryStack(pcs []uintptr) *sentryStacktrace {
+	if len(pcs) == 0 || pcs[0] == 0 {
+		return nil
+	}
+	var frames []sentryFrame
+	it := runtime.CallersFrames(pcs)
+	for {
+		f, more := it.Next()
+		if f.Function != "" || f.File != "" {
+			module, function := splitFuncName(f.Function)
+			frames = append(frames, sentryFrame{
+				Function: function,
+				Module:   module,
+				Filename: trimSourcePath(f.File),
+				AbsPath:  f.File,
+				Lineno:   f.Line,
+				InApp:    !strings.HasPrefix(f.Function, "runtime.") && !strings.Contains(f.File, "/pkg/mod/"),
+			})
+		}
+		if !more {
+			break
+		}
+	}
+	if len(frames) == 0 {
+		return nil
+	}
+	slices.Reverse(frames)
+	return &sentryStacktrace{Frames: frames}
+}
+
+// splitFuncName 将 "github.com/a/b.(*T).M" 拆分为包路径和函数名
+func splitFuncName(name string) (module, function string) {
+	i := strings.LastIndexByte(name, '/')
+	j := strings.IndexByte(name[i+1:], '.')
+	if j < 0 {
+		return "", name
+	}
+	return name[:i+1+j], name[i+2+j:]
+}
+
+// trimSourcePath 只保留文件所在目录和文件名
+func trimSourcePath(file string) string {
+	i := strings.LastIndexByte(file, '/')
+	if i < 0 {
+		return file
+	}
+	if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+		return file[j+1:]
+	}
+	return file
+}
+
+// newSentryEventID 生成 32 位十六进制的事件 ID
+func newSentryEventID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Flush 实现 Flusher
+func (c *sentryClient) Flush() error {
+	ch := make(chan error, 1)
+	select {
+	case c.flushCh <- ch:
+		return <-ch
+	case <-c.done:
+		return nil
+	}
+}
+
+func (c *sentryClient) loop() {
+	defer close(c.done)
+	send := func(e *sentryEvent) error {
+		start := time.Now()
+		err := c.send(e)
+		c.opts.Stats.Latency("sentry", time.Since(start))
+		if err != nil && c.opts.OnError != nil {
+			c.opts.OnError(err)
+		}
+		return err
+	}
+	drain := func() error {
+		var errs []error
+		for {
+			select {
+			case e := <-c.queue:
+				errs = append(errs, send(e))
+			default:
+				return errors.Join(errs...)
+			}
+		}
+	}
+
+	for {
+		select {
+		case e := <-c.queue:
+			send(e)
+		case ch := <-c.flushCh:
+			ch <- drain()
+		case <-c.stop:
+			c.lastErr = drain()
+			return
+		}
+	}
+}
+
+// send 以 envelope 格式发送一个事件
+func (c *sentryClient) send(e *sentryEvent) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	body.WriteString(`{"event_id":"`)
+	body.WriteString(e.EventID)
+	body.WriteString(`","sent_at":"`)
+	body.WriteString(time.Now().UTC().Format(time.RFC3339Nano))
+	body.WriteString("\"}\n{\"type\":\"event\",\"length\":")
+	body.WriteString(strconv.Itoa(len(payload)))
+	body.WriteString("}\n")
+	body.Write(payload)
+	body.WriteByte('\n')
+	envelope, encoding, err := compress(c.opts.Codec, body.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return c.opts.Retry.Do(context.Background(), func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(envelope))
+		if err != nil {
+			return Permanent(err)
+		}
+		req.Header.Set("Content-Type", "application/x-sentry-envelope")
+		req.Header.Set("X-Sentry-Auth", c.auth)
+		if encoding != "" {
+			req.Header.Set("Content-Encoding", encoding)
+		}
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return &StatusError{Code: resp.StatusCode, Status: resp.Status}
+		}
+		return nil
+	})
+}
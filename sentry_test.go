@@ -0,0 +1,176 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/IAmMrChen/slogplus/sinktest"
+)
+
+func TestSentryHandler(t *testing.T) {
+	c := sinktest.NewHTTPCollector(t)
+	dsn := strings.Replace(c.URL, "://", "://pubkey@", 1) + "/42"
+
+	var out bytes.Buffer
+	h, err := NewSentryHandler(New(&out, &Options{Format: FormatJSON}), &SentryOptions{
+		DSN:         dsn,
+		Environment: "prod",
+		TagKeys:     []string{"req.tenant"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	logger := slog.New(h).With("service", "api").WithGroup("req")
+	logger.Info("started", "tenant", "acme")
+	logger.Error("查询失败", "tenant", "acme", "err", errors.New("timeout"), "retries", 3)
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := strings.Count(out.String(), "\n"); n != 2 {
+		t.Errorf("所有记录都应该写入被包装的 Handler: %d", n)
+	}
+	batches := c.Batches()
+	if len(batches) != 1 {
+		t.Fatalf("只应该上报 Error 记录: %d", len(batches))
+	}
+	b := batches[0]
+	if b.Path != "/api/42/envelope/" {
+		t.Errorf("envelope 地址错误: %s", b.Path)
+	}
+	if auth := b.Header.Get("X-Sentry-Auth"); !strings.Contains(auth, "sentry_key=pubkey") {
+		t.Errorf("认证头错误: %s", auth)
+	}
+
+	lines := b.Lines()
+	if len(lines) != 3 {
+		t.Fatalf("envelope 应该有 3 行: %q", lines)
+	}
+	var e struct {
+		Level       string `json:"level"`
+		Environment string `json:"environment"`
+		Message     struct{ Formatted string }
+		Tags        map[string]string `json:"tags"`
+		Extra       map[string]any    `json:"extra"`
+		Fingerprint []string          `json:"fingerprint"`
+		Exception   struct {
+			Values []struct {
+				Type       string `json:"type"`
+				Value      string `json:"value"`
+				Stacktrace struct {
+					Frames []struct {
+						Function string `json:"function"`
+						Lineno   int    `json:"lineno"`
+					} `json:"frames"`
+				} `json:"stacktrace"`
+			} `json:"values"`
+		} `json:"exception"`
+	}
+	if err := json.Unmarshal([]byte(lines[2]), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Level != "error" || e.Environment != "prod" || e.Message.Formatted != "查询失败" {
+		t.Errorf("事件字段错误: %+v", e)
+	}
+	if e.Tags["req.tenant"] != "acme" {
+		t.Errorf("tag 错误: %v", e.Tags)
+	}
+	if e.Extra["service"] != "api" || e.Extra["req.retries"] != float64(3) {
+		t.Errorf("extra 错误: %v", e.Extra)
+	}
+	if len(e.Exception.Values) != 1 || e.Exception.Values[0].Value != "timeout" || e.Exception.Values[0].Type != "*errors.errorString" {
+		t.Fatalf("异常错误: %+v", e.Exception)
+	}
+	frames := e.Exception.Values[0].Stacktrace.Frames
+	if len(frames) == 0 || frames[len(frames)-1].Function != "TestSentryHandler" {
+		t.Errorf("调用栈错误: %+v", frames)
+	}
+	if len(e.Fingerprint) != 1 || e.Fingerprint[0] == "" {
+		t.Errorf("应该带上错误指纹: %v", e.Fingerprint)
+	}
+}
+
+func TestSentryHandler_Retry(t *testing.T) {
+	c := sinktest.NewHTTPCollector(t)
+	c.FailNext(1, 503)
+	dsn := strings.Replace(c.URL, "://", "://k@", 1) + "/7"
+
+	h, err := NewSentryHandler(nil, &SentryOptions{DSN: dsn, Level: slog.LevelWarn, Retry: &RetryPolicy{InitialBackoff: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Enabled(nil, slog.LevelInfo) {
+		t.Error("没有被包装的 Handler 时只应该启用上报级别")
+	}
+	slog.New(h).Warn("磁盘空间不足")
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Batches()) != 1 || c.Requests() != 2 {
+		t.Errorf("应该重试后上报: %d/%d", len(c.Batches()), c.Requests())
+	}
+	if !strings.Contains(c.Batches()[0].Lines()[2], `"threads"`) {
+		t.Error("没有错误时应该以线程上报调用栈")
+	}
+}
+
+func TestParseSentryDSN(t *testing.T) {
+	endpoint, key, err := parseSentryDSN("https://abc@o1.ingest.sentry.io/sub/123")
+	if err != nil || key != "abc" || endpoint != "https://o1.ingest.sentry.io/sub/api/123/envelope/" {
+		t.Errorf("解析错误: %s %s %v", endpoint, key, err)
+	}
+	for _, dsn := range []string{"", "https://o1.ingest.sentry.io/123", "https://abc@host/"} {
+		if _, _, err := parseSentryDSN(dsn); err == nil {
+			t.Errorf("%q 应该返回错误", dsn)
+		}
+	}
+}
+
+func TestSentryHandler_Redact(t *testing.T) {
+	c := sinktest.NewHTTPCollector(t)
+	dsn := strings.Replace(c.URL, "://", "://pubkey@", 1) + "/42"
+
+	opts := &Options{RedactKeys: []string{"password"}, PII: &PII{}}
+	h, err := NewSentryHandler(New(io.Discard, opts), &SentryOptions{DSN: dsn, Redact: opts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	logger := slog.New(h).With("password", "hunter2").WithGroup("req")
+	logger.Error("通知 bob@example.com 失败", "password", "s3cret", "to", "bob@example.com",
+		"err", errors.New("smtp: bob@example.com rejected"))
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	batches := c.Batches()
+	if len(batches) != 1 {
+		t.Fatalf("应该上报一个事件: %d", len(batches))
+	}
+	event := batches[0].Lines()[2]
+	for _, secret := range []string{"hunter2", "s3cret", "bob@example.com"} {
+		if strings.Contains(event, secret) {
+			t.Errorf("上报的事件不应该包含 %q: %s", secret, event)
+		}
+	}
+	var e struct {
+		Exception struct {
+			Values []struct {
+				Type string `json:"type"`
+			}
+		}
+	}
+	if err := json.Unmarshal([]byte(event), &e); err != nil || len(e.Exception.Values) != 1 {
+		t.Errorf("错误仍然应该作为异常上报: %v %s", err, event)
+	}
+}
@@ -0,0 +1,36 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// NotifyShutdown 返回一个在收到退出信号时取消的 context
+// 收到信号后先刷新所有已注册的输出（FlushAll），再取消 context，
+// 避免 Kubernetes 终止 Pod 时丢失最后的日志。
+// signals 为空时监听 SIGTERM 和 SIGINT，stop 用于停止监听并释放资源
+func NotifyShutdown(ctx context.Context, signals ...os.Signal) (_ context.Context, stop context.CancelFunc) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGTERM, os.Interrupt}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+
+	go func() {
+		select {
+		case <-ch:
+			FlushAll()
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(ch)
+	}()
+
+	return ctx, cancel
+}
@@ -0,0 +1,33 @@
+//go:build unix && !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNotifyShutdown(t *testing.T) {
+	flushed := make(chan struct{}, 1)
+	defer RegisterFlusher(FlusherFunc(func() error {
+		flushed <- struct{}{}
+		return nil
+	}))()
+
+	ctx, stop := NotifyShutdown(context.Background(), syscall.SIGUSR1)
+	defer stop()
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	select {
+	case <-ctx.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("收到信号后 context 应该被取消")
+	}
+	select {
+	case <-flushed:
+	default:
+		t.Errorf("取消 context 之前应该已经刷新输出")
+	}
+}
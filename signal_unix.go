@@ -0,0 +1,82 @@
+//go:build unix && !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// HandleSignals 每次收到 SIGHUP 时调用 ReloadAll，重新打开所有日志文件并执行注册的重新加载函数，
+// 例如 SetupFromEnv 重新读取 LOG_LEVEL、config.Watch 重新加载配置文件，
+// 配合 logrotate 的 postrotate 脚本（kill -HUP）使用；失败时通过默认 Logger 输出错误
+// 返回的函数用于停止监听
+func HandleSignals() (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ch:
+				if err := ReloadAll(); err != nil {
+					slog.Error("slogplus: 收到 SIGHUP 后重新加载失败", "error", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
+
+// SwitchFormatOnSignal 每次收到 SIGUSR2 时在 formats 之间循环切换 v 的格式
+// formats 为空时在当前格式和 FormatConsole 之间切换，
+// 便于在线上调试时临时把服务切换为便于阅读的格式
+// 返回的函数用于停止监听
+func SwitchFormatOnSignal(v *FormatVar, formats ...Format) (stop func()) {
+	if len(formats) == 0 {
+		cur := v.Format()
+		if cur == FormatConsole {
+			cur = FormatText
+		}
+		formats = []Format{cur, FormatConsole}
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR2)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ch:
+				v.Set(nextFormat(v.Format(), formats))
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
+
+// nextFormat 返回 formats 中 cur 的下一个格式
+func nextFormat(cur Format, formats []Format) Format {
+	for i, f := range formats {
+		if f == cur {
+			return formats[(i+1)%len(formats)]
+		}
+	}
+	return formats[0]
+}
@@ -0,0 +1,50 @@
+//go:build unix && !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSwitchFormatOnSignal(t *testing.T) {
+	fv := NewFormatVar(FormatJSON)
+	stop := SwitchFormatOnSignal(fv)
+	defer stop()
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	deadline := time.Now().Add(2 * time.Second)
+	for fv.Format() != FormatConsole && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if fv.Format() != FormatConsole {
+		t.Fatalf("收到 SIGUSR2 后应该切换为控制台格式: %s", fv.Format())
+	}
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	deadline = time.Now().Add(2 * time.Second)
+	for fv.Format() != FormatJSON && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if fv.Format() != FormatJSON {
+		t.Errorf("再次收到 SIGUSR2 后应该切换回 JSON 格式: %s", fv.Format())
+	}
+}
+
+func TestHandleSignals(t *testing.T) {
+	reloaded := make(chan struct{}, 1)
+	defer RegisterReloader(func() error {
+		reloaded <- struct{}{}
+		return nil
+	})()
+	stop := HandleSignals()
+	defer stop()
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+	select {
+	case <-reloaded:
+	case <-time.After(2 * time.Second):
+		t.Fatal("收到 SIGHUP 后应该调用 ReloadAll")
+	}
+}
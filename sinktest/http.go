@@ -0,0 +1,246 @@
+// Package sinktest 提供测试日志输出（sink）用的假采集端
+//
+// HTTPCollector 和 TCPCollector 记录收到的所有数据，支持注入延迟、错误状态码和断开连接等故障，
+// 并提供等待和断言的辅助方法。slogplus 自带的远程输出使用它测试，自定义输出也可以直接使用:
+//
+//	c := sinktest.NewHTTPCollector(t)
+//	c.FailNext(2, http.StatusServiceUnavailable) // 前两个请求返回 503
+//	h, _ := slogplus.NewOTLPHandler(&slogplus.OTLPOptions{Endpoint: c.URL})
+//	slog.New(h).Info("hello")
+//	h.Flush()
+//	batches := c.WaitForBatches(1, time.Second)
+package sinktest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Batch 是采集端收到的一个 HTTP 请求
+type Batch struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte // 已按 Content-Encoding: gzip 解压
+	Time   time.Time
+}
+
+// Lines 按行拆分请求体，忽略空行，适用于 NDJSON 等按行分隔的格式
+func (b Batch) Lines() []string {
+	return splitLines(b.Body)
+}
+
+// HTTPCollector 是假的 HTTP 采集端
+type HTTPCollector struct {
+	// URL 采集端地址，例如 http://127.0.0.1:12345
+	URL string
+
+	t   testing.TB
+	srv *httptest.Server
+
+	mu         sync.Mutex
+	cond       *sync.Cond
+	batches    []Batch
+	requests   int
+	latency    time.Duration
+	failN      int
+	failStatus int
+	dropN      int
+	respond    func(w http.ResponseWriter, r *http.Request)
+}
+
+// NewHTTPCollector 启动 HTTP 采集端，测试结束时自动关闭
+func NewHTTPCollector(t testing.TB) *HTTPCollector {
+	t.Helper()
+	c := &HTTPCollector{t: t}
+	c.cond = sync.NewCond(&c.mu)
+	c.srv = httptest.NewServer(http.HandlerFunc(c.serve))
+	c.URL = c.srv.URL
+	t.Cleanup(c.Close)
+	return c
+}
+
+// Close 关闭采集端
+func (c *HTTPCollector) Close() {
+	c.srv.CloseClientConnections()
+	c.srv.Close()
+}
+
+// SetLatency 设置每个请求的响应延迟，用于测试超时
+func (c *HTTPCollector) SetLatency(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.latency = d
+}
+
+// FailNext 接下来的 n 个请求返回 status，请求不会被记录
+func (c *HTTPCollector) FailNext(n, status int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failN, c.failStatus = n, status
+}
+
+// DisconnectNext 接下来的 n 个请求直接断开连接，不返回响应
+func (c *HTTPCollector) DisconnectNext(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dropN = n
+}
+
+// Respond 自定义成功请求的响应，例如返回 Splunk、Loki 等服务的特定响应体
+func (c *HTTPCollector) Respond(f func(w http.ResponseWriter, r *http.Request)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.respond = f
+}
+
+// Requests 返回收到的请求总数，包括注入故障的请求
+func (c *HTTPCollector) Requests() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests
+}
+
+// Batches 返回成功接收的请求
+func (c *HTTPCollector) Batches() []Batch {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Batch(nil), c.batches...)
+}
+
+// Lines 返回所有成功接收的请求体中的行
+func (c *HTTPCollector) Lines() []string {
+	var lines []string
+	for _, b := range c.Batches() {
+		lines = append(lines, b.Lines()...)
+	}
+	return lines
+}
+
+// WaitForBatches 等待至少收到 n 个请求，超时时测试失败
+func (c *HTTPCollector) WaitForBatches(n int, timeout time.Duration) []Batch {
+	c.t.Helper()
+	if !waitCond(&c.mu, c.cond, timeout, func() bool { return len(c.batches) >= n }) {
+		c.t.Fatalf("sinktest: %v 内只收到 %d 个请求，期望 %d 个", timeout, len(c.Batches()), n)
+	}
+	return c.Batches()
+}
+
+// Reset 清空已接收的请求和故障设置
+func (c *HTTPCollector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.batches, c.requests = nil, 0
+	c.latency, c.failN, c.dropN, c.respond = 0, 0, 0, nil
+}
+
+func (c *HTTPCollector) serve(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(r)
+
+	c.mu.Lock()
+	c.requests++
+	latency := c.latency
+	drop := c.dropN > 0
+	if drop {
+		c.dropN--
+	}
+	fail := 0
+	if !drop && c.failN > 0 {
+		c.failN--
+		fail = c.failStatus
+	}
+	respond := c.respond
+	c.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if drop {
+		if hj, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hj.Hijack(); err == nil {
+				conn.Close()
+				return
+			}
+		}
+		panic(http.ErrAbortHandler)
+	}
+	if fail != 0 {
+		w.WriteHeader(fail)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	c.batches = append(c.batches, Batch{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Header: r.Header.Clone(),
+		Body:   body,
+		Time:   time.Now(),
+	})
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	if respond != nil {
+		respond(w, r)
+	}
+}
+
+// readBody 读取请求体，按 Content-Encoding 解压
+func readBody(r *http.Request) ([]byte, error) {
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		body = zr
+	}
+	return io.ReadAll(body)
+}
+
+// splitLines 按行拆分，忽略空行
+func splitLines(b []byte) []string {
+	var lines []string
+	for _, l := range bytes.Split(b, []byte{'\n'}) {
+		if l = bytes.TrimRight(l, "\r"); len(l) > 0 {
+			lines = append(lines, string(l))
+		}
+	}
+	return lines
+}
+
+// waitCond 等待 ok 返回 true，超时返回 false
+func waitCond(mu *sync.Mutex, cond *sync.Cond, timeout time.Duration, ok func() bool) bool {
+	timer := time.AfterFunc(timeout, func() {
+		mu.Lock()
+		cond.Broadcast()
+		mu.Unlock()
+	})
+	defer timer.Stop()
+
+	deadline := time.Now().Add(timeout)
+	mu.Lock()
+	defer mu.Unlock()
+	for !ok() {
+		if !time.Now().Before(deadline) {
+			return false
+		}
+		cond.Wait()
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package sinktest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHTTPCollector_Faults(t *testing.T) {
+	c := NewHTTPCollector(t)
+	c.FailNext(1, http.StatusServiceUnavailable)
+	c.DisconnectNext(1)
+
+	post := func(body string) (int, error) {
+		resp, err := http.Post(c.URL+"/v1/logs", "text/plain", bytes.NewBufferString(body))
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+
+	if _, err := post("dropped"); err == nil {
+		t.Error("DisconnectNext 应该断开连接")
+	}
+	if code, _ := post("failed"); code != http.StatusServiceUnavailable {
+		t.Errorf("FailNext 应该返回 503: %d", code)
+	}
+	if code, _ := post("a\nb\n"); code != http.StatusOK {
+		t.Errorf("应该接收请求: %d", code)
+	}
+
+	batches := c.WaitForBatches(1, time.Second)
+	if len(batches) != 1 || batches[0].Path != "/v1/logs" || len(batches[0].Lines()) != 2 {
+		t.Errorf("收到的请求不正确: %+v", batches)
+	}
+	if c.Requests() != 3 {
+		t.Errorf("Requests() = %d, 期望 3", c.Requests())
+	}
+}
+
+func TestHTTPCollector_Gzip(t *testing.T) {
+	c := NewHTTPCollector(t)
+
+	var body bytes.Buffer
+	zw := gzip.NewWriter(&body)
+	zw.Write([]byte("compressed\n"))
+	zw.Close()
+	req, _ := http.NewRequest(http.MethodPost, c.URL, &body)
+	req.Header.Set("Content-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if lines := c.Lines(); len(lines) != 1 || lines[0] != "compressed" {
+		t.Errorf("应该自动解压请求体: %q", lines)
+	}
+}
+
+func TestTCPCollector_Restart(t *testing.T) {
+	c := NewTCPCollector(t)
+
+	conn, err := net.Dial("tcp", c.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Write([]byte("one\n"))
+	c.WaitForLines(1, time.Second)
+
+	c.Stop()
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Error("Stop 应该断开连接")
+	}
+	if _, err := net.DialTimeout("tcp", c.Addr, 100*time.Millisecond); err == nil {
+		t.Error("Stop 之后不应该接受连接")
+	}
+
+	c.Start()
+	conn, err = net.Dial("tcp", c.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("two\n"))
+	if lines := c.WaitForLines(2, time.Second); lines[1] != "two" {
+		t.Errorf("重新启动后应该继续接收: %q", lines)
+	}
+	if c.Accepted() != 2 {
+		t.Errorf("Accepted() = %d, 期望 2", c.Accepted())
+	}
+}
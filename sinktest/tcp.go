@@ -0,0 +1,180 @@
+package sinktest
+
+import (
+	"bytes"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TCPCollector 是假的 TCP 采集端，记录所有连接收到的数据
+type TCPCollector struct {
+	// Addr 监听地址，Stop 之后 Start 会重新监听同一地址
+	Addr string
+
+	t testing.TB
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	ln       net.Listener
+	conns    map[net.Conn]struct{}
+	data     bytes.Buffer
+	accepted int
+	latency  time.Duration
+	rejectN  int
+	wg       sync.WaitGroup
+}
+
+// NewTCPCollector 在随机端口启动 TCP 采集端，测试结束时自动关闭
+func NewTCPCollector(t testing.TB) *TCPCollector {
+	t.Helper()
+	c := &TCPCollector{t: t, conns: make(map[net.Conn]struct{})}
+	c.cond = sync.NewCond(&c.mu)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("sinktest: %v", err)
+	}
+	c.Addr = ln.Addr().String()
+	c.serve(ln)
+	t.Cleanup(c.Stop)
+	return c
+}
+
+// Start 在 Addr 上重新开始监听，用于模拟采集端恢复
+func (c *TCPCollector) Start() {
+	c.t.Helper()
+	ln, err := net.Listen("tcp", c.Addr)
+	if err != nil {
+		c.t.Fatalf("sinktest: 重新监听 %s 失败: %v", c.Addr, err)
+	}
+	c.serve(ln)
+}
+
+// Stop 停止监听并断开所有连接，用于模拟采集端宕机
+func (c *TCPCollector) Stop() {
+	c.mu.Lock()
+	if c.ln != nil {
+		c.ln.Close()
+		c.ln = nil
+	}
+	c.mu.Unlock()
+	c.DisconnectAll()
+	c.wg.Wait()
+}
+
+// DisconnectAll 断开当前所有连接，但继续接受新连接
+func (c *TCPCollector) DisconnectAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for conn := range c.conns {
+		conn.Close()
+	}
+}
+
+// RejectNext 接下来的 n 个连接在建立后立即关闭
+func (c *TCPCollector) RejectNext(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rejectN = n
+}
+
+// SetLatency 设置每次读取前的延迟，模拟慢速采集端，使发送方的写入超时或阻塞
+func (c *TCPCollector) SetLatency(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.latency = d
+}
+
+// Accepted 返回已接受的连接总数
+func (c *TCPCollector) Accepted() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.accepted
+}
+
+// Bytes 返回收到的所有数据
+func (c *TCPCollector) Bytes() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return bytes.Clone(c.data.Bytes())
+}
+
+// Lines 按行拆分收到的数据，忽略空行
+func (c *TCPCollector) Lines() []string {
+	return splitLines(c.Bytes())
+}
+
+// WaitForLines 等待至少收到 n 行，超时时测试失败
+func (c *TCPCollector) WaitForLines(n int, timeout time.Duration) []string {
+	c.t.Helper()
+	ok := waitCond(&c.mu, c.cond, timeout, func() bool {
+		return bytes.Count(c.data.Bytes(), []byte{'\n'}) >= n
+	})
+	if !ok {
+		c.t.Fatalf("sinktest: %v 内只收到 %d 行，期望 %d 行", timeout, len(c.Lines()), n)
+	}
+	return c.Lines()
+}
+
+func (c *TCPCollector) serve(ln net.Listener) {
+	c.mu.Lock()
+	c.ln = ln
+	c.mu.Unlock()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			c.mu.Lock()
+			c.accepted++
+			if c.rejectN > 0 {
+				c.rejectN--
+				c.mu.Unlock()
+				conn.Close()
+				continue
+			}
+			c.conns[conn] = struct{}{}
+			c.mu.Unlock()
+
+			c.wg.Add(1)
+			go c.read(conn)
+		}
+	}()
+}
+
+func (c *TCPCollector) read(conn net.Conn) {
+	defer c.wg.Done()
+	defer func() {
+		c.mu.Lock()
+		delete(c.conns, conn)
+		c.mu.Unlock()
+		conn.Close()
+	}()
+
+	buf := make([]byte, 32<<10)
+	for {
+		c.mu.Lock()
+		latency := c.latency
+		c.mu.Unlock()
+		if latency > 0 {
+			time.Sleep(latency)
+		}
+
+		n, err := conn.Read(buf)
+		if n > 0 {
+			c.mu.Lock()
+			c.data.Write(buf[:n])
+			c.cond.Broadcast()
+			c.mu.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
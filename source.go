@@ -0,0 +1,152 @@
+package slogplus
+
+import (
+	"net/url"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SourceLink 的常用模板
+const (
+	// SourceLinkFile 使用 file:// 链接，大多数支持 OSC 8 的终端会用默认程序打开
+	SourceLinkFile = "file://{host}{path}"
+
+	// SourceLinkVSCode 在 VS Code 中打开并跳转到对应行
+	SourceLinkVSCode = "vscode://file{path}:{line}"
+)
+
+// SourceFormat 表示源代码位置中文件路径的输出形式
+type SourceFormat string
+
+const (
+	// SourceFull 输出完整路径，例如 /home/builder/app/internal/db/conn.go:42，默认值
+	SourceFull SourceFormat = ""
+
+	// SourcePackage 输出所在目录和文件名，例如 db/conn.go:42
+	SourcePackage SourceFormat = "package"
+
+	// SourceFile 只输出文件名，例如 conn.go:42
+	SourceFile SourceFormat = "file"
+
+	// SourceModule 输出相对于主模块根目录的路径，例如 internal/db/conn.go:42，
+	// 主模块之外的包输出为包路径加文件名，例如 net/http/server.go:3285，不会泄露构建机器的目录
+	SourceModule SourceFormat = "module"
+)
+
+// mainModule 是主模块的路径，读取不到构建信息时为空
+var mainModule = sync.OnceValue(func() string {
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		return bi.Main.Path
+	}
+	return ""
+})
+
+// osc8 终端超链接控制码
+const (
+	osc8Start = "\x1b]8;;"
+	osc8End   = "\x1b\\"
+)
+
+// sourceRewrite 是一条源码路径前缀替换规则
+type sourceRewrite struct {
+	prefix, replacement string
+}
+
+// newSourceRewrites 将 Options.SourcePaths 转换为按前缀长度降序排列的规则，保证最长前缀优先
+func newSourceRewrites(m map[string]string) []sourceRewrite {
+	if len(m) == 0 {
+		return nil
+	}
+	rules := make([]sourceRewrite, 0, len(m))
+	for prefix, replacement := range m {
+		rules = append(rules, sourceRewrite{prefix, replacement})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if len(rules[i].prefix) != len(rules[j].prefix) {
+			return len(rules[i].prefix) > len(rules[j].prefix)
+		}
+		return rules[i].prefix < rules[j].prefix
+	})
+	return rules
+}
+
+// frame 返回 pc 对应的调用帧，文件路径按 SourcePaths 和 SourceFormat 改写
+func (h *Handler) frame(pc uintptr) runtime.Frame {
+	f := callerFrame(pc)
+	f.File = h.sourceFile(f)
+	return f
+}
+
+// frameCache 缓存 pc 对应的调用帧，调用位置的数量有限，不需要淘汰
+var frameCache sync.Map // uintptr -> *runtime.Frame
+
+// callerFrame 返回 pc 对应的原始调用帧
+// runtime.CallersFrames 需要分配内存并解析符号表，结果按 pc 缓存，开启 AddSource 时每条记录只需一次查表
+func callerFrame(pc uintptr) runtime.Frame {
+	if f, ok := frameCache.Load(pc); ok {
+		return *f.(*runtime.Frame)
+	}
+	fs := runtime.CallersFrames([]uintptr{pc})
+	f, _ := fs.Next()
+	frameCache.Store(pc, &f)
+	return f
+}
+
+// rewriteSource 按最长匹配的前缀改写源码路径
+func (h *Handler) rewriteSource(file string) string {
+	for _, r := range h.sourcePaths {
+		if strings.HasPrefix(file, r.prefix) {
+			return r.replacement + file[len(r.prefix):]
+		}
+	}
+	return file
+}
+
+// sourceFile 返回调用帧输出的文件路径，先按 SourcePaths 改写，再按 SourceFormat 截取
+func (h *Handler) sourceFile(f runtime.Frame) string {
+	file := h.rewriteSource(f.File)
+	base := file[strings.LastIndexByte(file, '/')+1:]
+	switch h.opts.SourceFormat {
+	case SourcePackage:
+		dir := file[:max(0, len(file)-len(base)-1)]
+		if dir == "" {
+			return base
+		}
+		return dir[strings.LastIndexByte(dir, '/')+1:] + "/" + base
+	case SourceFile:
+		return base
+	case SourceModule:
+		pkg := packagePath(f.Function)
+		if pkg == "" {
+			return base
+		}
+		if mod := mainModule(); mod != "" {
+			if pkg == mod {
+				return base
+			}
+			if rel, ok := strings.CutPrefix(pkg, mod+"/"); ok {
+				return rel + "/" + base
+			}
+		}
+		return pkg + "/" + base
+	}
+	return file
+}
+
+// appendLinkStart 追加源码位置的 OSC 8 超链接开头，链接文本之后需要追加 osc8Start+osc8End 结束
+func (h *Handler) appendLinkStart(buf []byte, file string, line int) []byte {
+	path := (&url.URL{Path: file}).EscapedPath()
+	link := strings.NewReplacer(
+		"{host}", h.opts.Hostname,
+		"{path}", path,
+		"{line}", strconv.Itoa(line),
+	).Replace(h.opts.SourceLink)
+
+	buf = append(buf, osc8Start...)
+	buf = append(buf, link...)
+	return append(buf, osc8End...)
+}
@@ -0,0 +1,127 @@
+package slogplus
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestHandler_SourcePaths(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	dir := filepath.Dir(file)
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{
+		AddSource: true,
+		SourcePaths: map[string]string{
+			"/":       "never/",
+			dir + "/": "github.com/IAmMrChen/slogplus/",
+		},
+	})
+	logger.Info("test")
+
+	if !strings.Contains(buf.String(), " source=github.com/IAmMrChen/slogplus/source_test.go:") {
+		t.Errorf("源码路径应该按最长前缀改写: %s", buf.String())
+	}
+}
+
+func TestRewriteSource(t *testing.T) {
+	h := New(nil, &Options{SourcePaths: map[string]string{
+		"/build/":         "",
+		"/build/vendor/":  "",
+		"/build/external": "ext",
+	}})
+	tests := map[string]string{
+		"/build/pkg/a.go":            "pkg/a.go",
+		"/build/vendor/x.org/y/b.go": "x.org/y/b.go",
+		"/build/external/c.go":       "ext/c.go",
+		"/other/d.go":                "/other/d.go",
+	}
+	for in, want := range tests {
+		if got := h.rewriteSource(in); got != want {
+			t.Errorf("rewriteSource(%q) = %q, 期望 %q", in, got, want)
+		}
+	}
+}
+
+func TestHandler_SourceLink(t *testing.T) {
+	var buf bytes.Buffer
+	h := New(&buf, &Options{
+		AddSource:   true,
+		SourceLink:  SourceLinkVSCode,
+		SourcePaths: map[string]string{"/": ""},
+	})
+	h.tty = true // 模拟终端输出
+	slog.New(h).Info("test")
+
+	_, file, _, _ := runtime.Caller(0)
+	out := buf.String()
+	if !strings.Contains(out, " source=\x1b]8;;vscode://file"+file+":") {
+		t.Errorf("应该输出 OSC 8 超链接，链接使用本地路径: %q", out)
+	}
+	if !strings.Contains(out, "\x1b\\"+strings.TrimPrefix(file, "/")+":") || !strings.Contains(out, "\x1b]8;;\x1b\\ msg=test") {
+		t.Errorf("链接文本应该使用改写后的路径: %q", out)
+	}
+
+	buf.Reset()
+	h.tty = false
+	slog.New(h).Info("test")
+	if strings.Contains(buf.String(), "\x1b]8") {
+		t.Errorf("非终端输出不应该包含超链接: %q", buf.String())
+	}
+}
+
+func TestHandler_SourceFormat(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	dir := filepath.Base(filepath.Dir(file))
+
+	for format, want := range map[SourceFormat]string{
+		SourcePackage: " source=" + dir + "/source_test.go:",
+		SourceFile:    " source=source_test.go:",
+		SourceModule:  " source=source_test.go:",
+	} {
+		var buf bytes.Buffer
+		NewLogger(&buf, &Options{AddSource: true, SourceFormat: format}).Info("test")
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("%s: 源码路径错误: %s", format, buf.String())
+		}
+	}
+
+	h := New(nil, &Options{SourceFormat: SourceModule})
+	for fn, want := range map[string]string{
+		"github.com/IAmMrChen/slogplus/parse.Parse": "parse/a.go",
+		"net/http.(*Server).Serve":                  "net/http/a.go",
+		"":                                          "a.go",
+	} {
+		if got := h.sourceFile(runtime.Frame{Function: fn, File: "/build/x/a.go"}); got != want {
+			t.Errorf("sourceFile(%q) = %q, 期望 %q", fn, got, want)
+		}
+	}
+}
+
+func TestCallerFrame_Cache(t *testing.T) {
+	pc, file, line, _ := runtime.Caller(0)
+	for i := 0; i < 2; i++ {
+		f := callerFrame(pc)
+		if f.File != file || f.Line != line || !strings.HasSuffix(f.Function, ".TestCallerFrame_Cache") {
+			t.Errorf("第 %d 次调用帧错误: %+v", i+1, f)
+		}
+	}
+	if _, ok := frameCache.Load(pc); !ok {
+		t.Error("调用帧应该被缓存")
+	}
+}
+
+func BenchmarkHandler_AddSource(b *testing.B) {
+	logger := NewLogger(io.Discard, &Options{AddSource: true})
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		logger.Info("test message", "key1", "value1")
+	}
+}
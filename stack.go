@@ -0,0 +1,88 @@
+package slogplus
+
+import (
+	"encoding/json"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// StackKey 是调用栈属性的名称
+const StackKey = "stack"
+
+// stackDepth 是调用栈最多保留的帧数
+const stackDepth = 32
+
+// Stack 返回调用方所在 goroutine 的调用栈属性，用于手动附加到记录
+//
+//	logger.Warn("意外的状态", "state", s, slogplus.Stack())
+//
+// 文本格式输出为多行，每帧为函数名和缩进的 file:line；JSON 格式输出为 "函数名 file:line" 字符串数组
+func Stack() slog.Attr {
+	pcs := make([]uintptr, stackDepth)
+	n := runtime.Callers(2, pcs)
+	return slog.Any(StackKey, stackTrace(pcs[:n]))
+}
+
+// stackTrace 是调用栈的程序计数器，最内层的调用在前
+type stackTrace []uintptr
+
+// frames 返回调用栈帧，去掉 runtime.goexit 等运行时入口
+func (s stackTrace) frames() []runtime.Frame {
+	frames := make([]runtime.Frame, 0, len(s))
+	it := runtime.CallersFrames(s)
+	for {
+		f, more := it.Next()
+		if f.Function != "runtime.goexit" && f.Function != "runtime.main" {
+			frames = append(frames, f)
+		}
+		if !more {
+			return frames
+		}
+	}
+}
+
+// String 实现 fmt.Stringer，格式与 runtime/debug.Stack 类似
+func (s stackTrace) String() string {
+	var b strings.Builder
+	for _, f := range s.frames() {
+		b.WriteString("\n\t")
+		b.WriteString(f.Function)
+		b.WriteString("\n\t\t")
+		b.WriteString(f.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(f.Line))
+	}
+	return b.String()
+}
+
+// MarshalJSON 实现 json.Marshaler
+func (s stackTrace) MarshalJSON() ([]byte, error) {
+	frames := s.frames()
+	lines := make([]string, len(frames))
+	for i, f := range frames {
+		lines[i] = f.Function + " " + f.File + ":" + strconv.Itoa(f.Line)
+	}
+	return json.Marshal(lines)
+}
+
+// withStack 为记录附加当前 goroutine 的调用栈，从记录的调用位置开始；
+// 调用位置不在当前 goroutine（例如经过异步队列）时只保留调用位置这一帧
+func withStack(r slog.Record) slog.Record {
+	if r.PC == 0 {
+		return r
+	}
+	pcs := make([]uintptr, stackDepth+16)
+	n := runtime.Callers(2, pcs)
+	stack := stackTrace{r.PC}
+	for i, pc := range pcs[:n] {
+		if pc == r.PC {
+			stack = stackTrace(pcs[i:min(n, i+stackDepth)])
+			break
+		}
+	}
+	r = r.Clone()
+	r.AddAttrs(slog.Any(StackKey, stack))
+	return r
+}
@@ -0,0 +1,45 @@
+package slogplus
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestStackTrace(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{TimeFormat: "-", StackTrace: slog.LevelError})
+
+	logger.Warn("慢")
+	if strings.Contains(buf.String(), "stack=") {
+		t.Errorf("低于 StackTrace 级别的记录不应该带调用栈: %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.Error("失败")
+	got := buf.String()
+	if !strings.HasPrefix(got, "- ERROR msg=失败 stack=\n\tgithub.com/IAmMrChen/slogplus.TestStackTrace\n\t\t") {
+		t.Errorf("调用栈应该从记录的调用位置开始: %q", got)
+	}
+	if strings.Contains(got, "slog.(*Logger)") || strings.Contains(got, "runtime.goexit") {
+		t.Errorf("调用栈不应该包含 slog 内部和运行时入口: %q", got)
+	}
+}
+
+func TestStack_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{Format: FormatJSON})
+	logger.Info("检查点", Stack())
+
+	var m struct {
+		Stack []string `json:"stack"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Stack) < 2 || !strings.HasPrefix(m.Stack[0], "github.com/IAmMrChen/slogplus.TestStack_JSON ") || !strings.Contains(m.Stack[0], "stack_test.go:") {
+		t.Errorf("调用栈错误: %q", m.Stack)
+	}
+}
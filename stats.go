@@ -0,0 +1,278 @@
+package slogplus
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PipelineStats 收集日志管道自身的运行统计，并定期输出为一条统计记录
+// 采样、去重、远程输出等组件向它报告采样比例、去重数量、队列深度和发送延迟，
+// 同一个 PipelineStats 可以在多个组件之间共享:
+//
+//	stats := &slogplus.PipelineStats{Interval: time.Minute}
+//	h, _ := slogplus.NewOTLPHandler(&slogplus.OTLPOptions{Stats: stats})
+//	stop := stats.Start(slog.Default().Handler())
+//	defer stop()
+type PipelineStats struct {
+	// Interval 输出统计记录的间隔，也是统计窗口，默认 1 分钟
+	Interval time.Duration
+
+	// Level 统计记录的级别，默认为 Info
+	Level slog.Level
+
+	mu      sync.Mutex
+	start   time.Time
+	seen    int64
+	kept    int64
+	deduped int64
+	queues  map[string]int
+	latency map[string]*latencySamples
+	now     func() time.Time
+}
+
+// PipelineSnapshot 是一个统计窗口内的统计信息
+type PipelineSnapshot struct {
+	Start        time.Time                `json:"start"`
+	End          time.Time                `json:"end"`
+	Seen         int64                    `json:"seen"`          // 参与采样的记录数
+	Kept         int64                    `json:"kept"`          // 采样保留的记录数
+	Deduped      int64                    `json:"deduped"`       // 去重合并的记录数
+	QueueHigh    map[string]int           `json:"queue_high"`    // 各队列的最高深度
+	LatencyP99   map[string]time.Duration `json:"latency_p99"`   // 各输出发送延迟的 p99
+	LatencyMax   map[string]time.Duration `json:"latency_max"`   // 各输出发送延迟的最大值
+	LatencyCount map[string]int64         `json:"latency_count"` // 各输出的发送次数
+}
+
+// SampledPercent 返回采样保留的百分比，没有记录参与采样时返回 100
+func (s PipelineSnapshot) SampledPercent() float64 {
+	if s.Seen == 0 {
+		return 100
+	}
+	return float64(s.Kept) * 100 / float64(s.Seen)
+}
+
+// PipelineStatsMessage 是统计记录的消息
+const PipelineStatsMessage = "日志管道统计"
+
+// latencySamplesMax 每个输出每个窗口最多保留的延迟样本数，超出后使用蓄水池抽样
+const latencySamplesMax = 1024
+
+// latencySamples 是一个输出在当前窗口内的延迟样本
+type latencySamples struct {
+	samples []time.Duration
+	count   int64
+	max     time.Duration
+}
+
+// Sampled 报告一条记录的采样结果，kept 表示记录被保留
+func (s *PipelineStats) Sampled(kept bool) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seen++
+	if kept {
+		s.kept++
+	}
+}
+
+// Deduped 报告 n 条记录因重复被合并
+func (s *PipelineStats) Deduped(n int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deduped += int64(n)
+}
+
+// QueueDepth 报告队列 name 的当前深度，统计记录中为窗口内的最高深度
+func (s *PipelineStats) QueueDepth(name string, n int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.queues == nil {
+		s.queues = make(map[string]int)
+	}
+	if n > s.queues[name] {
+		s.queues[name] = n
+	}
+}
+
+// Latency 报告输出 sink 的一次发送耗时
+func (s *PipelineStats) Latency(sink string, d time.Duration) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.latency == nil {
+		s.latency = make(map[string]*latencySamples)
+	}
+	l := s.latency[sink]
+	if l == nil {
+		l = &latencySamples{}
+		s.latency[sink] = l
+	}
+	l.count++
+	l.max = max(l.max, d)
+	if len(l.samples) < latencySamplesMax {
+		l.samples = append(l.samples, d)
+	} else if i := rand.Int64N(l.count); i < latencySamplesMax {
+		l.samples[i] = d
+	}
+}
+
+// Snapshot 返回当前窗口的统计信息，不会清空统计
+func (s *PipelineStats) Snapshot() PipelineSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshot()
+}
+
+// snapshot 计算当前窗口的统计信息，调用方需持有 mu
+func (s *PipelineStats) snapshot() PipelineSnapshot {
+	snap := PipelineSnapshot{
+		Start:        s.start,
+		End:          s.clock(),
+		Seen:         s.seen,
+		Kept:         s.kept,
+		Deduped:      s.deduped,
+		QueueHigh:    make(map[string]int, len(s.queues)),
+		LatencyP99:   make(map[string]time.Duration, len(s.latency)),
+		LatencyMax:   make(map[string]time.Duration, len(s.latency)),
+		LatencyCount: make(map[string]int64, len(s.latency)),
+	}
+	for name, n := range s.queues {
+		snap.QueueHigh[name] = n
+	}
+	for name, l := range s.latency {
+		snap.LatencyP99[name] = percentile(l.samples, 0.99)
+		snap.LatencyMax[name] = l.max
+		snap.LatencyCount[name] = l.count
+	}
+	return snap
+}
+
+// reset 开始新的统计窗口，返回上一窗口的统计信息
+func (s *PipelineStats) reset() PipelineSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap := s.snapshot()
+	s.start = snap.End
+	s.seen, s.kept, s.deduped = 0, 0, 0
+	s.queues, s.latency = nil, nil
+	return snap
+}
+
+func (s *PipelineStats) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// percentile 返回样本的 p 分位数
+func percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	i := int(float64(len(sorted))*p+0.5) - 1
+	return sorted[max(0, min(i, len(sorted)-1))]
+}
+
+// Start 每隔 Interval 向 h 输出一条统计记录并开始新的窗口，返回的 stop 函数停止输出，
+// 停止前会输出最后一个窗口的统计
+func (s *PipelineStats) Start(h slog.Handler) (stop func()) {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	s.mu.Lock()
+	s.start = s.clock()
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.emit(h)
+			case <-done:
+				s.emit(h)
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-exited
+		})
+	}
+}
+
+// emit 输出当前窗口的统计记录
+func (s *PipelineStats) emit(h slog.Handler) {
+	snap := s.reset()
+	ctx := context.Background()
+	if !h.Enabled(ctx, s.Level) {
+		return
+	}
+	h.Handle(ctx, snap.record(s.Level))
+}
+
+// record 生成统计记录，没有数据的部分省略
+func (s PipelineSnapshot) record(level slog.Level) slog.Record {
+	r := slog.NewRecord(s.End, level, PipelineStatsMessage, 0)
+	r.AddAttrs(slog.Duration("interval", s.End.Sub(s.Start)))
+	if s.Seen > 0 {
+		r.AddAttrs(slog.Group("sampled",
+			slog.Int64("seen", s.Seen),
+			slog.Int64("kept", s.Kept),
+			slog.Float64("percent", s.SampledPercent()),
+		))
+	}
+	if s.Deduped > 0 {
+		r.AddAttrs(slog.Int64("deduped", s.Deduped))
+	}
+	if len(s.QueueHigh) > 0 {
+		r.AddAttrs(slog.Group("queue_high", sortedAttrs(s.QueueHigh, func(n int) slog.Value {
+			return slog.IntValue(n)
+		})...))
+	}
+	if len(s.LatencyP99) > 0 {
+		r.AddAttrs(slog.Group("latency_p99", sortedAttrs(s.LatencyP99, slog.DurationValue)...))
+	}
+	return r
+}
+
+// sortedAttrs 将 map 按键排序后转换为属性
+func sortedAttrs[V any](m map[string]V, value func(V) slog.Value) []any {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]any, len(keys))
+	for i, k := range keys {
+		attrs[i] = slog.Attr{Key: k, Value: value(m[k])}
+	}
+	return attrs
+}
@@ -0,0 +1,14 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import "expvar"
+
+// PublishStats 将 p 的运行统计以 name 发布到 expvar，通过 /debug/vars 查看，
+// 便于仪表盘监控记录数、丢弃数和写入错误；名称重复时 panic，与 expvar.Publish 相同
+//
+//	h := slogplus.New(os.Stdout, nil)
+//	slogplus.PublishStats("slogplus", h)
+func PublishStats(name string, p StatsProvider) {
+	expvar.Publish(name, expvar.Func(func() any { return p.Stats() }))
+}
@@ -0,0 +1,31 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+)
+
+// publishCount 使 -count 多次运行时 expvar 名称不重复
+var publishCount atomic.Int32
+
+func TestPublishStats(t *testing.T) {
+	name := fmt.Sprintf("slogplus_test_%d", publishCount.Add(1))
+	h := New(&bytes.Buffer{}, nil)
+	PublishStats(name, h)
+	slog.New(h).Warn("test")
+
+	var s HandlerStats
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Records.Warn != 1 || s.Bytes == 0 {
+		t.Errorf("expvar 应该输出当前的统计: %+v", s)
+	}
+}
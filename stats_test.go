@@ -0,0 +1,82 @@
+package slogplus
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPipelineStats(t *testing.T) {
+	s := &PipelineStats{Interval: time.Hour}
+	now := time.Date(2025, 11, 14, 14, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	var buf bytes.Buffer
+	h := New(&buf, &Options{Format: FormatJSON})
+	stop := s.Start(h)
+
+	for i := 0; i < 10; i++ {
+		s.Sampled(i%4 == 0)
+	}
+	s.Deduped(5)
+	s.QueueDepth("otlp", 3)
+	s.QueueDepth("otlp", 42)
+	s.QueueDepth("otlp", 7)
+	for i := 1; i <= 100; i++ {
+		s.Latency("otlp", time.Duration(i)*time.Millisecond)
+	}
+
+	snap := s.Snapshot()
+	if snap.SampledPercent() != 30 || snap.QueueHigh["otlp"] != 42 || snap.LatencyP99["otlp"] != 99*time.Millisecond {
+		t.Errorf("统计错误: %+v", snap)
+	}
+
+	now = now.Add(time.Minute)
+	stop()
+	stop()
+
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("应该只输出一条统计记录: %v\n%s", err, buf.String())
+	}
+	if rec["msg"] != PipelineStatsMessage || rec["deduped"] != float64(5) {
+		t.Errorf("统计记录错误: %s", buf.String())
+	}
+	if rec["sampled"].(map[string]any)["percent"] != float64(30) {
+		t.Errorf("采样比例错误: %s", buf.String())
+	}
+	if rec["queue_high"].(map[string]any)["otlp"] != float64(42) {
+		t.Errorf("队列最高深度错误: %s", buf.String())
+	}
+	if rec["latency_p99"].(map[string]any)["otlp"] != float64(99*time.Millisecond) {
+		t.Errorf("延迟 p99 错误: %s", buf.String())
+	}
+
+	if snap := s.Snapshot(); snap.Seen != 0 || len(snap.QueueHigh) != 0 || !snap.Start.Equal(now) {
+		t.Errorf("输出后应该开始新的窗口: %+v", snap)
+	}
+}
+
+func TestPipelineStats_Nil(t *testing.T) {
+	var s *PipelineStats
+	s.Sampled(true)
+	s.Deduped(1)
+	s.QueueDepth("q", 1)
+	s.Latency("sink", time.Second)
+}
+
+func TestPipelineStats_Reservoir(t *testing.T) {
+	s := &PipelineStats{}
+	for i := 0; i < 10*latencySamplesMax; i++ {
+		s.Latency("kafka", time.Millisecond)
+	}
+	snap := s.Snapshot()
+	if snap.LatencyCount["kafka"] != 10*latencySamplesMax || len(s.latency["kafka"].samples) != latencySamplesMax {
+		t.Errorf("样本数应该受限: %d", len(s.latency["kafka"].samples))
+	}
+	if snap.LatencyP99["kafka"] != time.Millisecond || !strings.Contains(snap.record(0).Message, "统计") {
+		t.Errorf("统计错误: %+v", snap)
+	}
+}
@@ -0,0 +1,228 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StatsdOptions 定义 statsd 指标上报的配置
+type StatsdOptions struct {
+	// Prefix 指标名前缀，默认为 "slogplus"
+	Prefix string
+
+	// ComponentKey 用于区分组件的属性名，默认为 "component"
+	// 记录中含有该属性时，指标名为 <prefix>.<component>.<level>
+	ComponentKey string
+
+	// FlushInterval 上报间隔，默认 10 秒
+	FlushInterval time.Duration
+}
+
+// Statsd 通过 UDP 向 statsd 上报每个级别、每个组件的日志条数
+// 计数在内存中聚合，按间隔批量发送，不会阻塞日志写入
+type Statsd struct {
+	opts StatsdOptions
+	conn net.Conn
+
+	mu     sync.Mutex
+	counts map[string]int64
+
+	stop       chan struct{}
+	done       chan struct{}
+	once       sync.Once
+	unregister func()
+}
+
+// statsdMaxPacket 单个 UDP 包的最大字节数，避免 IP 分片
+const statsdMaxPacket = 1432
+
+// NewStatsd 创建一个向 addr 上报日志计数的 Statsd
+func NewStatsd(addr string, opts *StatsdOptions) (*Statsd, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Statsd{
+		conn:   conn,
+		counts: make(map[string]int64),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if opts != nil {
+		s.opts = *opts
+	}
+
+	// 设置默认值
+	if s.opts.Prefix == "" {
+		s.opts.Prefix = "slogplus"
+	}
+	if s.opts.ComponentKey == "" {
+		s.opts.ComponentKey = "component"
+	}
+	if s.opts.FlushInterval <= 0 {
+		s.opts.FlushInterval = 10 * time.Second
+	}
+
+	s.unregister = RegisterFlusher(s)
+	go s.loop()
+	return s, nil
+}
+
+// Handler 包装 h，每条记录在写入前计数
+func (s *Statsd) Handler(h slog.Handler) slog.Handler {
+	return &statsdHandler{next: h, s: s}
+}
+
+// Flush 立即发送所有已聚合的计数
+func (s *Statsd) Flush() error {
+	s.mu.Lock()
+	if len(s.counts) == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	keys := make([]string, 0, len(s.counts))
+	for k := range s.counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var packets [][]byte
+	var buf []byte
+	for _, k := range keys {
+		line := make([]byte, 0, len(k)+16)
+		line = append(line, k...)
+		line = append(line, ':')
+		line = strconv.AppendInt(line, s.counts[k], 10)
+		line = append(line, "|c"...)
+		if len(buf) > 0 && len(buf)+1+len(line) > statsdMaxPacket {
+			packets = append(packets, buf)
+			buf = nil
+		}
+		if len(buf) > 0 {
+			buf = append(buf, '\n')
+		}
+		buf = append(buf, line...)
+	}
+	packets = append(packets, buf)
+	clear(s.counts)
+	s.mu.Unlock()
+
+	var firstErr error
+	for _, p := range packets {
+		if _, err := s.conn.Write(p); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Close 停止定时上报，发送剩余计数并关闭连接
+func (s *Statsd) Close() error {
+	s.once.Do(func() {
+		s.unregister()
+		close(s.stop)
+		<-s.done
+	})
+	err := s.Flush()
+	if cerr := s.conn.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (s *Statsd) loop() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Flush()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// incr 增加一条记录的计数
+func (s *Statsd) incr(component string, level slog.Level) {
+	name := s.opts.Prefix + "."
+	if component != "" {
+		name += statsdSanitize(component) + "."
+	}
+	name += strings.ToLower(levelName(level))
+
+	s.mu.Lock()
+	s.counts[name]++
+	s.mu.Unlock()
+}
+
+// statsdSanitize 替换 statsd 协议中有特殊含义的字符
+func statsdSanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', '@', '.', ' ', '\n':
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// statsdHandler 在转发记录之前进行计数
+type statsdHandler struct {
+	next      slog.Handler
+	s         *Statsd
+	component string // 通过 WithAttrs 设置的组件名
+	grouped   bool   // WithGroup 之后的属性不再视为组件
+}
+
+func (h *statsdHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *statsdHandler) Handle(ctx context.Context, r slog.Record) error {
+	component := h.component
+	if component == "" && !h.grouped {
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key == h.s.opts.ComponentKey {
+				component = a.Value.String()
+				return false
+			}
+			return true
+		})
+	}
+	h.s.incr(component, r.Level)
+	return h.next.Handle(ctx, r)
+}
+
+func (h *statsdHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	nh := *h
+	nh.next = h.next.WithAttrs(attrs)
+	if !h.grouped {
+		for _, a := range attrs {
+			if a.Key == h.s.opts.ComponentKey {
+				nh.component = a.Value.String()
+			}
+		}
+	}
+	return &nh
+}
+
+func (h *statsdHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	nh := *h
+	nh.next = h.next.WithGroup(name)
+	nh.grouped = true
+	return &nh
+}
@@ -0,0 +1,52 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatsd_Counts(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("无法监听 UDP: %v", err)
+	}
+	defer pc.Close()
+
+	s, err := NewStatsd(pc.LocalAddr().String(), &StatsdOptions{
+		Prefix:        "app",
+		FlushInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	logger := slog.New(s.Handler(New(io.Discard, nil)))
+	logger.Info("a")
+	logger.Info("b")
+	logger.With("component", "db").Error("c")
+	logger.Error("d", "component", "cache")
+
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, statsdMaxPacket)
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(buf[:n])
+	for _, want := range []string{"app.info:2|c", "app.db.error:1|c", "app.cache.error:1|c"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("statsd 数据应该包含 %q: %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package slogplus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// Sugar 是 slog.Logger 的 printf 风格包装，便于从 log.Printf、zap.SugaredLogger 迁移
+// 底层仍然是同一个 Handler，格式化只在级别启用时进行
+//
+//	s := slogplus.NewSugar(logger)
+//	s.Infof("用户 %d 登录", id)                   // msg=用户 42 登录
+//	s.Errorw("保存失败", "order", id, "err", err) // 与 logger.Error 相同
+type Sugar struct {
+	l *slog.Logger
+}
+
+// NewSugar 创建 Sugar，l 为 nil 时使用默认 Logger
+func NewSugar(l *slog.Logger) *Sugar {
+	if l == nil {
+		l = slog.Default()
+	}
+	return &Sugar{l: l}
+}
+
+// Logger 返回底层的 slog.Logger
+func (s *Sugar) Logger() *slog.Logger { return s.l }
+
+// With 返回附加了属性的 Sugar，args 的写法与 slog.Logger.With 相同
+func (s *Sugar) With(args ...any) *Sugar { return &Sugar{l: s.l.With(args...)} }
+
+// Debugf 按 fmt.Sprintf 格式化消息并以 Debug 级别记录
+func (s *Sugar) Debugf(format string, args ...any) { s.logf(slog.LevelDebug, format, args) }
+
+// Infof 按 fmt.Sprintf 格式化消息并以 Info 级别记录
+func (s *Sugar) Infof(format string, args ...any) { s.logf(slog.LevelInfo, format, args) }
+
+// Warnf 按 fmt.Sprintf 格式化消息并以 Warn 级别记录
+func (s *Sugar) Warnf(format string, args ...any) { s.logf(slog.LevelWarn, format, args) }
+
+// Errorf 按 fmt.Sprintf 格式化消息并以 Error 级别记录
+func (s *Sugar) Errorf(format string, args ...any) { s.logf(slog.LevelError, format, args) }
+
+// Fatalf 按 fmt.Sprintf 格式化消息并以 LevelFatal 记录，之后刷新所有输出并退出进程，参见 Fatal
+func (s *Sugar) Fatalf(format string, args ...any) {
+	flushAndExit(logAt(context.Background(), s.l, LevelFatal, fmt.Sprintf(format, args...), nil, 3))
+}
+
+// Panicf 按 fmt.Sprintf 格式化消息并以 LevelPanic 记录，之后以该消息触发 panic
+func (s *Sugar) Panicf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	logAt(context.Background(), s.l, LevelPanic, msg, nil, 3)
+	panic(msg)
+}
+
+// Debugw 以 Debug 级别记录消息和键值对属性
+func (s *Sugar) Debugw(msg string, keysAndValues ...any) {
+	logAt(context.Background(), s.l, slog.LevelDebug, msg, keysAndValues, 3)
+}
+
+// Infow 以 Info 级别记录消息和键值对属性
+func (s *Sugar) Infow(msg string, keysAndValues ...any) {
+	logAt(context.Background(), s.l, slog.LevelInfo, msg, keysAndValues, 3)
+}
+
+// Warnw 以 Warn 级别记录消息和键值对属性
+func (s *Sugar) Warnw(msg string, keysAndValues ...any) {
+	logAt(context.Background(), s.l, slog.LevelWarn, msg, keysAndValues, 3)
+}
+
+// Errorw 以 Error 级别记录消息和键值对属性
+func (s *Sugar) Errorw(msg string, keysAndValues ...any) {
+	logAt(context.Background(), s.l, slog.LevelError, msg, keysAndValues, 3)
+}
+
+// logf 在级别启用时格式化消息并记录，源代码位置为导出方法的调用方
+func (s *Sugar) logf(level slog.Level, format string, args []any) {
+	if !s.l.Enabled(context.Background(), level) {
+		return
+	}
+	logAt(context.Background(), s.l, level, fmt.Sprintf(format, args...), nil, 4)
+}
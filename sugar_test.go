@@ -0,0 +1,40 @@
+package slogplus
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSugar(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewSugar(NewLogger(&buf, &Options{TimeFormat: "-", AddSource: true})).With("service", "api")
+
+	s.Infof("用户 %d 登录", 42)
+	s.Errorw("保存失败", "order", 7)
+	s.Debugf("不会输出 %v", 1)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("应该输出 2 行: %q", lines)
+	}
+	if !strings.Contains(lines[0], "sugar_test.go:14 service=api msg=用户 42 登录") {
+		t.Errorf("Infof 输出错误: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "sugar_test.go:15 service=api msg=保存失败 order=7") {
+		t.Errorf("Errorw 输出错误: %q", lines[1])
+	}
+}
+
+func TestSugar_Panicf(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewSugar(slog.New(New(&buf, &Options{TimeFormat: "-"})))
+
+	defer func() {
+		if r := recover(); r != "状态 3 无效" || buf.String() != "- PANIC msg=状态 3 无效\n" {
+			t.Errorf("Panicf 错误: %v %q", r, buf.String())
+		}
+	}()
+	s.Panicf("状态 %d 无效", 3)
+}
@@ -0,0 +1,174 @@
+package slogplus
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SyslogOptions 定义 RFC 5424 syslog 格式的配置
+type SyslogOptions struct {
+	// Facility syslog facility，默认为 1（user）
+	Facility int
+
+	// AppName APP-NAME 字段，默认为程序名
+	AppName string
+
+	// MsgID MSGID 字段，默认为 "-"
+	MsgID string
+
+	// SDID 结构化数据的 SD-ID，属性输出为该元素的参数，默认为 "slogplus@32473"
+	SDID string
+}
+
+// appendSyslog 按 RFC 5424 格式追加一条记录
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD-ID key="value" ...] MSG
+func (h *Handler) appendSyslog(buf []byte, r slog.Record) []byte {
+	var so SyslogOptions
+	if h.opts.Syslog != nil {
+		so = *h.opts.Syslog
+	}
+	if so.Facility <= 0 {
+		so.Facility = 1
+	}
+
+	buf = append(buf, '<')
+	buf = strconv.AppendInt(buf, int64(so.Facility*8+syslogSeverity(r.Level)), 10)
+	buf = append(buf, ">1 "...)
+
+	if r.Time.IsZero() {
+		buf = append(buf, '-')
+	} else {
+		buf = r.Time.AppendFormat(buf, "2006-01-02T15:04:05.000000Z07:00")
+	}
+	buf = append(buf, ' ')
+	buf = appendSyslogHeader(buf, h.opts.Hostname, 255)
+	buf = append(buf, ' ')
+	if so.AppName == "" {
+		so.AppName = filepath.Base(os.Args[0])
+	}
+	buf = appendSyslogHeader(buf, so.AppName, 48)
+	buf = append(buf, ' ')
+	buf = strconv.AppendInt(buf, int64(os.Getpid()), 10)
+	buf = append(buf, ' ')
+	buf = appendSyslogHeader(buf, so.MsgID, 32)
+	buf = append(buf, ' ')
+
+	// 结构化数据
+	if so.SDID == "" {
+		so.SDID = "slogplus@32473"
+	}
+	start := len(buf)
+	buf = append(buf, '[')
+	buf = appendSyslogName(buf, so.SDID)
+	n := len(buf)
+
+	if h.opts.AddSource && r.PC != 0 {
+		f := h.frame(r.PC)
+		if f.File != "" {
+			buf = append(buf, ` source="`...)
+			buf = appendSyslogValue(buf, f.File+":"+strconv.Itoa(f.Line))
+			buf = append(buf, '"')
+		}
+	}
+	buf = h.appendBound(buf, FormatSyslog)
+	r.Attrs(func(a slog.Attr) bool {
+		buf = h.appendSyslogParam(buf, h.groups, a)
+		return true
+	})
+
+	if len(buf) == n {
+		// 没有参数时输出 NILVALUE
+		buf = append(buf[:start], '-')
+	} else {
+		buf = append(buf, ']')
+	}
+
+	buf = append(buf, ' ')
+	buf = append(buf, r.Message...)
+	return append(buf, '\n')
+}
+
+// appendSyslogParam 追加结构化数据参数，分组展开为 group.key
+func (h *Handler) appendSyslogParam(buf []byte, groups []string, a slog.Attr) []byte {
+	if h.opts.ReplaceAttr != nil && a.Value.Kind() != slog.KindGroup {
+		a = h.opts.ReplaceAttr(groups, a)
+	}
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) || h.omitNil(a.Value) {
+		return buf
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], a.Key)
+		}
+		for _, ga := range a.Value.Group() {
+			buf = h.appendSyslogParam(buf, groups, ga)
+		}
+		return buf
+	}
+
+	buf = append(buf, ' ')
+	start := len(buf)
+	for _, g := range groups {
+		buf = appendSyslogName(buf, g)
+		buf = append(buf, '.')
+	}
+	buf = appendSyslogName(buf, a.Key)
+	if len(buf)-start > 32 {
+		// PARAM-NAME 最长 32 个字符
+		buf = buf[:start+32]
+	}
+	buf = append(buf, '=', '"')
+	buf = appendSyslogValue(buf, string(h.appendValue(nil, a.Value)))
+	return append(buf, '"')
+}
+
+// appendSyslogHeader 追加头部字段，空值输出为 "-"，只保留可打印 ASCII 字符
+func appendSyslogHeader(buf []byte, s string, max int) []byte {
+	if s == "" {
+		return append(buf, '-')
+	}
+	n := 0
+	for i := 0; i < len(s) && n < max; i++ {
+		if c := s[i]; c > ' ' && c < 0x7f {
+			buf = append(buf, c)
+			n++
+		}
+	}
+	if n == 0 {
+		buf = append(buf, '-')
+	}
+	return buf
+}
+
+// appendSyslogName 追加 SD-NAME，非法字符替换为 '_'
+func appendSyslogName(buf []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c >= 0x7f || c == '=' || c == ']' || c == '"' {
+			c = '_'
+		}
+		buf = append(buf, c)
+	}
+	return buf
+}
+
+// appendSyslogValue 追加 PARAM-VALUE，转义 '"'、'\' 和 ']'，非法 UTF-8 替换为 U+FFFD
+func appendSyslogValue(buf []byte, s string) []byte {
+	if !utf8.ValidString(s) {
+		s = strings.ToValidUTF8(s, "\uFFFD")
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\', ']':
+			buf = append(buf, '\\', c)
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return buf
+}
@@ -0,0 +1,37 @@
+package slogplus
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestHandler_Syslog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Options{
+		Format:   FormatSyslog,
+		Hostname: "web-1",
+		Syslog:   &SyslogOptions{Facility: 16, AppName: "api", MsgID: "REQ"},
+	})
+
+	logger.With("user", `a"b]`).Warn("slow request", slog.Group("req", "path", "/x"))
+
+	re := regexp.MustCompile(`^<132>1 \S+T\S+ web-1 api (\d+) REQ \[slogplus@32473 user="a\\"b\\]" req.path="/x"\] slow request\n$`)
+	m := re.FindStringSubmatch(buf.String())
+	if m == nil {
+		t.Fatalf("syslog 格式不正确: %q", buf.String())
+	}
+	if m[1] != strconv.Itoa(os.Getpid()) {
+		t.Errorf("PROCID 应该是进程号: %s", m[1])
+	}
+
+	buf.Reset()
+	logger.Error("no attrs")
+	if !strings.HasPrefix(buf.String(), "<131>1 ") || !strings.Contains(buf.String(), " REQ - no attrs") {
+		t.Errorf("没有属性时结构化数据应该为 '-': %q", buf.String())
+	}
+}
@@ -0,0 +1,131 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// syslogWriteTimeout 截止时间在写入前就已经过去时单条消息的写入时限
+const syslogWriteTimeout = 5 * time.Second
+
+// syslogLocalPaths 本地 syslog socket 的常见路径
+var syslogLocalPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogWriter 将 syslog 消息发送到本地 socket 或远程 UDP/TCP 端点
+// 每次 Write 视为一条消息，TCP 使用 RFC 6587 的八位组计数分帧，
+// 写入失败时自动重连一次
+type SyslogWriter struct {
+	network string
+	addr    string
+	sec     *SinkSecurity
+
+	mu   sync.Mutex
+	conn net.Conn
+	buf  []byte
+}
+
+// NewSyslogWriter 创建 syslog 输出
+// network 为空时连接本地 syslog（/dev/log 等），也可以是 "unix"、"unixgram"、"udp"、"tcp"；
+// sec 用于 TCP 连接的 TLS 配置，可以为 nil
+func NewSyslogWriter(network, addr string, sec *SinkSecurity) (*SyslogWriter, error) {
+	w := &SyslogWriter{network: network, addr: addr, sec: sec}
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *SyslogWriter) connect() error {
+	if w.network != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		conn, err := w.sec.DialContext(ctx, w.network, w.addr)
+		if err != nil {
+			return err
+		}
+		w.conn = conn
+		return nil
+	}
+
+	for _, path := range syslogLocalPaths {
+		for _, network := range []string{"unixgram", "unix"} {
+			if conn, err := net.Dial(network, path); err == nil {
+				w.conn = conn
+				return nil
+			}
+		}
+	}
+	return errors.New("slogplus: 找不到本地 syslog socket")
+}
+
+// Write 发送一条 syslog 消息，末尾的换行符会被去掉
+func (w *SyslogWriter) Write(p []byte) (int, error) {
+	return w.WriteDeadline(p, time.Time{})
+}
+
+// WriteDeadline 实现 DeadlineWriter，在 deadline 之前没有发送完的消息被丢弃并返回 ErrWriteTimeout，
+// 超时后断开连接，下一条消息重新连接
+// deadline 在写入前就已经过去时（例如请求超时后记录的日志）消息仍然发送，最多等待 5 秒
+func (w *SyslogWriter) WriteDeadline(p []byte, deadline time.Time) (int, error) {
+	msg := bytes.TrimRight(p, "\n")
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !deadline.IsZero() && !time.Now().Before(deadline) {
+		deadline = time.Now().Add(syslogWriteTimeout)
+	}
+
+	if w.network == "tcp" || w.network == "tcp4" || w.network == "tcp6" {
+		w.buf = strconv.AppendInt(w.buf[:0], int64(len(msg)), 10)
+		w.buf = append(w.buf, ' ')
+		w.buf = append(w.buf, msg...)
+		msg = w.buf
+	}
+
+	if w.conn != nil {
+		w.conn.SetWriteDeadline(deadline)
+		_, err := w.conn.Write(msg)
+		if err == nil {
+			return len(p), nil
+		}
+		w.conn.Close()
+		w.conn = nil
+		if ne, ok := err.(net.Error); ok && ne.Timeout() {
+			return 0, ErrWriteTimeout
+		}
+	}
+
+	if err := w.connect(); err != nil {
+		return 0, err
+	}
+	w.conn.SetWriteDeadline(deadline)
+	if _, err := w.conn.Write(msg); err != nil {
+		if ne, ok := err.(net.Error); ok && ne.Timeout() {
+			w.conn.Close()
+			w.conn = nil
+			return 0, ErrWriteTimeout
+		}
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close 关闭连接
+func (w *SyslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
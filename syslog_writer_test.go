@@ -0,0 +1,123 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogWriter_TCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("无法监听 TCP: %v", err)
+	}
+	defer ln.Close()
+
+	got := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		r := bufio.NewReader(conn)
+		var lines []string
+		for i := 0; i < 2; i++ {
+			n, err := r.ReadString(' ')
+			if err != nil {
+				return
+			}
+			size := 0
+			for _, c := range strings.TrimSpace(n) {
+				size = size*10 + int(c-'0')
+			}
+			msg := make([]byte, size)
+			if _, err := io.ReadFull(r, msg); err != nil {
+				return
+			}
+			lines = append(lines, string(msg))
+		}
+		got <- strings.Join(lines, "|")
+	}()
+
+	w, err := NewSyslogWriter("tcp", ln.Addr().String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	logger := NewLogger(w, &Options{Format: FormatSyslog})
+	logger.Info("first")
+	logger.Info("second")
+
+	select {
+	case s := <-got:
+		if !strings.HasSuffix(strings.Split(s, "|")[0], " first") || !strings.HasSuffix(s, " second") {
+			t.Errorf("应该按八位组计数分帧接收两条消息: %q", s)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("没有收到 syslog 消息")
+	}
+}
+
+func TestSyslogWriter_UDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("无法监听 UDP: %v", err)
+	}
+	defer pc.Close()
+
+	w, err := NewSyslogWriter("udp", pc.LocalAddr().String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	NewLogger(w, &Options{Format: FormatSyslog}).Error("boom")
+
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	b := make([]byte, 1024)
+	n, _, err := pc.ReadFrom(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg := string(b[:n]); !strings.HasPrefix(msg, "<11>1 ") || strings.HasSuffix(msg, "\n") {
+		t.Errorf("UDP 消息不正确: %q", msg)
+	}
+}
+
+func TestSyslogWriter_ExpiredDeadline(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("无法监听 UDP: %v", err)
+	}
+	defer pc.Close()
+
+	w, err := NewSyslogWriter("udp", pc.LocalAddr().String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// 请求超时后记录的日志不应该因为截止时间已过而被丢弃
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	NewLogger(w, &Options{Format: FormatSyslog, CtxRemaining: slog.LevelError}).ErrorContext(ctx, "request timed out")
+
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	b := make([]byte, 1024)
+	n, _, err := pc.ReadFrom(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg := string(b[:n]); !strings.Contains(msg, "request timed out") || !strings.Contains(msg, "ctx_remaining") {
+		t.Errorf("截止时间已过的记录应该照常发送: %q", msg)
+	}
+}
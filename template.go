@@ -0,0 +1,103 @@
+package slogplus
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// DebugT 使用默认 Logger 记录 Debug 级别的模板消息，参见 LogT
+func DebugT(ctx context.Context, template string, args ...any) {
+	logT(ctx, slog.Default(), slog.LevelDebug, template, args)
+}
+
+// InfoT 使用默认 Logger 记录 Info 级别的模板消息，参见 LogT
+func InfoT(ctx context.Context, template string, args ...any) {
+	logT(ctx, slog.Default(), slog.LevelInfo, template, args)
+}
+
+// WarnT 使用默认 Logger 记录 Warn 级别的模板消息，参见 LogT
+func WarnT(ctx context.Context, template string, args ...any) {
+	logT(ctx, slog.Default(), slog.LevelWarn, template, args)
+}
+
+// ErrorT 使用默认 Logger 记录 Error 级别的模板消息，参见 LogT
+func ErrorT(ctx context.Context, template string, args ...any) {
+	logT(ctx, slog.Default(), slog.LevelError, template, args)
+}
+
+// LogT 记录一条模板消息，模板中的 {name} 替换为同名属性的值，属性仍然保持结构化输出
+//
+//	slogplus.LogT(ctx, logger, slog.LevelInfo, "user {user_id} logged in from {ip}", "user_id", 42, "ip", ip)
+//	// msg="user 42 logged in from 10.0.0.1" user_id=42 ip=10.0.0.1
+//
+// 分组属性使用 {group.key}，{{ 和 }} 输出字面的花括号，找不到属性的占位符原样保留
+// args 的写法与 slog.Logger.Info 相同
+func LogT(ctx context.Context, l *slog.Logger, level slog.Level, template string, args ...any) {
+	logT(ctx, l, level, template, args)
+}
+
+func logT(ctx context.Context, l *slog.Logger, level slog.Level, template string, args []any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !l.Enabled(ctx, level) {
+		return
+	}
+
+	// 借助 slog.Group 将 key/value 参数转换为属性
+	attrs := slog.Group("", args...).Value.Group()
+
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // 跳过 Callers、logT 和导出的包装函数
+	r := slog.NewRecord(time.Now(), level, expandTemplate(template, attrs), pcs[0])
+	r.AddAttrs(attrs...)
+	_ = l.Handler().Handle(ctx, r)
+}
+
+// expandTemplate 将模板中的占位符替换为属性值
+func expandTemplate(template string, attrs []slog.Attr) string {
+	if strings.IndexByte(template, '{') < 0 && strings.IndexByte(template, '}') < 0 {
+		return template
+	}
+
+	var b strings.Builder
+	b.Grow(len(template))
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		if (c == '{' || c == '}') && i+1 < len(template) && template[i+1] == c {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+		if c != '{' {
+			b.WriteByte(c)
+			continue
+		}
+		end := strings.IndexByte(template[i+1:], '}')
+		if end < 0 {
+			b.WriteString(template[i:])
+			break
+		}
+		name := template[i+1 : i+1+end]
+		if v, ok := templateValue(attrs, name); ok {
+			b.WriteString(v.String())
+		} else {
+			b.WriteString(template[i : i+2+end])
+		}
+		i += end + 1
+	}
+	return b.String()
+}
+
+// templateValue 查找占位符对应的属性值，同名时后面的属性优先
+func templateValue(attrs []slog.Attr, name string) (v slog.Value, ok bool) {
+	for _, a := range attrs {
+		if av, found := findAttr(a, name); found {
+			v, ok = av, true
+		}
+	}
+	return v, ok
+}
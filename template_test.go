@@ -0,0 +1,54 @@
+package slogplus
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestExpandTemplate(t *testing.T) {
+	attrs := []slog.Attr{
+		slog.Int("user_id", 42),
+		slog.String("ip", "10.0.0.1"),
+		slog.Group("req", slog.String("path", "/login")),
+	}
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"user {user_id} logged in from {ip}", "user 42 logged in from 10.0.0.1"},
+		{"{req.path} done", "/login done"},
+		{"{missing} stays", "{missing} stays"},
+		{"literal {{braces}}", "literal {braces}"},
+		{"no placeholders", "no placeholders"},
+		{"unterminated {user_id", "unterminated {user_id"},
+	}
+	for _, tt := range tests {
+		if got := expandTemplate(tt.template, attrs); got != tt.want {
+			t.Errorf("expandTemplate(%q) = %q, 期望 %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestInfoT(t *testing.T) {
+	var buf bytes.Buffer
+	old := slog.Default()
+	defer slog.SetDefault(old)
+	Setup(&buf, &Options{AddSource: true})
+
+	InfoT(context.Background(), "user {user_id} logged in from {ip}", "user_id", 42, "ip", "10.0.0.1")
+	DebugT(context.Background(), "filtered {x}", "x", 1)
+
+	out := buf.String()
+	if !strings.Contains(out, "msg=user 42 logged in from 10.0.0.1 user_id=42 ip=10.0.0.1") {
+		t.Errorf("消息应该替换占位符并保留属性: %s", out)
+	}
+	if !strings.Contains(out, "template_test.go:") {
+		t.Errorf("源码位置应该指向调用方: %s", out)
+	}
+	if strings.Contains(out, "filtered") {
+		t.Errorf("未启用的级别不应该输出: %s", out)
+	}
+}
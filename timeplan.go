@@ -0,0 +1,182 @@
+package slogplus
+
+import (
+	"strings"
+	"time"
+)
+
+// timeField 是时间格式中的一个字段
+type timeField uint8
+
+const (
+	tfLiteral   timeField = iota
+	tfYear                // 2006
+	tfYear2               // 06
+	tfMonth               // 01
+	tfMonthName           // Jan
+	tfDay                 // 02
+	tfWeekday             // Mon
+	tfHour                // 15
+	tfHour12              // 03
+	tfPM                  // PM
+	tfMinute              // 04
+	tfSecond              // 05
+	tfMilli               // .000
+	tfMicro               // .000000
+	tfNano                // .000000000
+	tfISOColon            // Z07:00
+	tfISO                 // Z0700
+	tfTZColon             // -07:00
+	tfTZ                  // -0700
+)
+
+// timeTokens 是支持的定宽字段，较长的写法排在前面
+var timeTokens = []struct {
+	s string
+	f timeField
+}{
+	{"2006", tfYear},
+	{"Z07:00", tfISOColon},
+	{"Z0700", tfISO},
+	{"-07:00", tfTZColon},
+	{"-0700", tfTZ},
+	{".000000000", tfNano},
+	{".000000", tfMicro},
+	{".000", tfMilli},
+	{"Jan", tfMonthName},
+	{"Mon", tfWeekday},
+	{"PM", tfPM},
+	{"01", tfMonth},
+	{"02", tfDay},
+	{"03", tfHour12},
+	{"04", tfMinute},
+	{"05", tfSecond},
+	{"06", tfYear2},
+	{"15", tfHour},
+}
+
+// timeStep 是格式化计划中的一步，字面量保存在 lit 中
+type timeStep struct {
+	field timeField
+	lit   string
+}
+
+// timePlan 是预先编译的时间格式，追加时不分配内存
+type timePlan []timeStep
+
+// timePlanSamples 用于校验编译结果的时间，覆盖不同的月份、时区和上下午
+var timePlanSamples = []time.Time{
+	time.Date(2009, time.November, 10, 23, 4, 5, 123456789, time.UTC),
+	time.Date(2021, time.March, 7, 8, 9, 1, 1000, time.FixedZone("IST", 5*3600+1800)),
+	time.Date(1999, time.December, 31, 0, 59, 59, 999000000, time.FixedZone("PST", -8*3600)),
+}
+
+// compileTimePlan 将时间格式编译为格式化计划
+// 格式中含有不支持的字段（例如不补零的 1、_2、January、MST、.999）时返回 nil，由 time.AppendFormat 处理；
+// 编译结果与标准库的输出逐一比对，不一致时同样返回 nil
+func compileTimePlan(layout string) timePlan {
+	var p timePlan
+	lit := 0
+	for i := 0; i < len(layout); {
+		matched := false
+		for _, tok := range timeTokens {
+			if strings.HasPrefix(layout[i:], tok.s) {
+				if lit < i {
+					p = append(p, timeStep{lit: layout[lit:i]})
+				}
+				p = append(p, timeStep{field: tok.f})
+				i += len(tok.s)
+				lit = i
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			i++
+		}
+	}
+	if lit < len(layout) {
+		p = append(p, timeStep{lit: layout[lit:]})
+	}
+
+	for _, t := range timePlanSamples {
+		if string(p.append(nil, t)) != t.Format(layout) {
+			return nil
+		}
+	}
+	return p
+}
+
+// append 按计划追加格式化的时间
+func (p timePlan) append(buf []byte, t time.Time) []byte {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+	for _, s := range p {
+		switch s.field {
+		case tfLiteral:
+			buf = append(buf, s.lit...)
+		case tfYear:
+			buf = appendInt(buf, year, 4)
+		case tfYear2:
+			buf = appendInt(buf, year%100, 2)
+		case tfMonth:
+			buf = appendInt(buf, int(month), 2)
+		case tfMonthName:
+			buf = append(buf, month.String()[:3]...)
+		case tfDay:
+			buf = appendInt(buf, day, 2)
+		case tfWeekday:
+			buf = append(buf, t.Weekday().String()[:3]...)
+		case tfHour:
+			buf = appendInt(buf, hour, 2)
+		case tfHour12:
+			h := hour % 12
+			if h == 0 {
+				h = 12
+			}
+			buf = appendInt(buf, h, 2)
+		case tfPM:
+			if hour >= 12 {
+				buf = append(buf, "PM"...)
+			} else {
+				buf = append(buf, "AM"...)
+			}
+		case tfMinute:
+			buf = appendInt(buf, min, 2)
+		case tfSecond:
+			buf = appendInt(buf, sec, 2)
+		case tfMilli:
+			buf = append(buf, '.')
+			buf = appendInt(buf, t.Nanosecond()/1e6, 3)
+		case tfMicro:
+			buf = append(buf, '.')
+			buf = appendInt(buf, t.Nanosecond()/1e3, 6)
+		case tfNano:
+			buf = append(buf, '.')
+			buf = appendInt(buf, t.Nanosecond(), 9)
+		default:
+			_, offset := t.Zone()
+			buf = appendZoneOffset(buf, offset, s.field)
+		}
+	}
+	return buf
+}
+
+// appendZoneOffset 追加时区偏移，Z07:00 和 Z0700 在 UTC 时输出 Z
+func appendZoneOffset(buf []byte, offset int, f timeField) []byte {
+	if offset == 0 && (f == tfISOColon || f == tfISO) {
+		return append(buf, 'Z')
+	}
+	offset /= 60
+	if offset < 0 {
+		buf = append(buf, '-')
+		offset = -offset
+	} else {
+		buf = append(buf, '+')
+	}
+	buf = appendInt(buf, offset/60, 2)
+	if f == tfISOColon || f == tfTZColon {
+		buf = append(buf, ':')
+	}
+	return appendInt(buf, offset%60, 2)
+}
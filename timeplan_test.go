@@ -0,0 +1,62 @@
+package slogplus
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestCompileTimePlan(t *testing.T) {
+	times := []time.Time{
+		time.Date(2025, time.January, 2, 3, 4, 5, 6007008, time.UTC),
+		time.Date(2025, time.July, 20, 12, 30, 0, 0, time.FixedZone("", -(3*3600+1800))),
+		time.Date(2024, time.February, 29, 0, 0, 0, 999999999, time.FixedZone("CST", 8*3600)),
+	}
+	for _, layout := range []string{
+		"2006/01/02 15:04:05",
+		time.RFC3339,
+		"2006-01-02T15:04:05.000Z07:00",
+		"2006-01-02 15:04:05.000000 -0700",
+		time.DateTime,
+		"Mon Jan 02 03:04:05 PM -07:00 06",
+		"-",
+	} {
+		p := compileTimePlan(layout)
+		if p == nil {
+			t.Errorf("%q 应该可以编译", layout)
+			continue
+		}
+		for _, tm := range times {
+			if got, want := string(p.append(nil, tm)), tm.Format(layout); got != want {
+				t.Errorf("%q: 输出 %q, 期望 %q", layout, got, want)
+			}
+		}
+	}
+
+	// 不补零的字段、全称和时区名不支持，回退到 time.AppendFormat
+	for _, layout := range []string{time.Kitchen, time.RFC1123, time.StampNano, "January 2, 2006", "15:04:05.999"} {
+		if compileTimePlan(layout) != nil {
+			t.Errorf("%q 不应该被编译", layout)
+		}
+	}
+}
+
+func TestHandler_TimeFormatFallback(t *testing.T) {
+	var buf bytes.Buffer
+	h := New(&buf, &Options{TimeFormat: time.Kitchen})
+	tm := time.Date(2025, time.January, 2, 15, 4, 5, 0, time.UTC)
+	if got := string(h.appendTime(nil, tm)); got != "3:04PM" {
+		t.Errorf("不支持编译的格式应该回退到标准库: %q", got)
+	}
+}
+
+func BenchmarkHandler_RFC3339Millis(b *testing.B) {
+	logger := NewLogger(io.Discard, &Options{TimeFormat: "2006-01-02T15:04:05.000Z07:00"})
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		logger.Info("test message", "key1", "value1")
+	}
+}
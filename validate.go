@@ -0,0 +1,126 @@
+package slogplus
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// layoutProbes 是检查时间格式用的两个各字段都不同的时间，格式化结果相同说明布局中没有任何时间字段
+var layoutProbes = [2]time.Time{
+	time.Date(2006, 1, 2, 15, 4, 5, 123456789, time.FixedZone("MST", -7*3600)),
+	time.Date(2017, 11, 23, 8, 39, 47, 987654321, time.FixedZone("CET", 3600)),
+}
+
+// foreignTimeTokens 是 strftime 和 Java 风格时间格式中的常见写法
+var foreignTimeTokens = []string{"%Y", "%m", "%d", "%H", "%M", "%S", "yyyy", "YYYY", "HH", "mm", "ss", "dd"}
+
+// Validate 检查配置中无法生效的取值和组合，返回的错误列出所有问题
+// New 将无效的取值按默认值处理，NewE 在配置无效时返回该错误
+func (o *Options) Validate() error {
+	if o == nil {
+		return nil
+	}
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("slogplus: Options."+format, args...))
+	}
+
+	if !slices.Contains(formats, o.Format) {
+		invalid("Format 未知的日志格式 %q", string(o.Format))
+	}
+	if err := checkTimeFormat(o.TimeFormat); err != nil {
+		invalid("TimeFormat %v", err)
+	}
+	switch o.SourceFormat {
+	case SourceFull, SourcePackage, SourceFile, SourceModule:
+	default:
+		invalid("SourceFormat 未知的源码路径形式 %q", string(o.SourceFormat))
+	}
+	if o.CallerSkip < 0 {
+		invalid("CallerSkip 不能为负数: %d", o.CallerSkip)
+	}
+	if o.MaxValueLen < 0 {
+		invalid("MaxValueLen 不能为负数: %d", o.MaxValueLen)
+	}
+	if o.MaxBufferSize < 0 {
+		invalid("MaxBufferSize 不能为负数: %d", o.MaxBufferSize)
+	}
+	if o.WriteTimeout < 0 {
+		invalid("WriteTimeout 不能为负数: %s", o.WriteTimeout)
+	}
+	if o.Logfmt && o.FormatVar == nil && o.Format != FormatText {
+		invalid("Logfmt 只对文本格式生效，当前格式为 %s", o.Format)
+	}
+	for l, name := range o.LevelNames {
+		if name == "" {
+			invalid("LevelNames 中 %s 的名称为空", l.Level())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// normalize 将 Validate 报告的无效取值替换为默认值，供 New 使用
+func (o *Options) normalize() {
+	if !slices.Contains(formats, o.Format) {
+		o.Format = FormatText
+	}
+	if checkTimeFormat(o.TimeFormat) != nil {
+		o.TimeFormat = ""
+	}
+	switch o.SourceFormat {
+	case SourceFull, SourcePackage, SourceFile, SourceModule:
+	default:
+		o.SourceFormat = SourceFull
+	}
+	o.CallerSkip = max(o.CallerSkip, 0)
+	o.MaxValueLen = max(o.MaxValueLen, 0)
+	o.MaxBufferSize = max(o.MaxBufferSize, 0)
+	o.WriteTimeout = max(o.WriteTimeout, 0)
+	if o.Logfmt && o.FormatVar == nil && o.Format != FormatText {
+		o.Logfmt = false
+	}
+	for _, name := range o.LevelNames {
+		if name == "" {
+			// 不修改调用方的 map
+			o.LevelNames = maps.Clone(o.LevelNames)
+			maps.DeleteFunc(o.LevelNames, func(_ slog.Leveler, name string) bool { return name == "" })
+			break
+		}
+	}
+}
+
+// checkTimeFormat 检查时间布局是否误用了 strftime 或 Java 风格的写法，例如 "yyyy-MM-dd" 或 "%Y-%m-%d"
+// 不包含任何时间字段的固定文本（例如测试中常用的 "-"）是有效的
+func checkTimeFormat(layout string) error {
+	if layoutProbes[0].Format(layout) != layoutProbes[1].Format(layout) {
+		return nil
+	}
+	for _, tok := range foreignTimeTokens {
+		if strings.Contains(layout, tok) {
+			return fmt.Errorf("%q 不包含任何时间字段，应该使用 Go 的时间布局，例如 %q", layout, time.DateTime)
+		}
+	}
+	return nil
+}
+
+// Validate 检查队列配置，返回的错误列出所有问题；Async 将负数按默认值处理，AsyncE 在配置无效时返回该错误
+func (o *AsyncOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+	var errs []error
+	for _, f := range []struct {
+		name string
+		v    int
+	}{{"QueueSize", o.QueueSize}, {"Workers", o.Workers}, {"MaxBatch", o.MaxBatch}} {
+		if f.v < 0 {
+			errs = append(errs, fmt.Errorf("slogplus: AsyncOptions.%s 不能为负数: %d", f.name, f.v))
+		}
+	}
+	return errors.Join(errs...)
+}
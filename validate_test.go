@@ -0,0 +1,102 @@
+package slogplus
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOptions_Validate(t *testing.T) {
+	valid := []*Options{
+		nil,
+		{},
+		{TimeFormat: "-"}, // 固定文本，测试中常用
+		{TimeFormat: time.RFC3339Nano, Format: FormatJSON, SourceFormat: SourceModule},
+		{Logfmt: true, FormatVar: NewFormatVar(FormatJSON)},
+	}
+	for _, opts := range valid {
+		if err := opts.Validate(); err != nil {
+			t.Errorf("%+v 应该有效: %v", opts, err)
+		}
+	}
+
+	opts := &Options{
+		Format:        "yaml",
+		TimeFormat:    "yyyy-MM-dd HH:mm:ss",
+		SourceFormat:  "short",
+		CallerSkip:    -1,
+		MaxValueLen:   -8,
+		MaxBufferSize: -1,
+		WriteTimeout:  -time.Second,
+		LevelNames:    map[slog.Leveler]string{slog.LevelInfo + 2: ""},
+	}
+	err := opts.Validate()
+	if err == nil {
+		t.Fatal("无效的配置应该返回错误")
+	}
+	for _, want := range []string{"Options.Format", `"yyyy-MM-dd HH:mm:ss"`, "Options.SourceFormat", "CallerSkip", "MaxValueLen", "MaxBufferSize", "WriteTimeout", "LevelNames"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("错误应该包含 %s: %v", want, err)
+		}
+	}
+
+	if err := (&Options{Logfmt: true, Format: FormatJSON}).Validate(); err == nil || !strings.Contains(err.Error(), "Logfmt") {
+		t.Errorf("Logfmt 与 JSON 格式的组合应该返回错误: %v", err)
+	}
+	for _, layout := range []string{"%Y-%m-%dT%H:%M:%S", "yyyy/MM/dd"} {
+		if err := checkTimeFormat(layout); err == nil {
+			t.Errorf("%q 应该返回错误", layout)
+		}
+	}
+}
+
+func TestNew_InvalidOptions(t *testing.T) {
+	names := map[slog.Leveler]string{slog.LevelInfo: "", slog.LevelWarn: "WARNING"}
+	opts := &Options{Format: "yaml", TimeFormat: "yyyy-MM-dd", MaxBufferSize: -1, LevelNames: names}
+	if _, err := NewE(nil, opts); err == nil || !strings.Contains(err.Error(), "MaxBufferSize") {
+		t.Errorf("NewE 应该返回配置错误: %v", err)
+	}
+
+	// New 按默认值处理无效的配置项
+	var buf bytes.Buffer
+	logger := slog.New(New(&buf, opts))
+	logger.Info("a")
+	logger.Warn("b")
+	if got := buf.String(); !strings.Contains(got, " INFO msg=a\n") || !strings.Contains(got, " WARNING msg=b\n") || strings.Contains(got, "yyyy") {
+		t.Errorf("无效的配置项应该按默认值输出: %q", got)
+	}
+	if len(names) != 2 {
+		t.Error("不应该修改调用方的 LevelNames")
+	}
+
+	if h, err := NewE(nil, WithFormat(FormatJSON)); err != nil || h.opts.Format != FormatJSON {
+		t.Errorf("有效的配置不应该返回错误: %v", err)
+	}
+}
+
+func TestAsyncOptions_Validate(t *testing.T) {
+	if err := (&AsyncOptions{QueueSize: 8}).Validate(); err != nil {
+		t.Error(err)
+	}
+	opts := &AsyncOptions{QueueSize: -1, Workers: -2, MaxBatch: -3}
+	err := opts.Validate()
+	for _, want := range []string{"QueueSize", "Workers", "MaxBatch"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("错误应该包含 %s: %v", want, err)
+		}
+	}
+	if _, err := AsyncE(New(nil), opts); err == nil {
+		t.Error("AsyncE 应该返回配置错误")
+	}
+
+	// Async 按默认值处理
+	var buf syncBuffer
+	ah := Async(New(&buf, &Options{TimeFormat: "-"}), opts)
+	slog.New(ah).Info("x")
+	ah.Close()
+	if buf.String() != "- INFO msg=x\n" {
+		t.Errorf("无效的队列配置应该按默认值处理: %q", buf.String())
+	}
+}
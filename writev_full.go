@@ -0,0 +1,19 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"io"
+	"net"
+)
+
+// writeVectored 输出为网络连接时使用 net.Buffers 一次系统调用写入多个 buffer，返回 false 表示不支持
+func writeVectored(w io.Writer, bufs [][]byte) (bool, error) {
+	if _, ok := w.(net.Conn); !ok {
+		return false, nil
+	}
+	// WriteTo 会修改切片中的元素，复制一份以免影响调用方归还 buffer
+	nb := append(net.Buffers(nil), bufs...)
+	_, err := nb.WriteTo(w)
+	return true, err
+}
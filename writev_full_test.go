@@ -0,0 +1,37 @@
+//go:build !slogplus_slim && !tinygo
+
+package slogplus
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestWriteVectored(t *testing.T) {
+	if ok, _ := writeVectored(&bytes.Buffer{}, [][]byte{[]byte("a")}); ok {
+		t.Error("非网络连接不应该使用 writev")
+	}
+
+	c1, c2 := net.Pipe()
+	defer c2.Close()
+	done := make(chan []byte)
+	go func() {
+		b, _ := io.ReadAll(c2)
+		done <- b
+	}()
+
+	bufs := [][]byte{[]byte("line1\n"), []byte("line2\n")}
+	ok, err := writeVectored(c1, bufs)
+	c1.Close()
+	if !ok || err != nil {
+		t.Fatalf("网络连接应该使用 writev: %v %v", ok, err)
+	}
+	if got := string(<-done); got != "line1\nline2\n" {
+		t.Errorf("写入内容错误: %q", got)
+	}
+	if string(bufs[0]) != "line1\n" {
+		t.Error("不应该修改调用方的切片")
+	}
+}
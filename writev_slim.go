@@ -0,0 +1,10 @@
+//go:build slogplus_slim || tinygo
+
+package slogplus
+
+import "io"
+
+// writeVectored 精简构建不包含 net 包，总是返回 false，由调用方拼接后写入
+func writeVectored(w io.Writer, bufs [][]byte) (bool, error) {
+	return false, nil
+}